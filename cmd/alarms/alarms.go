@@ -15,6 +15,7 @@ var Cmd = &cobra.Command{
 
 func init() {
 	Cmd.AddCommand(notificationchannel.Cmd)
+	Cmd.AddCommand(listenCmd)
 }
 
 func run(cmd *cobra.Command, args []string) {
@@ -22,4 +23,4 @@ func run(cmd *cobra.Command, args []string) {
 	if err != nil {
 		return
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,305 @@
+package alarms
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/spf13/cobra"
+)
+
+const maxEventBodySize = 10 << 20
+
+var listenCmd = &cobra.Command{
+	Use:   "listen [flags]",
+	Short: "Run a local webhook receiver to inspect notification events",
+	Long: `Run a local HTTP receiver that prints every notification event it receives, to debug webhook integrations.
+
+Each request body is validated against the schema of the events Omnistrate delivers to webhook channels (eventID,
+eventType, eventCategory, alertType, priority, scope, eventPayload, time and expiryTime are required) and
+pretty-printed. Omnistrate doesn't sign webhook deliveries, but sends the headers configured on the webhook channel.
+With --header, requests without the given header values, such as a token set in the channel's headers, are rejected
+with 401.
+
+--status-code sets the status codes to respond with, in order, repeating the last one. This makes it possible to
+test retry behavior, e.g. 503,503,200 fails the first two deliveries and accepts the rest.
+
+Point a webhook notification channel at the receiver, e.g. through a tunnel, and resend historical events with
+'omctl alarms notification-channel replay-event'.`,
+	Example: `# Print events sent to http://localhost:8080
+omctl alarms listen --port 8080
+
+# Record events to a file and only accept the ones with the token configured in the channel's headers
+omctl alarms listen --port 8080 --record events.ndjson --header X-Webhook-Token=my-webhook-token
+
+# Fail the first two deliveries to test retries
+omctl alarms listen --port 8080 --status-code 503,503,200`,
+	Args:         cobra.NoArgs,
+	RunE:         runListen,
+	SilenceUsage: true,
+}
+
+func init() {
+	listenCmd.Flags().Int("port", 8080, "Port to listen on")
+	listenCmd.Flags().String("host", "127.0.0.1", "Address to listen on. Use 0.0.0.0 to accept connections from other machines")
+	listenCmd.Flags().IntSlice("status-code", []int{http.StatusOK}, "Status codes to respond with, in order, repeating the last one, e.g. 503,503,200")
+	listenCmd.Flags().String("record", "", "Append received events to this NDJSON file")
+	listenCmd.Flags().StringToString("header", map[string]string{}, "Header values requests must have, as configured in the webhook channel's headers, e.g. X-Webhook-Token=my-webhook-token. Can be repeated")
+	listenCmd.Flags().Bool("strict", false, "Respond with 400 to requests that aren't valid notification events")
+}
+
+// receivedEvent is a request received by the listener, as printed and recorded.
+type receivedEvent struct {
+	Sequence          int                 `json:"sequence"`
+	ReceivedAt        string              `json:"receivedAt"`
+	Method            string              `json:"method"`
+	Path              string              `json:"path"`
+	Headers           map[string][]string `json:"headers"`
+	Body              json.RawMessage     `json:"body,omitempty"`
+	RawBody           string              `json:"rawBody,omitempty"`
+	Valid             bool                `json:"valid"`
+	ValidationError   string              `json:"validationError,omitempty"`
+	HeadersValid      *bool               `json:"headersValid,omitempty"`
+	MismatchedHeaders []string            `json:"mismatchedHeaders,omitempty"`
+	ResponseStatus    int                 `json:"responseStatus"`
+}
+
+// redactedHeaderValue replaces the values of the headers holding credentials when events are printed and recorded.
+const redactedHeaderValue = "[REDACTED]"
+
+// eventReceiver is the HTTP handler of the listener.
+type eventReceiver struct {
+	statusCodes     []int
+	requiredHeaders map[string]string
+	strict          bool
+	out             io.Writer
+	record          io.Writer
+	now             func() time.Time
+
+	mu       sync.Mutex
+	received int
+}
+
+func runListen(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	host, _ := cmd.Flags().GetString("host")
+	statusCodes, _ := cmd.Flags().GetIntSlice("status-code")
+	recordPath, _ := cmd.Flags().GetString("record")
+	requiredHeaders, _ := cmd.Flags().GetStringToString("header")
+	strict, _ := cmd.Flags().GetBool("strict")
+
+	if len(statusCodes) == 0 {
+		return errors.New("at least one --status-code is required")
+	}
+	for _, code := range statusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid status code %d", code)
+		}
+	}
+
+	receiver := &eventReceiver{
+		statusCodes:     statusCodes,
+		requiredHeaders: requiredHeaders,
+		strict:          strict,
+		out:             cmd.OutOrStdout(),
+		now:             time.Now,
+	}
+
+	if recordPath != "" {
+		file, err := os.OpenFile(recordPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open record file: %w", err)
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		receiver.record = file
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s:%d: %w", host, port, err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Handler: receiver, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(receiver.out, "Listening for notification events on http://%s (Ctrl+C to stop)\n", listener.Addr())
+	if err = server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(receiver.out, "Stopped after receiving %d requests\n", receiver.receivedCount())
+	return nil
+}
+
+func (r *eventReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxEventBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.received++
+	event := receivedEvent{
+		Sequence:   r.received,
+		ReceivedAt: r.now().UTC().Format(time.RFC3339Nano),
+		Method:     req.Method,
+		Path:       req.URL.RequestURI(),
+		Headers:    redactHeaders(req.Header, r.requiredHeaders),
+	}
+	if json.Valid(body) {
+		event.Body = body
+	} else {
+		event.RawBody = string(body)
+	}
+
+	if err = validateNotificationEvent(body); err != nil {
+		event.ValidationError = err.Error()
+	} else {
+		event.Valid = true
+	}
+
+	if len(r.requiredHeaders) > 0 {
+		event.MismatchedHeaders = mismatchedHeaders(req.Header, r.requiredHeaders)
+		valid := len(event.MismatchedHeaders) == 0
+		event.HeadersValid = &valid
+	}
+
+	switch {
+	case event.HeadersValid != nil && !*event.HeadersValid:
+		event.ResponseStatus = http.StatusUnauthorized
+	case r.strict && !event.Valid:
+		event.ResponseStatus = http.StatusBadRequest
+	default:
+		event.ResponseStatus = r.statusCodes[min(r.received, len(r.statusCodes))-1]
+	}
+
+	r.print(event)
+	if r.record != nil {
+		if data, err := json.Marshal(event); err == nil {
+			_, _ = r.record.Write(append(data, '\n'))
+		}
+	}
+
+	w.WriteHeader(event.ResponseStatus)
+}
+
+func (r *eventReceiver) receivedCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.received
+}
+
+func (r *eventReceiver) print(event receivedEvent) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#%d %s %s %s -> %d\n", event.Sequence, event.ReceivedAt, event.Method, event.Path, event.ResponseStatus)
+	if event.Valid {
+		b.WriteString("  schema:    valid notification event\n")
+	} else {
+		fmt.Fprintf(&b, "  schema:    invalid notification event: %s\n", event.ValidationError)
+	}
+	if event.HeadersValid != nil {
+		if *event.HeadersValid {
+			b.WriteString("  headers:   valid\n")
+		} else {
+			fmt.Fprintf(&b, "  headers:   missing or different %s\n", strings.Join(event.MismatchedHeaders, ", "))
+		}
+	}
+
+	if event.Body != nil {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, event.Body, "  ", "  "); err == nil {
+			fmt.Fprintf(&b, "  %s\n", indented.String())
+		}
+	} else if event.RawBody != "" {
+		fmt.Fprintf(&b, "  %s\n", event.RawBody)
+	}
+
+	_, _ = io.WriteString(r.out, b.String()+"\n")
+}
+
+// validateNotificationEvent checks that the body decodes as an event delivered by Omnistrate, with all required
+// properties. Webhook channels can add their own body parameters, so unknown properties are allowed.
+func validateNotificationEvent(body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return errors.New("empty body")
+	}
+
+	var event openapiclientfleet.ServiceProviderEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return err
+	}
+	if event.EventID == "" {
+		return errors.New("eventID is empty")
+	}
+	if event.EventType == "" {
+		return errors.New("eventType is empty")
+	}
+	if _, err := time.Parse(time.RFC3339, event.Time); err != nil {
+		return fmt.Errorf("time %q isn't an RFC 3339 timestamp", event.Time)
+	}
+	return nil
+}
+
+// redactHeaders returns a copy of the request headers with the values of Authorization and of the required headers,
+// such as a webhook token, redacted.
+func redactHeaders(header http.Header, required map[string]string) http.Header {
+	redacted := header.Clone()
+	if redacted == nil {
+		return http.Header{}
+	}
+	for name := range redacted {
+		if name == "Authorization" || name == "Proxy-Authorization" || hasHeader(required, name) {
+			redacted[name] = []string{redactedHeaderValue}
+		}
+	}
+	return redacted
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if http.CanonicalHeaderKey(key) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mismatchedHeaders returns the names of the required headers the request doesn't have with the required value.
+func mismatchedHeaders(header http.Header, required map[string]string) []string {
+	mismatched := make([]string, 0)
+	for name, value := range required {
+		if subtle.ConstantTimeCompare([]byte(header.Get(name)), []byte(value)) != 1 {
+			mismatched = append(mismatched, http.CanonicalHeaderKey(name))
+		}
+	}
+	sort.Strings(mismatched)
+	return mismatched
+}
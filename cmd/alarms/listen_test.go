@@ -0,0 +1,102 @@
+package alarms
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testEventBody = `{"eventID":"e-123","eventType":"FailedDeployment","eventCategory":"InstanceEvent","alertType":"Alarm","priority":"High","scope":"ServiceProvider","instanceID":"instance-123","eventPayload":{"message":"deployment failed"},"time":"2026-01-02T03:04:05Z","expiryTime":"2026-01-09T03:04:05Z"}`
+
+func newTestReceiver(statusCodes []int) (*eventReceiver, *bytes.Buffer, *bytes.Buffer) {
+	var out, record bytes.Buffer
+	return &eventReceiver{
+		statusCodes: statusCodes,
+		out:         &out,
+		record:      &record,
+		now:         func() time.Time { return time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC) },
+	}, &out, &record
+}
+
+func postEvent(receiver http.Handler, body string, header http.Header) int {
+	req := httptest.NewRequest(http.MethodPost, "/hooks/omnistrate", strings.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func TestEventReceiverStatusCodes(t *testing.T) {
+	require := require.New(t)
+
+	receiver, out, record := newTestReceiver([]int{503, 500, 200})
+	var codes []int
+	for range 4 {
+		codes = append(codes, postEvent(receiver, testEventBody, nil))
+	}
+	require.Equal([]int{503, 500, 200, 200}, codes)
+	require.Contains(out.String(), "#1 2026-01-02T03:04:06Z POST /hooks/omnistrate -> 503")
+	require.Contains(out.String(), "schema:    valid notification event")
+	require.Contains(out.String(), `"eventType": "FailedDeployment"`)
+
+	lines := strings.Split(strings.TrimSpace(record.String()), "\n")
+	require.Len(lines, 4)
+	var recorded receivedEvent
+	require.NoError(json.Unmarshal([]byte(lines[3]), &recorded))
+	require.Equal(4, recorded.Sequence)
+	require.True(recorded.Valid)
+	require.Equal(200, recorded.ResponseStatus)
+	require.JSONEq(testEventBody, string(recorded.Body))
+}
+
+func TestEventReceiverValidation(t *testing.T) {
+	require := require.New(t)
+
+	receiver, out, record := newTestReceiver([]int{200})
+	require.Equal(200, postEvent(receiver, `{"id":"event-123","publicationStatus":"SUCCESS","timestamp":"2026-01-02T03:04:05Z"}`, nil))
+	require.Contains(out.String(), "invalid notification event: no value given for required property alertType")
+
+	require.Equal(200, postEvent(receiver, strings.Replace(testEventBody, "2026-01-02T03:04:05Z", "yesterday", 1), nil))
+	require.Contains(out.String(), `invalid notification event: time "yesterday" isn't an RFC 3339 timestamp`)
+
+	// Body parameters added by the webhook channel are allowed
+	require.Equal(200, postEvent(receiver, strings.Replace(testEventBody, "{", `{"team":"sre",`, 1), nil))
+	require.Contains(out.String(), "#3 2026-01-02T03:04:06Z POST /hooks/omnistrate -> 200\n  schema:    valid notification event")
+
+	receiver.strict = true
+	require.Equal(400, postEvent(receiver, "not json", nil))
+	require.Contains(out.String(), "  not json\n")
+	require.Contains(record.String(), `"rawBody":"not json"`)
+}
+
+func TestEventReceiverHeaders(t *testing.T) {
+	require := require.New(t)
+
+	receiver, out, record := newTestReceiver([]int{200})
+	receiver.requiredHeaders = map[string]string{"x-webhook-token": "s3cret", "X-Team": "sre"}
+
+	require.Equal(200, postEvent(receiver, testEventBody, http.Header{"X-Webhook-Token": {"s3cret"}, "X-Team": {"sre"}, "Authorization": {"Bearer t0ken"}, "User-Agent": {"omnistrate"}}))
+	require.Contains(out.String(), "headers:   valid")
+
+	// Credentials aren't recorded
+	require.NotContains(record.String(), "s3cret")
+	require.NotContains(record.String(), "t0ken")
+	var recorded receivedEvent
+	require.NoError(json.Unmarshal(record.Bytes(), &recorded))
+	require.Equal([]string{redactedHeaderValue}, recorded.Headers["X-Webhook-Token"])
+	require.Equal([]string{redactedHeaderValue}, recorded.Headers["Authorization"])
+	require.Equal([]string{"omnistrate"}, recorded.Headers["User-Agent"])
+
+	require.Equal(401, postEvent(receiver, testEventBody, http.Header{"X-Webhook-Token": {"wrong"}, "X-Team": {"sre"}}))
+	require.Contains(out.String(), "headers:   missing or different X-Webhook-Token\n")
+	require.Equal(401, postEvent(receiver, testEventBody, nil))
+	require.Contains(out.String(), "headers:   missing or different X-Team, X-Webhook-Token\n")
+}
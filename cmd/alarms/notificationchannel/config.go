@@ -0,0 +1,250 @@
+package notificationchannel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"gopkg.in/yaml.v3"
+)
+
+// channelConfig is the YAML representation of a notification channel accepted by create and update.
+// Exactly one destination (email, slack, webhook or pagerDuty) is expected.
+type channelConfig struct {
+	Name         string                     `yaml:"name"`
+	Email        *emailConfig               `yaml:"email,omitempty"`
+	Slack        *slackConfig               `yaml:"slack,omitempty"`
+	Webhook      *webhookConfig             `yaml:"webhook,omitempty"`
+	PagerDuty    *pagerDutyConfig           `yaml:"pagerDuty,omitempty"`
+	Subscription *channelSubscriptionConfig `yaml:"subscription,omitempty"`
+}
+
+type emailConfig struct {
+	To string `yaml:"to"`
+}
+
+type slackConfig struct {
+	WebhookURL string `yaml:"webhookUrl"`
+}
+
+type webhookConfig struct {
+	URL                      string                 `yaml:"url"`
+	Method                   string                 `yaml:"method,omitempty"`
+	Headers                  map[string]string      `yaml:"headers,omitempty"`
+	AdditionalBodyParameters map[string]interface{} `yaml:"additionalBodyParameters,omitempty"`
+}
+
+type pagerDutyConfig struct {
+	IntegrationKey string `yaml:"integrationKey"`
+}
+
+type channelSubscriptionConfig struct {
+	EventCategories  []string `yaml:"eventCategories"`
+	EventPriorities  []string `yaml:"eventPriorities"`
+	AlertTypes       []string `yaml:"alertTypes,omitempty"`
+	EventTypes       []string `yaml:"eventTypes,omitempty"`
+	EnvironmentTypes []string `yaml:"environmentTypes,omitempty"`
+}
+
+var webhookMethods = []string{"POST", "PUT", "PATCH", "GET"}
+
+const exampleChannelConfig = `name: ops-webhook
+webhook:
+  url: https://hooks.example.com/omnistrate
+  method: POST
+  headers:
+    Authorization: Bearer my-token
+subscription:
+  eventCategories: [Infra, Maintenance]
+  eventPriorities: [Critical, High]
+  alertTypes: [InstanceFailed]`
+
+func loadChannelConfig(path string) (*channelConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read channel config file: %w", err)
+	}
+
+	return parseChannelConfig(data)
+}
+
+func parseChannelConfig(data []byte) (*channelConfig, error) {
+	var config channelConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid channel config: %w", err)
+	}
+
+	config.Name = strings.TrimSpace(config.Name)
+	if config.Webhook != nil {
+		config.Webhook.Method = strings.ToUpper(strings.TrimSpace(config.Webhook.Method))
+		if config.Webhook.Method == "" {
+			config.Webhook.Method = "POST"
+		}
+	}
+
+	return &config, nil
+}
+
+// destinations returns the names of the destinations set in the config.
+func (c *channelConfig) destinations() (destinations []string) {
+	if c.Email != nil {
+		destinations = append(destinations, "email")
+	}
+	if c.Slack != nil {
+		destinations = append(destinations, "slack")
+	}
+	if c.Webhook != nil {
+		destinations = append(destinations, "webhook")
+	}
+	if c.PagerDuty != nil {
+		destinations = append(destinations, "pagerDuty")
+	}
+	return
+}
+
+// validateForCreate checks that the config describes a complete channel.
+func (c *channelConfig) validateForCreate() error {
+	if c.Name == "" {
+		return fmt.Errorf("channel name is required")
+	}
+	if len(c.destinations()) == 0 {
+		return fmt.Errorf("one of email, slack, webhook or pagerDuty must be configured")
+	}
+	if c.Subscription == nil {
+		return fmt.Errorf("subscription is required")
+	}
+	return c.validate()
+}
+
+// validateForUpdate checks the fields present in the config. Fields left out are not changed.
+func (c *channelConfig) validateForUpdate() error {
+	if c.Name == "" && len(c.destinations()) == 0 && c.Subscription == nil {
+		return fmt.Errorf("nothing to update: set name, a destination or subscription")
+	}
+	return c.validate()
+}
+
+func (c *channelConfig) validate() error {
+	if destinations := c.destinations(); len(destinations) > 1 {
+		return fmt.Errorf("a channel has a single destination, got %s", strings.Join(destinations, ", "))
+	}
+
+	if c.Email != nil {
+		if _, err := mail.ParseAddress(c.Email.To); err != nil {
+			return fmt.Errorf("invalid email.to address %q: %w", c.Email.To, err)
+		}
+	}
+	if c.Slack != nil {
+		if err := validateChannelURL("slack.webhookUrl", c.Slack.WebhookURL); err != nil {
+			return err
+		}
+	}
+	if c.Webhook != nil {
+		if err := validateChannelURL("webhook.url", c.Webhook.URL); err != nil {
+			return err
+		}
+		if !slices.Contains(webhookMethods, c.Webhook.Method) {
+			return fmt.Errorf("unsupported webhook.method %s, expected one of %s", c.Webhook.Method, strings.Join(webhookMethods, ", "))
+		}
+	}
+	if c.PagerDuty != nil && strings.TrimSpace(c.PagerDuty.IntegrationKey) == "" {
+		return fmt.Errorf("pagerDuty.integrationKey is required")
+	}
+
+	if c.Subscription != nil {
+		if len(c.Subscription.EventCategories) == 0 {
+			return fmt.Errorf("subscription.eventCategories must list at least one category")
+		}
+		if len(c.Subscription.EventPriorities) == 0 {
+			return fmt.Errorf("subscription.eventPriorities must list at least one priority")
+		}
+	}
+
+	return nil
+}
+
+func validateChannelURL(field, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", field, err)
+	}
+	if (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("invalid %s %q: expected an http or https URL", field, value)
+	}
+	return nil
+}
+
+func (c *channelConfig) toCreateRequest() openapiclientfleet.CreateNotificationChannelRequest2 {
+	request := openapiclientfleet.NewCreateNotificationChannelRequest2(c.Name, c.Subscription.toChannelSubscription())
+	request.Email, request.Slack, request.Webhook, request.PagerDuty = c.toDestinations()
+	return *request
+}
+
+func (c *channelConfig) toUpdateRequest() openapiclientfleet.UpdateNotificationChannelRequest2 {
+	request := openapiclientfleet.NewUpdateNotificationChannelRequest2()
+	if c.Name != "" {
+		request.SetName(c.Name)
+	}
+	if c.Subscription != nil {
+		request.SetSubscription(c.Subscription.toChannelSubscription())
+	}
+	request.Email, request.Slack, request.Webhook, request.PagerDuty = c.toDestinations()
+	return *request
+}
+
+func (c *channelConfig) toDestinations() (
+	email *openapiclientfleet.EmailConfiguration,
+	slack *openapiclientfleet.SlackConfiguration,
+	webhook *openapiclientfleet.WebhookConfiguration,
+	pagerDuty *openapiclientfleet.PagerDutyConfiguration,
+) {
+	if c.Email != nil {
+		email = openapiclientfleet.NewEmailConfiguration(c.Email.To)
+	}
+	if c.Slack != nil {
+		slack = openapiclientfleet.NewSlackConfiguration(c.Slack.WebhookURL)
+	}
+	if c.Webhook != nil {
+		webhook = openapiclientfleet.NewWebhookConfiguration(c.Webhook.Method, c.Webhook.URL)
+		if len(c.Webhook.Headers) > 0 {
+			webhook.SetHeaders(c.Webhook.Headers)
+		}
+		if len(c.Webhook.AdditionalBodyParameters) > 0 {
+			webhook.SetAdditionalBodyParameters(c.Webhook.AdditionalBodyParameters)
+		}
+	}
+	if c.PagerDuty != nil {
+		pagerDuty = openapiclientfleet.NewPagerDutyConfiguration(c.PagerDuty.IntegrationKey)
+	}
+	return
+}
+
+func (s *channelSubscriptionConfig) toChannelSubscription() openapiclientfleet.ChannelSubscription {
+	return *openapiclientfleet.NewChannelSubscription(
+		nonNilStrings(s.AlertTypes),
+		nonNilStrings(s.EnvironmentTypes),
+		s.EventCategories,
+		s.EventPriorities,
+		nonNilStrings(s.EventTypes),
+	)
+}
+
+// nonNilStrings keeps optional lists serialized as [] rather than null, since the API declares them as required.
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
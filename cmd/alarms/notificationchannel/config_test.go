@@ -0,0 +1,125 @@
+package notificationchannel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChannelConfig(t *testing.T) {
+	require := require.New(t)
+
+	config, err := parseChannelConfig([]byte(exampleChannelConfig))
+	require.NoError(err)
+	require.NoError(config.validateForCreate())
+
+	request := config.toCreateRequest()
+	require.Equal("ops-webhook", request.Name)
+	require.NotNil(request.Webhook)
+	require.Equal("POST", request.Webhook.GetMethod())
+	require.Equal("https://hooks.example.com/omnistrate", request.Webhook.GetUrl())
+	require.Equal(map[string]string{"Authorization": "Bearer my-token"}, request.Webhook.GetHeaders())
+	require.Nil(request.Slack)
+	require.Equal([]string{"Infra", "Maintenance"}, request.Subscription.EventCategories)
+	require.Equal([]string{"InstanceFailed"}, request.Subscription.AlertTypes)
+
+	data, err := json.Marshal(request)
+	require.NoError(err)
+	require.Contains(string(data), `"eventTypes":[]`)
+	require.Contains(string(data), `"environmentTypes":[]`)
+}
+
+func TestChannelConfigValidation(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           string
+		forUpdate        bool
+		expectedErrorMsg string
+	}{
+		{
+			name:             "Missing name",
+			config:           "slack: {webhookUrl: https://hooks.slack.com/x}\nsubscription: {eventCategories: [Infra], eventPriorities: [High]}",
+			expectedErrorMsg: "channel name is required",
+		},
+		{
+			name:             "Missing destination",
+			config:           "name: c\nsubscription: {eventCategories: [Infra], eventPriorities: [High]}",
+			expectedErrorMsg: "one of email, slack, webhook or pagerDuty must be configured",
+		},
+		{
+			name:             "Several destinations",
+			config:           "name: c\nemail: {to: ops@example.com}\npagerDuty: {integrationKey: k}\nsubscription: {eventCategories: [Infra], eventPriorities: [High]}",
+			expectedErrorMsg: "a channel has a single destination, got email, pagerDuty",
+		},
+		{
+			name:             "Missing subscription",
+			config:           "name: c\nemail: {to: ops@example.com}",
+			expectedErrorMsg: "subscription is required",
+		},
+		{
+			name:             "Empty priorities",
+			config:           "name: c\nemail: {to: ops@example.com}\nsubscription: {eventCategories: [Infra]}",
+			expectedErrorMsg: "subscription.eventPriorities must list at least one priority",
+		},
+		{
+			name:             "Invalid email",
+			config:           "name: c\nemail: {to: ops}\nsubscription: {eventCategories: [Infra], eventPriorities: [High]}",
+			expectedErrorMsg: "invalid email.to address",
+		},
+		{
+			name:             "Invalid webhook URL",
+			config:           "name: c\nwebhook: {url: hooks.example.com}\nsubscription: {eventCategories: [Infra], eventPriorities: [High]}",
+			expectedErrorMsg: "expected an http or https URL",
+		},
+		{
+			name:             "Invalid webhook method",
+			config:           "name: c\nwebhook: {url: 'https://hooks.example.com', method: delete}\nsubscription: {eventCategories: [Infra], eventPriorities: [High]}",
+			expectedErrorMsg: "unsupported webhook.method DELETE",
+		},
+		{
+			name:             "Missing PagerDuty key",
+			config:           "name: c\npagerDuty: {integrationKey: ' '}\nsubscription: {eventCategories: [Infra], eventPriorities: [High]}",
+			expectedErrorMsg: "pagerDuty.integrationKey is required",
+		},
+		{
+			name:             "Empty update",
+			config:           "",
+			forUpdate:        true,
+			expectedErrorMsg: "nothing to update",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := parseChannelConfig([]byte(test.config))
+			require.NoError(t, err)
+
+			if test.forUpdate {
+				err = config.validateForUpdate()
+			} else {
+				err = config.validateForCreate()
+			}
+			require.ErrorContains(t, err, test.expectedErrorMsg)
+		})
+	}
+}
+
+func TestParseChannelConfigRejectsUnknownFields(t *testing.T) {
+	_, err := parseChannelConfig([]byte("name: c\nslack: {webhookURL: https://hooks.slack.com/x}"))
+	require.ErrorContains(t, err, "field webhookURL not found")
+}
+
+func TestChannelConfigToUpdateRequest(t *testing.T) {
+	require := require.New(t)
+
+	config, err := parseChannelConfig([]byte("slack: {webhookUrl: 'https://hooks.slack.com/services/x'}"))
+	require.NoError(err)
+	require.NoError(config.validateForUpdate())
+
+	request := config.toUpdateRequest()
+	require.Nil(request.Name)
+	require.Nil(request.Subscription)
+	require.Nil(request.Webhook)
+	require.Equal("https://hooks.slack.com/services/x", request.Slack.GetWebhookUrl())
+}
@@ -0,0 +1,80 @@
+package notificationchannel
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create --file [config-file]",
+	Short: "Create a notification channel from a YAML config file",
+	Long: `Create a notification channel from a YAML config file. The file sets the channel name, exactly one destination
+(email, slack, webhook or pagerDuty) and the subscription that selects which events are routed to the channel.
+
+Example config:
+
+` + exampleChannelConfig,
+	Example: `# Create a notification channel
+omctl alarms notification-channel create --file channel.yaml
+
+# Create a channel, overriding the name in the config file
+omctl alarms notification-channel create --file channel.yaml --name staging-alerts`,
+	Args: cobra.NoArgs,
+	RunE: runCreate,
+}
+
+func init() {
+	createCmd.Flags().StringP("file", "f", "", "Path to the YAML channel config file")
+	createCmd.Flags().String("name", "", "Name of the channel, overriding the name in the config file")
+	_ = createCmd.MarkFlagRequired("file")
+}
+
+func runCreate(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	name, _ := cmd.Flags().GetString("name")
+
+	config, err := loadChannelConfig(file)
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		config.Name = name
+	}
+	if err = config.validateForCreate(); err != nil {
+		return fmt.Errorf("invalid channel config %s: %v", file, err)
+	}
+
+	// Validate user is currently logged in
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	channelID, err := dataaccess.CreateNotificationChannel(cmd.Context(), token, config.toCreateRequest())
+	if err != nil {
+		return fmt.Errorf("failed to create notification channel: %v", err)
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == "json" {
+		channel, err := dataaccess.GetNotificationChannel(cmd.Context(), token, channelID)
+		if err != nil {
+			return fmt.Errorf("failed to describe notification channel %s: %v", channelID, err)
+		}
+		jsonData, err := json.MarshalIndent(channel, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	fmt.Printf("Successfully created notification channel %s: %s\n", config.Name, channelID)
+	return nil
+}
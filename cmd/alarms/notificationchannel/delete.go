@@ -0,0 +1,70 @@
+package notificationchannel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cqroot/prompt"
+	"github.com/cqroot/prompt/input"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete [channel-id]",
+	Short: "Delete a notification channel",
+	Long:  `Delete a notification channel. Events are no longer routed to the channel once it is deleted.`,
+	Example: `# Delete a notification channel
+omctl alarms notification-channel delete [channel-id]
+
+# Delete without prompting for confirmation
+omctl alarms notification-channel delete [channel-id] --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDelete,
+}
+
+func init() {
+	deleteCmd.Flags().BoolP("yes", "y", false, "Pre-approve the deletion of the notification channel without prompting for confirmation")
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	channelID := args[0]
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	// Validate user is currently logged in
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	if !yes {
+		ok, err := prompt.New().Ask("Are you sure you want to delete this notification channel? (y/n)").
+			Input("", input.WithValidateFunc(
+				func(input string) error {
+					if slices.Contains([]string{"y", "yes", "n", "no"}, strings.ToLower(input)) {
+						return nil
+					}
+					return errors.New("invalid input")
+				}))
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains([]string{"y", "yes"}, strings.ToLower(ok)) {
+			return nil
+		}
+	}
+
+	err = dataaccess.DeleteNotificationChannel(cmd.Context(), token, channelID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %v", err)
+	}
+
+	fmt.Printf("Successfully deleted notification channel: %s\n", channelID)
+	return nil
+}
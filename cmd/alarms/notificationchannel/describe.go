@@ -0,0 +1,83 @@
+package notificationchannel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/spf13/cobra"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe [channel-id]",
+	Short: "Describe a notification channel",
+	Long:  `Display the destination and subscription of a notification channel. Use --output json to see the full channel configuration.`,
+	Example: `# Describe a notification channel
+omctl alarms notification-channel describe [channel-id]`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDescribe,
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	channelID := args[0]
+
+	// Validate user is currently logged in
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	channel, err := dataaccess.GetNotificationChannel(cmd.Context(), token, channelID)
+	if err != nil {
+		return fmt.Errorf("failed to describe notification channel: %v", err)
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == "json" {
+		jsonData, err := json.MarshalIndent(channel, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	subscription := channel.GetSubscription()
+	table := utils.NewTable([]any{"Field", "Value"})
+	table.AddRow([]any{"ID", channel.GetId()})
+	table.AddRow([]any{"Name", channel.GetName()})
+	table.AddRow([]any{"Type", channel.GetChannelType()})
+	table.AddRow([]any{"Destination", channelDestination(channel)})
+	table.AddRow([]any{"Event Categories", formatStringArray(subscription.GetEventCategories(), 200)})
+	table.AddRow([]any{"Event Priorities", formatStringArray(subscription.GetEventPriorities(), 200)})
+	table.AddRow([]any{"Event Types", formatStringArray(subscription.GetEventTypes(), 200)})
+	table.AddRow([]any{"Alert Types", formatStringArray(subscription.GetAlertTypes(), 200)})
+	table.AddRow([]any{"Environment Types", formatStringArray(subscription.GetEnvironmentTypes(), 200)})
+	table.Print()
+	return nil
+}
+
+// channelDestination describes where a channel delivers events, without secrets such as headers or integration keys.
+func channelDestination(channel *openapiclientfleet.Channel) string {
+	switch {
+	case channel.Webhook != nil:
+		return fmt.Sprintf("%s %s", channel.Webhook.GetMethod(), channel.Webhook.GetUrl())
+	case channel.Slack != nil:
+		// Slack webhook URLs embed their token, so only the host is shown
+		if parsed, err := url.Parse(channel.Slack.GetWebhookUrl()); err == nil && parsed.Host != "" {
+			return fmt.Sprintf("Slack webhook on %s", parsed.Host)
+		}
+		return "Slack webhook"
+	case channel.Email != nil:
+		return channel.Email.GetTo()
+	case channel.PagerDuty != nil:
+		return "PagerDuty integration"
+	default:
+		return "-"
+	}
+}
@@ -3,6 +3,7 @@ package notificationchannel
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -18,7 +19,17 @@ import (
 var eventHistoryCmd = &cobra.Command{
 	Use:   "event-history [channel-id]",
 	Short: "Show event history for a notification channel with interactive TUI",
-	Long:  `Display event history for a notification channel in an interactive table interface that allows expanding rows to see event details.`,
+	Long: `Display event history for a notification channel in an interactive table interface that allows expanding rows to see event details.
+
+Events can be filtered on the fields read from the event body, and exported as CSV or newline-delimited JSON with --format.`,
+	Example: `# Browse the event history of a channel
+omctl alarms notification-channel event-history [channel-id]
+
+# Show only failed critical events
+omctl alarms notification-channel event-history [channel-id] --filter "publication_status = FAILED and priority = Critical"
+
+# Export the events of an instance over a day as CSV
+omctl alarms notification-channel event-history [channel-id] --start-time 2025-01-01T00:00:00Z --end-time 2025-01-02T00:00:00Z --filter "instance_id = instance-abcd1234" --format csv > events.csv`,
 	Args:  cobra.ExactArgs(1),
 	RunE:  runEventHistory,
 }
@@ -26,11 +37,15 @@ var eventHistoryCmd = &cobra.Command{
 var (
 	startTimeFlag string
 	endTimeFlag   string
+	eventFilters  []string
+	formatFlag    string
 )
 
 func init() {
 	eventHistoryCmd.Flags().StringVarP(&startTimeFlag, "start-time", "s", "", "Start time for event history (RFC3339 format)")
 	eventHistoryCmd.Flags().StringVarP(&endTimeFlag, "end-time", "e", "", "End time for event history (RFC3339 format)")
+	eventHistoryCmd.Flags().StringArrayVarP(&eventFilters, "filter", "f", []string{}, "Filter to apply to the events, e.g. \"event_type = InstanceFailed\". "+utils.FilterExpressionSyntax+" Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(eventRecord{}, utils.ScalarFieldsOnly), ","))
+	eventHistoryCmd.Flags().StringVar(&formatFlag, "format", "", "Export the events instead of opening the interactive view (csv|ndjson)")
 }
 
 func runEventHistory(cmd *cobra.Command, args []string) error {
@@ -60,11 +75,34 @@ func runEventHistory(cmd *cobra.Command, args []string) error {
 		endTime = &t
 	}
 
+	if formatFlag != "" && formatFlag != "csv" && formatFlag != "ndjson" {
+		return fmt.Errorf("invalid format %s, expected csv or ndjson", formatFlag)
+	}
+
 	result, err := dataaccess.GetNotificationChannelEventHistory(cmd.Context(), token, channelID, startTime, endTime)
 	if err != nil {
 		return fmt.Errorf("failed to get event history: %v", err)
 	}
 
+	records := make([]eventRecord, 0, len(result.GetEvents()))
+	for _, event := range result.GetEvents() {
+		records = append(records, newEventRecord(channelID, event))
+	}
+	if records, err = filterEventRecords(records, eventFilters); err != nil {
+		return err
+	}
+
+	switch formatFlag {
+	case "csv":
+		return writeEventRecordsCSV(os.Stdout, records)
+	case "ndjson":
+		return writeEventRecordsNDJSON(os.Stdout, records)
+	}
+
+	if len(eventFilters) > 0 {
+		result.SetEvents(filterEvents(result.GetEvents(), records))
+	}
+
 	outputFormat, _ := cmd.Flags().GetString("output")
 	if outputFormat == "json" {
 		jsonData, err := json.MarshalIndent(result, "", "  ")
@@ -255,6 +293,22 @@ func showEventHistoryTUI(events []openapiclientfleet.Event, channelID string) er
 	return nil
 }
 
+// filterEvents keeps the events with a matching record, preserving their order.
+func filterEvents(events []openapiclientfleet.Event, records []eventRecord) []openapiclientfleet.Event {
+	matched := make(map[string]bool, len(records))
+	for _, record := range records {
+		matched[record.EventID] = true
+	}
+
+	filtered := make([]openapiclientfleet.Event, 0, len(records))
+	for _, event := range events {
+		if matched[event.GetId()] {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
 func getEventColor(event openapiclientfleet.Event) tcell.Color {
 	status := strings.ToLower(event.GetPublicationStatus())
 	switch {
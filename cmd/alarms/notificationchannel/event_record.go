@@ -0,0 +1,127 @@
+package notificationchannel
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+)
+
+// eventRecord is a flattened view of a notification event used for filtering and export. The type, priority,
+// instance and message are read from the event body, whose shape depends on the event.
+type eventRecord struct {
+	EventID           string      `json:"event_id"`
+	ChannelID         string      `json:"channel_id"`
+	Timestamp         string      `json:"timestamp"`
+	PublicationStatus string      `json:"publication_status"`
+	EventType         string      `json:"event_type"`
+	Priority          string      `json:"priority"`
+	InstanceID        string      `json:"instance_id"`
+	Message           string      `json:"message"`
+	Body              interface{} `json:"body,omitempty"`
+	ChannelResponse   interface{} `json:"channel_response,omitempty"`
+}
+
+// Body keys the event fields are read from, in order of preference.
+var (
+	eventTypeBodyKeys  = []string{"eventType", "type", "name"}
+	priorityBodyKeys   = []string{"priority", "eventPriority", "severity"}
+	instanceIDBodyKeys = []string{"instanceId", "instanceID", "resourceInstanceId", "resourceInstanceID"}
+	messageBodyKeys    = []string{"message", "description", "summary"}
+)
+
+var eventRecordCSVColumns = []string{"event_id", "channel_id", "timestamp", "publication_status", "event_type", "priority", "instance_id", "message"}
+
+func newEventRecord(channelID string, event openapiclientfleet.Event) eventRecord {
+	body, _ := event.GetBody().(map[string]interface{})
+	return eventRecord{
+		EventID:           event.GetId(),
+		ChannelID:         channelID,
+		Timestamp:         event.GetTimestamp().UTC().Format(time.RFC3339),
+		PublicationStatus: event.GetPublicationStatus(),
+		EventType:         eventBodyValue(body, eventTypeBodyKeys),
+		Priority:          eventBodyValue(body, priorityBodyKeys),
+		InstanceID:        eventBodyValue(body, instanceIDBodyKeys),
+		Message:           eventBodyValue(body, messageBodyKeys),
+		Body:              event.GetBody(),
+		ChannelResponse:   event.GetChannelResponse(),
+	}
+}
+
+// eventBodyValue returns the first of keys found in the body, or in a nested "payload" object.
+func eventBodyValue(body map[string]interface{}, keys []string) string {
+	for _, candidate := range []map[string]interface{}{body, nestedBodyObject(body, "payload")} {
+		for _, key := range keys {
+			for bodyKey, value := range candidate {
+				if !strings.EqualFold(bodyKey, key) || value == nil {
+					continue
+				}
+				if s, ok := value.(string); ok {
+					return s
+				}
+				return fmt.Sprintf("%v", value)
+			}
+		}
+	}
+	return ""
+}
+
+func nestedBodyObject(body map[string]interface{}, key string) map[string]interface{} {
+	nested, _ := body[key].(map[string]interface{})
+	return nested
+}
+
+// isUndelivered reports whether the event failed to reach the channel, matching the statuses shown in red by
+// event-history.
+func (r eventRecord) isUndelivered() bool {
+	status := strings.ToLower(r.PublicationStatus)
+	return strings.Contains(status, "failed") || strings.Contains(status, "error")
+}
+
+func filterEventRecords(records []eventRecord, filters []string) ([]eventRecord, error) {
+	expressions, err := utils.ParseFilterExpressions(filters, utils.GetSupportedFilterKeys(eventRecord{}, utils.ScalarFieldsOnly))
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []eventRecord
+	for _, record := range records {
+		ok, err := utils.MatchesFilterExpressions(record, expressions)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered, nil
+}
+
+func writeEventRecordsCSV(w io.Writer, records []eventRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(eventRecordCSVColumns); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := writer.Write([]string{r.EventID, r.ChannelID, r.Timestamp, r.PublicationStatus, r.EventType, r.Priority, r.InstanceID, r.Message}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeEventRecordsNDJSON(w io.Writer, records []eventRecord) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
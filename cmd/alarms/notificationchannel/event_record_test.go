@@ -0,0 +1,139 @@
+package notificationchannel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(id, status string, timestamp time.Time, body map[string]interface{}) openapiclientfleet.Event {
+	event := openapiclientfleet.NewEvent(id, status, timestamp)
+	event.SetBody(body)
+	return *event
+}
+
+func testEventRecords() []eventRecord {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	return []eventRecord{
+		newEventRecord("nc-1", newTestEvent("e-1", "SUCCESS", now, map[string]interface{}{
+			"eventType": "InstanceFailed", "priority": "Critical", "instanceId": "instance-1", "message": "Instance failed",
+		})),
+		newEventRecord("nc-1", newTestEvent("e-2", "FAILED", now.Add(-time.Hour), map[string]interface{}{
+			"type": "MaintenanceScheduled", "payload": map[string]interface{}{"priority": "Low", "instanceID": "instance-2"},
+		})),
+		newEventRecord("nc-2", newTestEvent("e-3", "ERROR", now.Add(-2*time.Hour), map[string]interface{}{
+			"eventType": "InstanceFailed", "priority": "Critical", "instanceId": "instance-2",
+		})),
+		newEventRecord("nc-2", newTestEvent("e-2", "FAILED", now.Add(-time.Hour), nil)),
+	}
+}
+
+func TestNewEventRecord(t *testing.T) {
+	require := require.New(t)
+
+	records := testEventRecords()
+	require.Equal(eventRecord{
+		EventID:           "e-2",
+		ChannelID:         "nc-1",
+		Timestamp:         "2026-01-02T09:00:00Z",
+		PublicationStatus: "FAILED",
+		EventType:         "MaintenanceScheduled",
+		Priority:          "Low",
+		InstanceID:        "instance-2",
+		Body:              records[1].Body,
+	}, records[1])
+	require.Equal("Instance failed", records[0].Message)
+
+	supportedKeys := utils.GetSupportedFilterKeys(eventRecord{}, utils.ScalarFieldsOnly)
+	require.Contains(supportedKeys, "instance_id")
+	require.NotContains(supportedKeys, "body")
+}
+
+func TestFilterEventRecords(t *testing.T) {
+	require := require.New(t)
+
+	filtered, err := filterEventRecords(testEventRecords(), []string{"event_type = InstanceFailed and instance_id = instance-2"})
+	require.NoError(err)
+	require.Len(filtered, 1)
+	require.Equal("e-3", filtered[0].EventID)
+
+	filtered, err = filterEventRecords(testEventRecords(), nil)
+	require.NoError(err)
+	require.Len(filtered, 4)
+
+	_, err = filterEventRecords(testEventRecords(), []string{"body = x"})
+	require.ErrorContains(err, "unsupported filter key: body")
+}
+
+func TestWriteEventRecords(t *testing.T) {
+	require := require.New(t)
+
+	records := testEventRecords()[:2]
+
+	var csv bytes.Buffer
+	require.NoError(writeEventRecordsCSV(&csv, records))
+	require.Equal(`event_id,channel_id,timestamp,publication_status,event_type,priority,instance_id,message
+e-1,nc-1,2026-01-02T10:00:00Z,SUCCESS,InstanceFailed,Critical,instance-1,Instance failed
+e-2,nc-1,2026-01-02T09:00:00Z,FAILED,MaintenanceScheduled,Low,instance-2,
+`, csv.String())
+
+	var ndjson bytes.Buffer
+	require.NoError(writeEventRecordsNDJSON(&ndjson, records))
+	lines := strings.Split(strings.TrimSpace(ndjson.String()), "\n")
+	require.Len(lines, 2)
+	require.Contains(lines[1], `"event_id":"e-2"`)
+	require.Contains(lines[1], `"body":{"payload":{"instanceID":"instance-2","priority":"Low"},"type":"MaintenanceScheduled"}`)
+}
+
+func TestReplayUndeliveredEvents(t *testing.T) {
+	require := require.New(t)
+
+	undelivered := undeliveredEventRecords(testEventRecords())
+	require.Len(undelivered, 2)
+	require.Equal("e-3", undelivered[0].EventID)
+	require.Equal("e-2", undelivered[1].EventID)
+
+	var replayed []string
+	results := replayEvents(context.Background(), undelivered, 1000, func(_ context.Context, eventID string) error {
+		replayed = append(replayed, eventID)
+		if eventID == "e-3" {
+			return errors.New("event not found")
+		}
+		return nil
+	})
+	require.Equal([]string{"e-3", "e-2"}, replayed)
+	require.Equal(replayStatusFailed, results[0].Status)
+	require.Equal("event not found", results[0].Error)
+	require.Equal(replayStatusReplayed, results[1].Status)
+	require.Equal("2 failed events found, 1 replayed, 1 failed", summarizeReplayResults(results))
+
+	results = replayEvents(context.Background(), undelivered, 1000, nil)
+	require.Equal("2 failed events found, 2 skipped (dry run)", summarizeReplayResults(results))
+}
+
+func TestReplayEventsStopsWhenCancelled(t *testing.T) {
+	require := require.New(t)
+
+	undelivered := undeliveredEventRecords(testEventRecords())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The second event waits an hour for the rate limit, so it is only left by the cancellation
+	var replayed []string
+	results := replayEvents(ctx, undelivered, 1.0/3600, func(_ context.Context, eventID string) error {
+		replayed = append(replayed, eventID)
+		cancel()
+		return nil
+	})
+	require.Equal([]string{"e-3"}, replayed)
+	require.Len(results, 2)
+	require.Equal(replayStatusReplayed, results[0].Status)
+	require.Equal(replayStatusFailed, results[1].Status)
+	require.Equal(context.Canceled.Error(), results[1].Error)
+}
@@ -14,6 +14,11 @@ var Cmd = &cobra.Command{
 
 func init() {
 	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(describeCmd)
+	Cmd.AddCommand(createCmd)
+	Cmd.AddCommand(updateCmd)
+	Cmd.AddCommand(deleteCmd)
+	Cmd.AddCommand(testCmd)
 	Cmd.AddCommand(eventHistoryCmd)
 	Cmd.AddCommand(replayEventCmd)
 }
@@ -23,4 +28,4 @@ func run(cmd *cobra.Command, args []string) {
 	if err != nil {
 		return
 	}
-}
\ No newline at end of file
+}
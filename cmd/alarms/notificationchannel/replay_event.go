@@ -1,30 +1,83 @@
 package notificationchannel
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
 	"github.com/spf13/cobra"
 )
 
 var replayEventCmd = &cobra.Command{
 	Use:   "replay-event [event-id]",
 	Short: "Replay a specific event to notification channels",
-	Long:  `Replay a specific event by its ID to all configured notification channels.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Replay a specific event by its ID to all configured notification channels.
+
+With --all-failed, the event history of every channel (or of the channel set with --channel-id) is searched for events
+that failed to be delivered since --since, and each of them is replayed once, at most --rate events per second.`,
+	Example: `# Replay a single event
+omctl alarms notification-channel replay-event [event-id]
+
+# Replay all events that failed to be delivered in the last 2 hours
+omctl alarms notification-channel replay-event --all-failed --since 2h
+
+# Preview the failed critical events of a channel that would be replayed
+omctl alarms notification-channel replay-event --all-failed --since 24h --channel-id [channel-id] --filter "priority = Critical" --dry-run`,
+	Args:  cobra.MaximumNArgs(1),
 	RunE:  runReplayEvent,
 }
 
+// replayResult is the outcome of replaying one event.
+type replayResult struct {
+	EventID   string `json:"event_id"`
+	ChannelID string `json:"channel_id"`
+	EventType string `json:"event_type"`
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+const (
+	replayStatusReplayed = "replayed"
+	replayStatusFailed   = "failed"
+	replayStatusSkipped  = "skipped (dry run)"
+)
+
+func init() {
+	replayEventCmd.Flags().Bool("all-failed", false, "Replay all events that failed to be delivered since --since")
+	replayEventCmd.Flags().Duration("since", 24*time.Hour, "How far back to look for failed events with --all-failed, e.g. 2h")
+	replayEventCmd.Flags().String("channel-id", "", "Only replay failed events of this notification channel")
+	replayEventCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the failed events, e.g. \"priority = Critical\". "+utils.FilterExpressionSyntax+" Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(eventRecord{}, utils.ScalarFieldsOnly), ","))
+	replayEventCmd.Flags().Float64("rate", 5, "Maximum number of events replayed per second with --all-failed")
+	replayEventCmd.Flags().Bool("dry-run", false, "List the failed events that would be replayed without replaying them")
+}
+
 func runReplayEvent(cmd *cobra.Command, args []string) error {
-	eventID := args[0]
-	
+	allFailed, _ := cmd.Flags().GetBool("all-failed")
+	if allFailed && len(args) > 0 {
+		return fmt.Errorf("an event ID can't be combined with --all-failed")
+	}
+	if !allFailed && len(args) == 0 {
+		return fmt.Errorf("an event ID or --all-failed is required")
+	}
+
 	// Validate user is currently logged in
 	token, err := common.GetTokenWithLogin()
 	if err != nil {
 		return fmt.Errorf("authentication failed: %v", err)
 	}
 
+	if allFailed {
+		return runReplayAllFailed(cmd, token)
+	}
+
+	eventID := args[0]
 	err = dataaccess.ReplayNotificationEvent(cmd.Context(), token, eventID)
 	if err != nil {
 		return fmt.Errorf("failed to replay event: %v", err)
@@ -32,4 +85,175 @@ func runReplayEvent(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Successfully replayed event: %s\n", eventID)
 	return nil
-}
\ No newline at end of file
+}
+
+func runReplayAllFailed(cmd *cobra.Command, token string) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	channelID, _ := cmd.Flags().GetString("channel-id")
+	filters, _ := cmd.Flags().GetStringArray("filter")
+	rate, _ := cmd.Flags().GetFloat64("rate")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	if since <= 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+	if rate <= 0 {
+		return fmt.Errorf("--rate must be positive")
+	}
+
+	channelIDs := []string{channelID}
+	if channelID == "" {
+		channels, err := dataaccess.ListNotificationChannels(cmd.Context(), token)
+		if err != nil {
+			return fmt.Errorf("failed to list notification channels: %v", err)
+		}
+		channelIDs = nil
+		for _, channel := range channels.GetChannels() {
+			channelIDs = append(channelIDs, channel.GetId())
+		}
+	}
+
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-since)
+	var records []eventRecord
+	for _, id := range channelIDs {
+		history, err := dataaccess.GetNotificationChannelEventHistory(cmd.Context(), token, id, &startTime, &endTime)
+		if err != nil {
+			return fmt.Errorf("failed to get event history of channel %s: %v", id, err)
+		}
+		for _, event := range history.GetEvents() {
+			records = append(records, newEventRecord(id, event))
+		}
+	}
+
+	failed, err := filterEventRecords(undeliveredEventRecords(records), filters)
+	if err != nil {
+		return err
+	}
+	if len(failed) == 0 {
+		if outputFormat == "json" {
+			fmt.Println("[]")
+			return nil
+		}
+		fmt.Printf("No failed events found since %s.\n", startTime.Format(time.RFC3339))
+		return nil
+	}
+
+	replay := func(ctx context.Context, eventID string) error {
+		return dataaccess.ReplayNotificationEvent(ctx, token, eventID)
+	}
+	if dryRun {
+		replay = nil
+	}
+	results := replayEvents(cmd.Context(), failed, rate, replay)
+
+	if outputFormat == "json" {
+		jsonData, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		table := utils.NewTable([]any{"Event ID", "Channel ID", "Event Type", "Timestamp", "Status", "Error"})
+		for _, result := range results {
+			table.AddRow([]any{result.EventID, result.ChannelID, result.EventType, result.Timestamp, result.Status, result.Error})
+		}
+		table.Print()
+	}
+
+	summary := summarizeReplayResults(results)
+	if outputFormat != "json" {
+		fmt.Println(summary)
+	}
+	for _, result := range results {
+		if result.Status == replayStatusFailed {
+			return fmt.Errorf("some events could not be replayed: %s", summary)
+		}
+	}
+	return nil
+}
+
+// undeliveredEventRecords returns the undelivered events, oldest first. An event that failed on several channels is
+// returned once, since replaying it sends it to all channels again.
+func undeliveredEventRecords(records []eventRecord) []eventRecord {
+	seen := make(map[string]bool)
+	var undelivered []eventRecord
+	for _, record := range records {
+		if !record.isUndelivered() || seen[record.EventID] {
+			continue
+		}
+		seen[record.EventID] = true
+		undelivered = append(undelivered, record)
+	}
+
+	sort.SliceStable(undelivered, func(i, j int) bool {
+		return undelivered[i].Timestamp < undelivered[j].Timestamp
+	})
+	return undelivered
+}
+
+// replayEvents replays the events one at a time, at most rate per second, and carries on past failures. A nil
+// replay function only reports the events, for dry runs. Once the context is done, the events left are reported as
+// failed without being replayed.
+func replayEvents(ctx context.Context, records []eventRecord, rate float64, replay func(ctx context.Context, eventID string) error) []replayResult {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer ticker.Stop()
+
+	results := make([]replayResult, 0, len(records))
+	for i, record := range records {
+		result := newReplayResult(record)
+
+		switch {
+		case replay == nil:
+			result.Status = replayStatusSkipped
+		case ctx.Err() != nil:
+			result.Status, result.Error = replayStatusFailed, ctx.Err().Error()
+		default:
+			if i > 0 {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					for _, record := range records[i:] {
+						result = newReplayResult(record)
+						result.Status, result.Error = replayStatusFailed, ctx.Err().Error()
+						results = append(results, result)
+					}
+					return results
+				}
+			}
+			if err := replay(ctx, record.EventID); err != nil {
+				result.Status, result.Error = replayStatusFailed, err.Error()
+			} else {
+				result.Status = replayStatusReplayed
+			}
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
+
+func newReplayResult(record eventRecord) replayResult {
+	return replayResult{
+		EventID:   record.EventID,
+		ChannelID: record.ChannelID,
+		EventType: record.EventType,
+		Timestamp: record.Timestamp,
+	}
+}
+
+func summarizeReplayResults(results []replayResult) string {
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	parts := []string{fmt.Sprintf("%d failed events found", len(results))}
+	for _, status := range []string{replayStatusReplayed, replayStatusFailed, replayStatusSkipped} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
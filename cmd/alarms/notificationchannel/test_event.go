@@ -0,0 +1,202 @@
+package notificationchannel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/spf13/cobra"
+)
+
+const (
+	testEventType          = "NotificationChannelTest"
+	testEventCategory      = "ServiceEvent"
+	testEventAlertType     = "Notification"
+	testEventPriority      = "Low"
+	testEventScope         = "ServiceProvider"
+	testEventExpiry        = 24 * time.Hour
+	pagerDutyEventsURL     = "https://events.pagerduty.com/v2/enqueue"
+	maxTestResponseBodyLen = 512
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [channel-id]",
+	Short: "Send a synthetic event from this machine to the destination of a notification channel",
+	Long: `Send a synthetic test event to the destination of a notification channel, to confirm that the destination of a
+webhook, Slack or PagerDuty channel accepts events. Webhooks receive the event in the format Omnistrate delivers
+events in, with the channel's method, headers and additional body parameters.
+
+The event doesn't go through Omnistrate: it is sent directly from this machine, so it can't show that Omnistrate can
+reach the destination, e.g. through a firewall or from outside a private network, and it doesn't appear in the
+channel's event history. To check delivery through Omnistrate, replay a recent event with replay-event. Email channels
+can't be tested this way.`,
+	Example: `# Send a test event to a notification channel
+omctl alarms notification-channel test [channel-id]`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTest,
+}
+
+// testEventResult is the outcome of delivering a test event to a channel's destination.
+type testEventResult struct {
+	ChannelID   string `json:"channelId"`
+	ChannelName string `json:"channelName"`
+	EventID     string `json:"eventId"`
+	Destination string `json:"destination"`
+	StatusCode  int    `json:"statusCode"`
+	Response    string `json:"response,omitempty"`
+}
+
+func init() {
+	testCmd.Flags().Duration("timeout", 10*time.Second, "Timeout for delivering the test event")
+}
+
+func runTest(cmd *cobra.Command, args []string) error {
+	channelID := args[0]
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	// Validate user is currently logged in
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	channel, err := dataaccess.GetNotificationChannel(cmd.Context(), token, channelID)
+	if err != nil {
+		return fmt.Errorf("failed to describe notification channel: %v", err)
+	}
+
+	result, err := sendTestEvent(cmd.Context(), &http.Client{Timeout: timeout}, channel, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("test event to notification channel %s failed: %v", channelID, err)
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == "json" {
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	fmt.Printf("Successfully delivered test event %s to %s (HTTP %d)\n", result.EventID, result.Destination, result.StatusCode)
+	return nil
+}
+
+// sendTestEvent delivers a synthetic event to the channel's destination and fails unless it answers with a 2xx status.
+func sendTestEvent(ctx context.Context, client *http.Client, channel *openapiclientfleet.Channel, now time.Time) (*testEventResult, error) {
+	eventID := fmt.Sprintf("test-%d", now.UnixNano())
+	message := fmt.Sprintf("Test event from omctl for notification channel %s (%s)", channel.GetName(), channel.GetId())
+
+	var method, url string
+	var headers map[string]string
+	var body map[string]interface{}
+	switch {
+	case channel.Webhook != nil:
+		method, url = channel.Webhook.GetMethod(), channel.Webhook.GetUrl()
+		if method == "" {
+			method = http.MethodPost
+		}
+		headers = channel.Webhook.GetHeaders()
+
+		// Webhooks receive the event as Omnistrate delivers it, with the additional body parameters of the channel
+		event := openapiclientfleet.NewServiceProviderEvent(
+			testEventAlertType,
+			testEventCategory,
+			eventID,
+			map[string]interface{}{
+				"message":     message,
+				"channelId":   channel.GetId(),
+				"channelName": channel.GetName(),
+			},
+			testEventType,
+			now.Add(testEventExpiry).Format(time.RFC3339),
+			testEventPriority,
+			testEventScope,
+			now.Format(time.RFC3339),
+		)
+		var err error
+		if body, err = event.ToMap(); err != nil {
+			return nil, err
+		}
+		for key, value := range channel.Webhook.GetAdditionalBodyParameters() {
+			body[key] = value
+		}
+	case channel.Slack != nil:
+		method, url = http.MethodPost, channel.Slack.GetWebhookUrl()
+		body = map[string]interface{}{"text": message}
+	case channel.PagerDuty != nil:
+		method, url = http.MethodPost, pagerDutyEventsURL
+		body = map[string]interface{}{
+			"routing_key":  channel.PagerDuty.GetIntegrationKey(),
+			"event_action": "trigger",
+			"dedup_key":    eventID,
+			"payload": map[string]interface{}{
+				"summary":   message,
+				"source":    "omctl",
+				"severity":  "info",
+				"timestamp": now.Format(time.RFC3339),
+			},
+		}
+	case channel.Email != nil:
+		return nil, fmt.Errorf("email channels can't be tested from the CLI, replay a recent event with replay-event instead")
+	default:
+		return nil, fmt.Errorf("channel type %s has no destination that can be tested", channel.GetChannelType())
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		// Don't echo the URL, which embeds the token of Slack webhooks
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("failed to reach %s: %v", channelDestination(channel), err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(response.Body, maxTestResponseBodyLen))
+	result := &testEventResult{
+		ChannelID:   channel.GetId(),
+		ChannelName: channel.GetName(),
+		EventID:     eventID,
+		Destination: channelDestination(channel),
+		StatusCode:  response.StatusCode,
+		Response:    strings.TrimSpace(string(responseBody)),
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return result, fmt.Errorf("%s answered with HTTP %d: %s", result.Destination, response.StatusCode, result.Response)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package notificationchannel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendTestEventToWebhook(t *testing.T) {
+	require := require.New(t)
+
+	var received map[string]interface{}
+	var header http.Header
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, header = r.Method, r.Header
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &received)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	webhook := openapiclientfleet.NewWebhookConfiguration(http.MethodPut, server.URL)
+	webhook.SetHeaders(map[string]string{"Authorization": "Bearer token"})
+	webhook.SetAdditionalBodyParameters(map[string]interface{}{"team": "ops"})
+	channel := openapiclientfleet.NewChannel("webhook", "nc-123", "ops-webhook", *openapiclientfleet.NewChannelSubscriptionWithDefaults())
+	channel.Webhook = webhook
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	result, err := sendTestEvent(context.Background(), server.Client(), channel, now)
+	require.NoError(err)
+	require.Equal(http.StatusOK, result.StatusCode)
+	require.Equal("ok", result.Response)
+	require.Equal("nc-123", result.ChannelID)
+
+	require.Equal(http.MethodPut, method)
+	require.Equal("Bearer token", header.Get("Authorization"))
+	require.Equal("application/json", header.Get("Content-Type"))
+	require.Equal(testEventType, received["eventType"])
+	require.Equal(result.EventID, received["eventID"])
+	require.Equal("ServiceEvent", received["eventCategory"])
+	require.Equal("Notification", received["alertType"])
+	require.Equal("Low", received["priority"])
+	require.Equal("ServiceProvider", received["scope"])
+	require.Equal("2026-01-02T03:04:05Z", received["time"])
+	require.Equal("2026-01-03T03:04:05Z", received["expiryTime"])
+	require.Equal("nc-123", received["eventPayload"].(map[string]interface{})["channelId"])
+	require.Equal("ops", received["team"])
+
+	// The event is a valid delivered event
+	data, err := json.Marshal(received)
+	require.NoError(err)
+	var event openapiclientfleet.ServiceProviderEvent
+	require.NoError(json.Unmarshal(data, &event))
+}
+
+func TestSendTestEventToSlackFailure(t *testing.T) {
+	require := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("invalid_token"))
+	}))
+	defer server.Close()
+
+	channel := openapiclientfleet.NewChannel("slack", "nc-123", "ops-slack", *openapiclientfleet.NewChannelSubscriptionWithDefaults())
+	channel.Slack = openapiclientfleet.NewSlackConfiguration(server.URL + "/services/secret")
+
+	result, err := sendTestEvent(context.Background(), server.Client(), channel, time.Now())
+	require.ErrorContains(err, "answered with HTTP 403: invalid_token")
+	require.Equal(http.StatusForbidden, result.StatusCode)
+	require.NotContains(err.Error(), "secret")
+}
+
+func TestSendTestEventToEmail(t *testing.T) {
+	channel := openapiclientfleet.NewChannel("email", "nc-123", "ops-email", *openapiclientfleet.NewChannelSubscriptionWithDefaults())
+	channel.Email = openapiclientfleet.NewEmailConfiguration("ops@example.com")
+
+	_, err := sendTestEvent(context.Background(), http.DefaultClient, channel, time.Now())
+	require.ErrorContains(t, err, "email channels can't be tested")
+}
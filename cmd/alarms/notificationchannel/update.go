@@ -0,0 +1,79 @@
+package notificationchannel
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update [channel-id] --file [config-file]",
+	Short: "Update a notification channel from a YAML config file",
+	Long: `Update a notification channel from a YAML config file in the same format as create. Only the name, destination
+and subscription set in the file are changed; a subscription replaces the existing one as a whole.`,
+	Example: `# Update the destination and subscription of a channel
+omctl alarms notification-channel update [channel-id] --file channel.yaml
+
+# Rename a channel
+omctl alarms notification-channel update [channel-id] --name ops-alerts`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUpdate,
+}
+
+func init() {
+	updateCmd.Flags().StringP("file", "f", "", "Path to the YAML channel config file")
+	updateCmd.Flags().String("name", "", "New name of the channel, overriding the name in the config file")
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	channelID := args[0]
+	file, _ := cmd.Flags().GetString("file")
+	name, _ := cmd.Flags().GetString("name")
+
+	config := &channelConfig{}
+	if file != "" {
+		var err error
+		if config, err = loadChannelConfig(file); err != nil {
+			return err
+		}
+	}
+	if name != "" {
+		config.Name = name
+	}
+	if err := config.validateForUpdate(); err != nil {
+		return fmt.Errorf("invalid channel config: %v", err)
+	}
+
+	// Validate user is currently logged in
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	err = dataaccess.UpdateNotificationChannel(cmd.Context(), token, channelID, config.toUpdateRequest())
+	if err != nil {
+		return fmt.Errorf("failed to update notification channel: %v", err)
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == "json" {
+		channel, err := dataaccess.GetNotificationChannel(cmd.Context(), token, channelID)
+		if err != nil {
+			return fmt.Errorf("failed to describe notification channel %s: %v", channelID, err)
+		}
+		jsonData, err := json.MarshalIndent(channel, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	fmt.Printf("Successfully updated notification channel: %s\n", channelID)
+	return nil
+}
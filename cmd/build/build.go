@@ -52,6 +52,9 @@ omctl build --file docker-compose.yml --product-name "My Service" --release --re
 # Build service with compose spec and release the service as preferred with a release description
 omctl build --file docker-compose.yml --product-name "My Service" --release-as-preferred --release-description "v1.0.0-alpha"
 
+# Build service with compose spec, failing if any image is not pinned by digest
+omctl build --file docker-compose.yml --product-name "My Service" --require-pinned-images
+
 # Build service with compose spec interactively
 omctl build --file docker-compose.yml --product-name "My Service" --interactive
 
@@ -85,7 +88,7 @@ This command has an interactive mode. In this mode, you can choose to promote th
 
 // BuildCmd represents the build command
 var BuildCmd = &cobra.Command{
-	Use:          "build [--file=file] [--spec-type=spec-type] [--product-name=service-name] [--description=service-description] [--service-logo-url=service-logo-url] [--environment=environment-name] [--environment-type=environment-type] [--release] [--release-as-preferred] [--release-description=release-description][--interactive] [--image=image-url] [--image-registry-auth-username=username] [--image-registry-auth-password=password] [--env-var=\"key=var\"]",
+	Use:          "build [--file=file] [--spec-type=spec-type] [--product-name=service-name] [--description=service-description] [--service-logo-url=service-logo-url] [--environment=environment-name] [--environment-type=environment-type] [--release] [--release-as-preferred] [--release-description=release-description][--interactive] [--image=image-url] [--image-registry-auth-username=username] [--image-registry-auth-password=password] [--env-var=\"key=var\"] [--require-pinned-images]",
 	Short:        "Build Services from image, compose spec or service plan spec",
 	Long:         buildLong,
 	Example:      buildExample,
@@ -108,6 +111,7 @@ func init() {
 	BuildCmd.Flags().BoolP("interactive", "i", false, "Interactive mode")
 	BuildCmd.Flags().StringP("spec-type", "s", DockerComposeSpecType, "Spec type")
 	BuildCmd.Flags().BoolP("dry-run", "d", false, "Simulate building the service without actually creating resources")
+	BuildCmd.Flags().Bool("require-pinned-images", false, "Fail before uploading the compose spec if any service image is not pinned by digest (image@sha256:<digest>)")
 
 	BuildCmd.Flags().StringP("image", "", "", "Provide the complete image repository URL with the image name and tag (e.g., docker.io/namespace/my-image:v1.2)")
 	BuildCmd.Flags().StringArrayP("env-var", "", nil, "Used together with --image flag. Provide environment variables in the format --env-var key1=var1 --env-var key2=var2")
@@ -231,6 +235,10 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	requirePinnedImages, err := cmd.Flags().GetBool("require-pinned-images")
+	if err != nil {
+		return err
+	}
 
 	// Validate input arguments
 	if file == "" && imageUrl == "" {
@@ -251,6 +259,12 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if requirePinnedImages && specType != DockerComposeSpecType {
+		err := errors.New("--require-pinned-images is only supported with the DockerCompose spec type")
+		utils.PrintError(err)
+		return err
+	}
+
 	// Load the compose file
 	var fileData []byte
 	if file != "" {
@@ -409,6 +423,14 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Verify the images are pinned by digest before uploading the compose spec
+	if requirePinnedImages {
+		if err = verifyComposeImagesPinned(fileData); err != nil {
+			utils.HandleSpinnerError(spinner1, sm1, err)
+			return err
+		}
+	}
+
 	var undefinedResources map[string]string
 	ServiceID, EnvironmentID, ProductTierID, undefinedResources, err = buildService(
 		cmd.Context(),
@@ -3,7 +3,6 @@ package build
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"fmt"
 	"os"
 	"os/exec"
@@ -22,7 +21,6 @@ import (
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
-	openapiclient "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
 	"github.com/pkg/browser"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -53,23 +51,51 @@ omctl build-from-repo --dry-run
 # Build for multiple platforms
 omctl build-from-repo --platforms linux/amd64 --platforms linux/arm64
 
+# Build the images of up to 8 compose services at a time
+omctl build-from-repo --build-parallelism 8
+
 # Build with release description
 omctl build-from-repo --release-description "v1.0.0-alpha"
 
+# Reference the pushed images by their mutable tag instead of pinning them by digest
+omctl build-from-repo --pin-image-digests=false
+
+# List the stages that would run without running them
+omctl build-from-repo --plan
+
+# Resume from the stage that failed in the previous run
+omctl build-from-repo --resume
+
+# Only build and push the images and generate the compose spec
+omctl build-from-repo --to-stage generate-spec
+
+# Rerun the promotion to production using the service built in the previous run
+omctl build-from-repo --from-stage create-prod-env
+
 # Build using github token from environment variable (GH_PAT)
 set GH_PAT=ghp_xxxxxxxx
 omctl build-from-repo
+
+# Push images to Amazon ECR using the ECR credential helper
+omctl build-from-repo --registry 123456789012.dkr.ecr.us-east-1.amazonaws.com --registry-auth credential-helper --registry-credential-helper ecr-login --image-name-template "{{.Registry}}/{{.Repo}}{{if .Label}}-{{.Label}}{{end}}"
+
+# Push images to Google Artifact Registry using the credentials from the docker config
+omctl build-from-repo --registry us-docker.pkg.dev --registry-auth docker-config --image-name-template "{{.Registry}}/my-project/my-repo/{{.Repo}}{{if .Label}}-{{.Label}}{{end}}"
+
+# Push images to a private Harbor registry with the password read from stdin
+echo $HARBOR_PASSWORD | omctl build-from-repo --registry harbor.example.com --registry-auth password-stdin --registry-username ci-robot --image-name-template "{{.Registry}}/my-project/{{.Repo}}"
 "`
 	GitHubPATGenerateURL = "https://github.com/settings/tokens"
 	ComposeFileName      = "compose.yaml"
 	DefaultProdEnvName   = "Production"
 	defaultServiceName   = "default" // Default service name when no compose spec exists in the repo. It won't show up in the resulting image or compose spec. Only intermediate use.
+	gitBranchLabel       = "com.omnistrate.git.branch"
 )
 
 var BuildFromRepoCmd = &cobra.Command{
 	Use:          "build-from-repo",
 	Short:        "Build Service from Git Repository",
-	Long:         "This command helps to build service from git repository. Run this command from the root of the repository. Make sure you have the Dockerfile in the repository and have the Docker daemon running on your machine. By default, the service name will be the repository name, but you can specify a custom service name with the --product-name flag.\n\nYou can also skip specific stages of the build process using the --skip-* flags. For example, you can skip building the Docker image with --skip-docker-build, skip creating the service with --skip-service-build, skip environment promotion with --skip-environment-promotion, or skip SaaS portal initialization with --skip-saas-portal-init.\n\nFor testing purposes, use the --dry-run flag to only build the Docker image locally without pushing, skip service creation, and generate a local spec file with a '-dry-run' suffix. Note that --dry-run cannot be used together with any of the --skip-* flags as they are mutually exclusive.",
+	Long:         "This command helps to build service from git repository. Run this command from the root of the repository. Make sure you have the Dockerfile in the repository and have the Docker daemon running on your machine. By default, the service name will be the repository name, but you can specify a custom service name with the --product-name flag.\n\nBy default, images are pushed to GitHub Container Registry (ghcr.io) using a GitHub Personal Access Token. Use --registry, --registry-auth and --image-name-template to push the images to another registry such as Amazon ECR, Google Artifact Registry or a private registry.\n\nThe repository can be hosted on GitHub, GitLab, Bitbucket or a self-hosted git server. The git branch and commit being built are recorded as OCI labels on the images and in the release description.\n\nThe images of the compose services are built concurrently, up to --build-parallelism at a time, with the output of each build prefixed with the service name. Each image is built for all --platforms with a single buildx invocation. If a build fails, the remaining builds are cancelled and the end of the failing build's log is reported.\n\nThe pushed images are referenced by digest (repo@sha256:<digest>) in the compose spec sent to Omnistrate, so a later push to the same tag doesn't change what a released version deploys. The digest each tag pointed to is recorded in the release description. Use --pin-image-digests=false to reference the images by tag instead.\n\nYou can also skip specific stages of the build process using the --skip-* flags. For example, you can skip building the Docker image with --skip-docker-build, skip creating the service with --skip-service-build, skip environment promotion with --skip-environment-promotion, or skip SaaS portal initialization with --skip-saas-portal-init.\n\nThe build runs in stages: docker-build, generate-spec, build-service, create-prod-env, promote, set-default and saas-portal-init. The result and outputs of each stage, such as image digests, the service ID and environment IDs, are recorded in a local checkpoint file. If a stage fails, rerun with --resume to continue from that stage. Use --from-stage and --to-stage to run a range of stages, and --plan to list the stages that would run.\n\nFor testing purposes, use the --dry-run flag to only build the Docker image locally without pushing, skip service creation, and generate a local spec file with a '-dry-run' suffix. Note that --dry-run cannot be used together with any of the --skip-* flags as they are mutually exclusive.",
 	Example:      buildFromRepoExample,
 	RunE:         runBuildFromRepo,
 	SilenceUsage: true,
@@ -99,8 +125,28 @@ func init() {
 	// Platform flag
 	BuildFromRepoCmd.Flags().StringArray("platforms", []string{"linux/amd64"}, "Specify the platforms to build for. Use the format: --platforms linux/amd64 --platforms linux/arm64. Default is linux/amd64.")
 
+	// Build parallelism flag
+	BuildFromRepoCmd.Flags().Int("build-parallelism", DefaultBuildParallelism, "Maximum number of Docker images to build and push concurrently. The output of each build is prefixed with the compose service name")
+
 	// Release description flag
-	BuildFromRepoCmd.Flags().String("release-description", "", "Provide a description for the release version")
+	BuildFromRepoCmd.Flags().String("release-description", "", "Provide a description for the release version. The git branch and commit the service is built from, and the digest each pushed image tag pointed to, are appended automatically")
+
+	// Image digest flag
+	BuildFromRepoCmd.Flags().Bool("pin-image-digests", true, "Reference the pushed images by digest (repo@sha256:<digest>) in the compose spec sent to Omnistrate, so a later push to the same tag doesn't change what a released version deploys")
+
+	// Pipeline stage flags
+	BuildFromRepoCmd.Flags().Bool("resume", false, "Resume from the first stage that didn't complete in the previous run, using the outputs recorded in the checkpoint file")
+	BuildFromRepoCmd.Flags().String("from-stage", "", fmt.Sprintf("Run the stages starting from this stage, using the outputs of earlier stages recorded in the checkpoint file. Options: %s", strings.Join(buildFromRepoStageNames(), ", ")))
+	BuildFromRepoCmd.Flags().String("to-stage", "", fmt.Sprintf("Stop after this stage. Options: %s", strings.Join(buildFromRepoStageNames(), ", ")))
+	BuildFromRepoCmd.Flags().Bool("plan", false, "List the stages that would run, with the reason for any stage that would be skipped, and exit without running them")
+	BuildFromRepoCmd.Flags().String("checkpoint-file", "", "Path to the checkpoint file recording the stage results and outputs. Defaults to a file per repository in the omnistrate-ctl config directory")
+
+	// Container registry flags
+	BuildFromRepoCmd.Flags().String("registry", DefaultRegistry, "Container registry to push the images to, e.g. ghcr.io, <account-id>.dkr.ecr.<region>.amazonaws.com, <region>-docker.pkg.dev or a private registry host")
+	BuildFromRepoCmd.Flags().String("registry-auth", "", "How to authenticate with the container registry. Options: 'github-pat', 'docker-config', 'password-stdin', 'credential-helper'. Defaults to 'github-pat' for ghcr.io and 'docker-config' for other registries")
+	BuildFromRepoCmd.Flags().String("registry-username", "", "Username for the container registry. Required with --registry-auth password-stdin")
+	BuildFromRepoCmd.Flags().String("registry-credential-helper", "", "Name of the docker credential helper to use, e.g. 'ecr-login' or 'gcloud'. The docker-credential-<name> binary must be in PATH. Required with --registry-auth credential-helper")
+	BuildFromRepoCmd.Flags().String("image-name-template", DefaultImageNameTemplate, "Go template for the image repository URL of each service. Available fields: .Registry, .Owner, .Repo, .Service and .Label (the distinguishing directory of the service's Dockerfile when the repo builds multiple images)")
 
 	// Deprecate the old --service-name flag
 	if err := BuildFromRepoCmd.Flags().MarkDeprecated("service-name", "use --product-name instead"); err != nil {
@@ -198,6 +244,89 @@ func runBuildFromRepo(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Get build-parallelism flag
+	buildParallelism, err := cmd.Flags().GetInt("build-parallelism")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Get pin-image-digests flag
+	pinImageDigests, err := cmd.Flags().GetBool("pin-image-digests")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Get pipeline stage flags
+	resume, err := cmd.Flags().GetBool("resume")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	fromStage, err := cmd.Flags().GetString("from-stage")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	toStage, err := cmd.Flags().GetString("to-stage")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	plan, err := cmd.Flags().GetBool("plan")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	checkpointFile, err := cmd.Flags().GetString("checkpoint-file")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Get the platforms defined in flag
+	platforms, err := cmd.Flags().GetStringArray("platforms")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Get container registry flags
+	registry, err := cmd.Flags().GetString("registry")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	registryAuth, err := cmd.Flags().GetString("registry-auth")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	registryUsername, err := cmd.Flags().GetString("registry-username")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	registryCredentialHelper, err := cmd.Flags().GetString("registry-credential-helper")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	imageNameTemplate, err := cmd.Flags().GetString("image-name-template")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
 	// Get the service name from flag
 	serviceName, err := cmd.Flags().GetString("service-name")
 	if err != nil {
@@ -222,6 +351,12 @@ func runBuildFromRepo(cmd *cobra.Command, args []string) error {
 		serviceName = productName
 	}
 
+	if buildParallelism < 1 {
+		err = errors.New("--build-parallelism must be at least 1")
+		utils.PrintError(err)
+		return err
+	}
+
 	// Check for incompatible flag combinations
 	if dryRun {
 		// If dry-run is set, other skip flags should not be set
@@ -231,12 +366,24 @@ func runBuildFromRepo(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
+		if resume {
+			err = errors.New("--dry-run flag is not compatible with --resume since dry runs don't record a checkpoint")
+			utils.PrintError(err)
+			return err
+		}
+
 		// In dry-run mode, we implicitly skip these steps
 		skipServiceBuild = true
 		skipEnvironmentPromotion = true
 		skipSaasPortalInit = true
 	}
 
+	if resume && fromStage != "" {
+		err = errors.New("only one of --resume or --from-stage can be provided")
+		utils.PrintError(err)
+		return err
+	}
+
 	// Convert the file path to an absolute path
 	file, err = filepath.Abs(file)
 	if err != nil {
@@ -281,118 +428,114 @@ func runBuildFromRepo(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Initialize the spinner manager
-	var sm ysmrr.SpinnerManager
-	var spinner *ysmrr.Spinner
-	sm = ysmrr.NewSpinnerManager()
-	sm.Start()
-
-	// Step 0: Validate user is currently logged in
-	spinner = sm.AddSpinner("Checking if user is logged in")
-	time.Sleep(1 * time.Second) // Add a delay to show the spinner
-	spinner.Complete()
-	sm.Stop()
+	// Validate the container registry settings
+	registry = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://"), "/")
+	if registry == "" {
+		err = errors.New("registry cannot be empty")
+		utils.PrintError(err)
+		return err
+	}
 
-	token, err := common.GetTokenWithLogin()
+	authProvider, err := newRegistryAuthProvider(registryAuth, registry, registryUsername, registryCredentialHelper, resetPAT)
 	if err != nil {
 		utils.PrintError(err)
 		return err
 	}
 
-	sm = ysmrr.NewSpinnerManager()
-	sm.Start()
-
-	// Only check for gh if we're not skipping Docker build
-	if !skipDockerBuild {
-		spinner = sm.AddSpinner("Checking if gh installed")
-		time.Sleep(1 * time.Second) // Add a delay to show the spinner
-		err = exec.Command("gh", "version").Run()
-		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
-			return err
-		}
-		spinner.UpdateMessage("Checking if gh installed: Yes")
-		spinner.Complete()
+	opts := buildFromRepoOptions{
+		envVars:                  envVars,
+		deploymentType:           deploymentType,
+		awsAccountID:             awsAccountID,
+		gcpProjectID:             gcpProjectID,
+		gcpProjectNumber:         gcpProjectNumber,
+		file:                     file,
+		serviceName:              serviceName,
+		releaseDescription:       releaseDescription,
+		pinImageDigests:          pinImageDigests,
+		platforms:                platforms,
+		buildParallelism:         buildParallelism,
+		dryRun:                   dryRun,
+		skipDockerBuild:          skipDockerBuild,
+		skipServiceBuild:         skipServiceBuild,
+		skipEnvironmentPromotion: skipEnvironmentPromotion,
+		skipSaasPortalInit:       skipSaasPortalInit,
+		registry:                 registry,
+		imageNameTemplate:        imageNameTemplate,
+		authProvider:             authProvider,
+	}
+
+	pipeline := &buildFromRepoPipeline{
+		opts:            opts,
+		dockerfilePaths: make(map[string]string),
 	}
 
+	// Initialize the spinner manager
+	var spinner *ysmrr.Spinner
+	pipeline.sm = ysmrr.NewSpinnerManager()
+	pipeline.sm.Start()
+
 	// Step 1: Check if the user is in the root of the repository
-	spinner = sm.AddSpinner("Checking if user is in the root of the repository")
+	spinner = pipeline.sm.AddSpinner("Checking if user is in the root of the repository")
 	time.Sleep(1 * time.Second) // Add a delay to show the spinner
 	cwd, err := os.Getwd()
 	if err != nil {
-		utils.HandleSpinnerError(spinner, sm, err)
+		utils.HandleSpinnerError(spinner, pipeline.sm, err)
 		return err
 	}
 	if _, err := os.Stat(filepath.Join(cwd, ".git")); os.IsNotExist(err) {
-		utils.HandleSpinnerError(spinner, sm, errors.New("you are not in the root of a git repository"))
+		utils.HandleSpinnerError(spinner, pipeline.sm, errors.New("you are not in the root of a git repository"))
 		return err
 	}
 	spinner.UpdateMessage("Checking if user is in the root of the repository: Yes")
 	spinner.Complete()
 
-	rootDir := cwd
+	pipeline.rootDir = cwd
 
 	// Step 2: Retrieve the repository name
-	spinner = sm.AddSpinner("Retrieving repository name")
+	spinner = pipeline.sm.AddSpinner("Retrieving repository name")
 	time.Sleep(1 * time.Second) // Add a delay to show the spinner
-	output, err := exec.Command("sh", "-c", `git config --get remote.origin.url | sed -E 's/:([^\/])/\/\1/g' | sed -e 's/ssh\/\/\///g' | sed -e 's/git@/https:\/\//g'`).Output()
+	pipeline.gitRepo, err = utils.ReadGitRepository(cwd)
 	if err != nil {
-		utils.HandleSpinnerError(spinner, sm, err)
+		utils.HandleSpinnerError(spinner, pipeline.sm, err)
 		return err
 	}
-	repoURL := strings.TrimSpace(string(output))
-	repoName := filepath.Base(repoURL)
-	repoOwner := filepath.Base(filepath.Dir(repoURL))
-	repoName = strings.TrimSuffix(repoName, ".git") // Extract repo name
-	spinner.UpdateMessage(fmt.Sprintf("Retrieving repository name: %s/%s", repoOwner, repoName))
+	spinner.UpdateMessage(fmt.Sprintf("Retrieving repository name: %s/%s/%s (%s)", pipeline.gitRepo.Remote.Host, pipeline.gitRepo.Remote.Owner, pipeline.gitRepo.Remote.Repo, describeGitRevision(pipeline.gitRepo)))
 	spinner.Complete()
 
 	// Step 3: Check if there exists a compose spec in the repository
-	spinner = sm.AddSpinner("Checking if there exists a compose spec in the repository")
+	spinner = pipeline.sm.AddSpinner("Checking if there exists a compose spec in the repository")
 	time.Sleep(1 * time.Second) // Add a delay to show the spinner
-	var composeSpecExists bool
 	if _, err = os.Stat(file); os.IsNotExist(err) {
-		composeSpecExists = false
+		pipeline.composeSpecExists = false
 	} else {
-		composeSpecExists = true
+		pipeline.composeSpecExists = true
 	}
 	yesOrNo := "No"
-	if composeSpecExists {
+	if pipeline.composeSpecExists {
 		yesOrNo = "Yes"
 	}
 	spinner.UpdateMessage(fmt.Sprintf("Checking if there exists a compose spec in the repository: %s", yesOrNo))
 	spinner.Complete()
 
-	var fileData []byte
-	var parsedYaml map[string]interface{}
-	var project *types.Project
-	dockerfilePaths := make(map[string]string)        // service -> dockerfile path
-	versionTaggedImageUrls := make(map[string]string) // service -> image url with digest tag
-	var pat string
-	var ghUsername string
-
-	composeSpecHasBuildContext := false
-	if composeSpecExists {
-		// Load the compose file
-		if _, err = os.Stat(file); os.IsNotExist(err) {
-			utils.PrintError(err)
-			return err
-		}
+	if pipeline.composeSpecExists {
+		var parsedYaml map[string]interface{}
 
-		fileData, err = os.ReadFile(file)
+		pipeline.fileData, err = os.ReadFile(file)
 		if err != nil {
+			utils.HandleSpinnerError(spinner, pipeline.sm, err)
 			return err
 		}
 
 		// Load the YAML content
-		parsedYaml, err = loader.ParseYAML(fileData)
+		parsedYaml, err = loader.ParseYAML(pipeline.fileData)
 		if err != nil {
 			err = errors.Wrap(err, "failed to parse YAML content")
+			utils.HandleSpinnerError(spinner, pipeline.sm, err)
 			return err
 		}
 
 		// Decode spec YAML into a compose project
-		if project, err = loader.LoadWithContext(context.Background(), types.ConfigDetails{
+		if pipeline.project, err = loader.LoadWithContext(context.Background(), types.ConfigDetails{
 			ConfigFiles: []types.ConfigFile{
 				{
 					Config: parsedYaml,
@@ -400,758 +543,123 @@ func runBuildFromRepo(cmd *cobra.Command, args []string) error {
 			},
 		}); err != nil {
 			err = errors.Wrap(err, "invalid compose")
+			utils.HandleSpinnerError(spinner, pipeline.sm, err)
 			return err
 		}
 
-		for _, service := range project.Services {
+		for _, service := range pipeline.project.Services {
 			if service.Build != nil {
-				composeSpecHasBuildContext = true
+				pipeline.composeSpecHasBuildContext = true
 
 				absContextPath, err := filepath.Abs(service.Build.Context)
 				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
+					utils.HandleSpinnerError(spinner, pipeline.sm, err)
 					return err
 				}
 
-				dockerfilePaths[service.Name] = filepath.Join(absContextPath, service.Build.Dockerfile)
+				pipeline.dockerfilePaths[service.Name] = filepath.Join(absContextPath, service.Build.Dockerfile)
 			}
 		}
 	} else {
-		dockerfilePaths[defaultServiceName], err = filepath.Abs("Dockerfile")
+		pipeline.dockerfilePaths[defaultServiceName], err = filepath.Abs("Dockerfile")
 		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
+			utils.HandleSpinnerError(spinner, pipeline.sm, err)
 			return err
 		}
 	}
 
-	dockerfilePathsArr := make([]string, 0)
-	for _, dockerfilePath := range dockerfilePaths {
-		dockerfilePathsArr = append(dockerfilePathsArr, dockerfilePath)
-	}
-
-	if !composeSpecExists || composeSpecHasBuildContext {
-		// Skip Docker build if flag is set
-		if skipDockerBuild {
-			spinner = sm.AddSpinner("Skipping Docker build (--skip-docker-build flag is set)")
-			spinner.Complete()
-
-			// We still need to get the GitHub username for the compose spec
-			spinner = sm.AddSpinner("Getting GitHub username for compose spec")
-			pat, err = config.LookupGitHubPersonalAccessToken()
-			if err != nil && !errors.As(err, &config.ErrGitHubPATNotFound) {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-
-			if !errors.As(err, &config.ErrGitHubPATNotFound) {
-				if config.IsGithubTokenEnvVarConfigured() {
-					ghUsername = config.GithubTokenUserName
-				} else {
-					ghUsernameOutput, err := exec.Command("gh", "api", "user", "-q", ".login").Output()
-					if err != nil {
-						utils.HandleSpinnerError(spinner, sm, err)
-						return err
-					}
-					ghUsername = strings.TrimSpace(string(ghUsernameOutput))
-				}
-				spinner.UpdateMessage(fmt.Sprintf("Getting GitHub username for compose spec: %s", ghUsername))
-			} else {
-				spinner.UpdateMessage("GitHub PAT not found, will prompt if needed later")
-			}
-			spinner.Complete()
-
-			// Set placeholder image URLs if needed
-			for service := range dockerfilePaths {
-				label := strings.ToLower(utils.GetFirstDifferentSegmentInFilePaths(dockerfilePaths[service], dockerfilePathsArr))
-				var imageUrl string
-				if label == "" {
-					imageUrl = fmt.Sprintf("ghcr.io/%s/%s", strings.ToLower(repoOwner), repoName)
-				} else {
-					imageUrl = fmt.Sprintf("ghcr.io/%s/%s-%s", strings.ToLower(repoOwner), repoName, label)
-				}
-				versionTaggedImageUrls[service] = fmt.Sprintf("%s:latest", imageUrl)
-			}
-		} else {
-			// Step 4: Check if the Dockerfile exists
-			for _, dockerfilePath := range dockerfilePaths {
-				spinner = sm.AddSpinner(fmt.Sprintf("Checking if %s exists in the repository", dockerfilePath))
-				time.Sleep(1 * time.Second) // Add a delay to show the spinner
-
-				if _, err = os.Stat(dockerfilePath); os.IsNotExist(err) {
-					utils.HandleSpinnerError(spinner, sm, errors.New(fmt.Sprintf("%s not found in the repository", dockerfilePath)))
-					return err
-				}
-
-				spinner.UpdateMessage(fmt.Sprintf("Checking if %s exists in the repository: Yes", dockerfilePath))
-				spinner.Complete()
-			}
-
-			// Step 5: Check if Docker is installed
-			spinner = sm.AddSpinner("Checking if Docker installed")
-			time.Sleep(1 * time.Second)                   // Add a delay to show the spinner
-			err = exec.Command("docker", "version").Run() // Simple way to check if Docker is available
-			if err != nil {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-			spinner.UpdateMessage("Checking if Docker installed: Yes")
-			spinner.Complete()
-
-			// Step 6: Check if the Docker daemon is running
-			spinner = sm.AddSpinner("Checking if Docker daemon is running")
-			time.Sleep(1 * time.Second)                // Add a delay to show the spinner
-			err = exec.Command("docker", "info").Run() // Simple way to check if Docker is available
-			if err != nil {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-			spinner.UpdateMessage("Checking if Docker daemon is running: Yes")
-			spinner.Complete()
-
-			// Step 7: Check if there is an existing GitHub pat
-			sm, pat, err = getOrCreatePAT(sm, resetPAT)
-			if err != nil {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-
-			// Step 8: Retrieve the GitHub username
-			spinner = sm.AddSpinner("Retrieving GitHub username")
-			time.Sleep(1 * time.Second) // Add a delay to show the spinner
-			if config.IsGithubTokenEnvVarConfigured() {
-				ghUsername = config.GithubTokenUserName
-			} else {
-				ghUsernameOutput, err := exec.Command("gh", "api", "user", "-q", ".login").Output()
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-				ghUsername = strings.TrimSpace(string(ghUsernameOutput))
-			}
-			spinner.UpdateMessage(fmt.Sprintf("Retrieving GitHub username: %s", ghUsername))
-			spinner.Complete()
-
-			// Step 9: Label the docker image with the repository name
-			spinner = sm.AddSpinner("Labeling Docker image with the repository name")
-			for _, dockerfilePath := range dockerfilePaths {
-				// Read the Dockerfile
-				var dockerfileData []byte
-				dockerfileData, err = os.ReadFile(dockerfilePath)
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-
-				// Check if the Dockerfile already has the label
-				if strings.Contains(string(dockerfileData), "LABEL org.opencontainers.image.source") {
-					spinner.UpdateMessage("Labeling Docker image with the repository name: Already labeled")
-				} else {
-					// Append the label to the Dockerfile
-					dockerfileData = append(dockerfileData, []byte(fmt.Sprintf("\nLABEL org.opencontainers.image.source=\"https://github.com/%s/%s\"\n", repoOwner, repoName))...)
-
-					// Write the Dockerfile back
-					err = os.WriteFile(dockerfilePath, dockerfileData, 0600)
-					if err != nil {
-						utils.HandleSpinnerError(spinner, sm, err)
-						return err
-					}
-
-					spinner.UpdateMessage(fmt.Sprintf("Labeling Docker image with the repository name: %s/%s", repoOwner, repoName))
-				}
-			}
-
-			spinner.Complete()
-
-			// Step 10: Login to GitHub Container Registry
-			spinner = sm.AddSpinner("Logging in to ghcr.io")
-			spinner.Complete()
-			sm.Stop()
-			loginCmd := exec.Command("docker", "login", "ghcr.io", "--username", ghUsername, "--password", pat)
-
-			// Redirect stdout and stderr to the terminal
-			loginCmd.Stdout = os.Stdout
-			loginCmd.Stderr = os.Stderr
-
-			fmt.Printf("Invoking 'docker login ghcr.io --username %s --password ******'...\n", ghUsername)
-			err = loginCmd.Run()
-			if err != nil {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-
-			sm = ysmrr.NewSpinnerManager()
-			sm.Start()
-
-			for service, dockerfilePath := range dockerfilePaths {
-				// Set current working directory to the service context
-				err = os.Chdir(filepath.Dir(dockerfilePath))
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-
-				// Step 11: Build docker image
-				label := strings.ToLower(utils.GetFirstDifferentSegmentInFilePaths(dockerfilePath, dockerfilePathsArr))
-				var imageUrl string
-				if label == "" {
-					imageUrl = fmt.Sprintf("ghcr.io/%s/%s", strings.ToLower(repoOwner), repoName)
-				} else {
-					imageUrl = fmt.Sprintf("ghcr.io/%s/%s-%s", strings.ToLower(repoOwner), repoName, label)
-				}
-
-				spinner = sm.AddSpinner(fmt.Sprintf("Building Docker image: %s", imageUrl))
-				spinner.Complete()
-				sm.Stop()
-
-				// Get the platforms defined in flag
-				platformsFlag, err := cmd.Flags().GetStringArray("platforms")
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-
-				// Join the platforms list with comma as separator
-				platformsStr := strings.Join(platformsFlag, ",")
-
-				buildCmd := exec.Command("docker", "buildx", "build", "--pull", "--platform", platformsStr, ".", "-f", dockerfilePath, "-t", imageUrl, "--load")
-
-				// Redirect stdout and stderr to the terminal
-				buildCmd.Stdout = os.Stdout
-				buildCmd.Stderr = os.Stderr
-
-				fmt.Printf("Invoking 'docker buildx build --pull --platform %s . -f %s -t %s --load'...\n", platformsStr, dockerfilePath, imageUrl)
-				err = buildCmd.Run()
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-
-				sm = ysmrr.NewSpinnerManager()
-				sm.Start()
-
-				// In dry-run mode, skip pushing to registry and use local image tag
-				if dryRun {
-					spinner = sm.AddSpinner("Dry run: Using local image tag (skipping push)")
-					spinner.Complete()
-					versionTaggedImageUrls[service] = fmt.Sprintf("%s:latest", imageUrl)
-					continue
-				}
-
-				// Step 12: Push docker image to GitHub Container Registry
-				spinner = sm.AddSpinner("Pushing Docker image to GitHub Container Registry")
-				spinner.Complete()
-				sm.Stop()
-				pushCmd := exec.Command("docker", "push", imageUrl)
-
-				// Redirect stdout and stderr to the terminal
-				pushCmd.Stdout = os.Stdout
-				pushCmd.Stderr = os.Stderr
-
-				fmt.Printf("Invoking 'docker push %s'...\n", imageUrl)
-				err = pushCmd.Run()
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-
-				sm = ysmrr.NewSpinnerManager()
-				sm.Start()
-
-				// Retrieve the digest
-				spinner = sm.AddSpinner("Retrieving the digest for the image")
-				digestCmd := exec.Command("docker", "buildx", "imagetools", "inspect", imageUrl)
-
-				var digestOutput []byte
-				digestOutput, err = digestCmd.Output()
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-
-				// Convert output to string and search for the Digest line
-				var digest string
-				digestOutputStr := string(digestOutput)
-				for _, line := range strings.Split(digestOutputStr, "\n") {
-					if strings.Contains(line, "Digest:") {
-						parts := strings.Split(line, ":")
-						if len(parts) < 3 {
-							utils.HandleSpinnerError(spinner, sm, errors.New("unable to retrieve the digest"))
-							return err
-						}
-						digest = fmt.Sprintf("sha-%s", strings.TrimSpace(strings.Split(line, ":")[2]))
-						break
-					}
-				}
-
-				spinner.Complete()
-				sm.Stop()
-
-				fmt.Printf("Retrieved digest: %s\n", digest)
-
-				sm = ysmrr.NewSpinnerManager()
-				sm.Start()
-
-				imageUrlWithDigestTag := fmt.Sprintf("%s:%s", imageUrl, digest)
-				versionTaggedImageUrls[service] = imageUrlWithDigestTag
-
-				// Tag the image with the digest
-				spinner = sm.AddSpinner("Tagging the image with the digest")
-				spinner.Complete()
-				sm.Stop()
-
-				tagCmd := exec.Command("docker", "tag", imageUrl, imageUrlWithDigestTag)
-
-				tagCmd.Stdout = os.Stdout
-				tagCmd.Stderr = os.Stderr
-
-				fmt.Printf("Invoking 'docker tag %s %s'...\n", imageUrl, imageUrlWithDigestTag)
-				if err = tagCmd.Run(); err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-
-				sm = ysmrr.NewSpinnerManager()
-				sm.Start()
-
-				// Push the image with the digest tag
-				spinner = sm.AddSpinner("Pushing the image with the digest tag")
-				spinner.Complete()
-				sm.Stop()
-
-				pushCmd = exec.Command("docker", "push", imageUrlWithDigestTag)
-
-				// Redirect stdout and stderr to the terminal
-				pushCmd.Stdout = os.Stdout
-				pushCmd.Stderr = os.Stderr
-
-				fmt.Printf("Invoking 'docker push %s'...\n", imageUrlWithDigestTag)
-				err = pushCmd.Run()
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-
-				sm = ysmrr.NewSpinnerManager()
-				sm.Start()
-			}
-
-			// Change back to the root directory
-			err = os.Chdir(rootDir)
-			if err != nil {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-		}
-
-		// Step 13: Generate compose spec from the Docker image
-		spinner = sm.AddSpinner("Generating compose spec from the Docker image")
-		if !composeSpecExists {
-			// Parse the environment variables
-			var formattedEnvVars []openapiclient.EnvironmentVariable
-			for _, envVar := range envVars {
-				if envVar == "[]" {
-					continue
-				}
-				envVarParts := strings.Split(envVar, "=")
-				if len(envVarParts) != 2 {
-					err = errors.New("invalid environment variable format")
-					utils.PrintError(err)
-					return err
-				}
-				formattedEnvVars = append(formattedEnvVars, openapiclient.EnvironmentVariable{
-					Key:   envVarParts[0],
-					Value: envVarParts[1],
-				})
-			}
-
-			// Generate compose spec from image
-			generateComposeSpecRequest := openapiclient.GenerateComposeSpecFromContainerImageRequest2{
-				ImageRegistry:        "ghcr.io",
-				Image:                strings.TrimPrefix(versionTaggedImageUrls[defaultServiceName], "ghcr.io/"),
-				Username:             utils.ToPtr(ghUsername),
-				Password:             utils.ToPtr(pat),
-				EnvironmentVariables: formattedEnvVars,
-			}
-
-			var generateComposeSpecRes *openapiclient.GenerateComposeSpecFromContainerImageResult
-			generateComposeSpecRes, err = dataaccess.GenerateComposeSpecFromContainerImage(cmd.Context(), token, generateComposeSpecRequest)
-			if err != nil {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-
-			// Decode the base64 encoded file content
-			fileData, err = base64.StdEncoding.DecodeString(generateComposeSpecRes.FileContent)
-			if err != nil {
-				utils.PrintError(err)
-				return err
-			}
-
-			// Replace the actual PAT with ${{ secrets.GitHubPAT }}
-			fileData = []byte(strings.ReplaceAll(string(fileData), pat, "${{ secrets.GitHubPAT }}"))
-
-			// Replace the image tag with build tag
-			fileData = []byte(strings.ReplaceAll(string(fileData), fmt.Sprintf("image: %s", versionTaggedImageUrls[defaultServiceName]), "build:\n      context: .\n      dockerfile: Dockerfile"))
-
-			// Append the deployment section to the compose spec
-			switch deploymentType {
-			case "hosted":
-				fileData = append(fileData, []byte("  deployment:\n")...)
-				fileData = append(fileData, []byte("    hostedDeployment:\n")...)
-			case "byoa":
-				fileData = append(fileData, []byte("  deployment:\n")...)
-				fileData = append(fileData, []byte("    byoaDeployment:\n")...)
-			}
-
-			if deploymentType != "" {
-				if awsAccountID != "" {
-					fileData = append(fileData, []byte(fmt.Sprintf("      AwsAccountId: '%s'\n", awsAccountID))...)
-					awsBootstrapRoleAccountARN := fmt.Sprintf("arn:aws:iam::%s:role/omnistrate-bootstrap-role", awsAccountID)
-					fileData = append(fileData, []byte(fmt.Sprintf("      AwsBootstrapRoleAccountArn: '%s'\n", awsBootstrapRoleAccountARN))...)
-				}
-				if gcpProjectID != "" {
-					fileData = append(fileData, []byte(fmt.Sprintf("      GcpProjectId: '%s'\n", gcpProjectID))...)
-					fileData = append(fileData, []byte(fmt.Sprintf("      GcpProjectNumber: '%s'\n", gcpProjectNumber))...)
-
-					// Get organization id
-					user, err := dataaccess.DescribeUser(cmd.Context(), token)
-					if err != nil {
-						utils.HandleSpinnerError(spinner, sm, err)
-						return err
-					}
-
-					gcpServiceAccountEmail := fmt.Sprintf("bootstrap-%s@%s.iam.gserviceaccount.com", *user.OrgId, gcpProjectID)
-					fileData = append(fileData, []byte(fmt.Sprintf("      GcpServiceAccountEmail: '%s'\n", gcpServiceAccountEmail))...)
-				}
-			}
-
-			// Write the compose spec to a file
-			err = os.WriteFile(file, fileData, 0600)
-			if err != nil {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-			spinner.UpdateMessage(fmt.Sprintf("Generating compose spec from the Docker image: saved to %s", file))
-			spinner.Complete()
-		} else {
-			// Append the deployment section to the compose spec if it doesn't exist
-			if !strings.Contains(string(fileData), "deployment:") {
-				switch deploymentType {
-				case "hosted":
-					fileData = append(fileData, []byte("  deployment:\n")...)
-					fileData = append(fileData, []byte("    hostedDeployment:\n")...)
-				case "byoa":
-					fileData = append(fileData, []byte("  deployment:\n")...)
-					fileData = append(fileData, []byte("    byoaDeployment:\n")...)
-				}
-
-				if deploymentType != "" {
-					if awsAccountID != "" {
-						fileData = append(fileData, []byte(fmt.Sprintf("      AwsAccountId: '%s'\n", awsAccountID))...)
-						awsBootstrapRoleAccountARN := fmt.Sprintf("arn:aws:iam::%s:role/omnistrate-bootstrap-role", awsAccountID)
-						fileData = append(fileData, []byte(fmt.Sprintf("      AwsBootstrapRoleAccountArn: '%s'\n", awsBootstrapRoleAccountARN))...)
-					}
-					if gcpProjectID != "" {
-						fileData = append(fileData, []byte(fmt.Sprintf("      GcpProjectId: '%s'\n", gcpProjectID))...)
-						fileData = append(fileData, []byte(fmt.Sprintf("      GcpProjectNumber: '%s'\n", gcpProjectNumber))...)
-
-						// Get organization id
-						user, err := dataaccess.DescribeUser(cmd.Context(), token)
-						if err != nil {
-							utils.HandleSpinnerError(spinner, sm, err)
-							return err
-						}
-
-						gcpServiceAccountEmail := fmt.Sprintf("bootstrap-%s@%s.iam.gserviceaccount.com", *user.OrgId, gcpProjectID)
-						fileData = append(fileData, []byte(fmt.Sprintf("      GcpServiceAccountEmail: '%s'\n", gcpServiceAccountEmail))...)
-					}
-				}
-			}
-
-			// Append the image registry attributes to the compose spec if it doesn't exist
-			if !strings.Contains(string(fileData), "x-omnistrate-image-registry-attributes") {
-				fileData = append(fileData, []byte(fmt.Sprintf(`
-x-omnistrate-image-registry-attributes:
-  ghcr.io:
-    auth:
-      password: ${{ secrets.GitHubPAT }}
-      username: %s
-`, ghUsername))...)
-			}
-
-			// Write the compose spec to a file
-			err = os.WriteFile(file, fileData, 0600)
-			if err != nil {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-			spinner.UpdateMessage(fmt.Sprintf("Generating compose spec from the Docker image: saved to %s", file))
-			spinner.Complete()
-		}
-	}
-
-	// Step 13: Get or create a GitHub PAT if needed
-	if strings.Contains(string(fileData), "${{ secrets.GitHubPAT }}") && pat == "" {
-		sm, pat, err = getOrCreatePAT(sm, resetPAT)
-		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
-			return err
-		}
-	}
-
-	// Step 14: Render the compose file: variable interpolation (if env_file appears), ${{ secrets.GitHubPAT }} replacement, build context replacement
-	spinner = sm.AddSpinner("Rendering compose spec")
-
-	if strings.Contains(string(fileData), "env_file:") {
-		fileData, err = RenderEnvFileAndInterpolateVariables(fileData, rootDir, file, sm, spinner)
-		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
-			return err
-		}
+	// Load the checkpoint of the previous run when resuming or starting from a later stage
+	if checkpointFile == "" {
+		checkpointFile = defaultCheckpointPath(pipeline.rootDir)
+	} else if checkpointFile, err = filepath.Abs(checkpointFile); err != nil {
+		utils.HandleSpinnerError(spinner, pipeline.sm, err)
+		return err
 	}
-
-	// Render the ${{ secrets.GitHubPAT }} in the compose file if needed
-	if strings.Contains(string(fileData), "${{ secrets.GitHubPAT }}") {
-		fileData = []byte(strings.ReplaceAll(string(fileData), "${{ secrets.GitHubPAT }}", pat))
+	pipeline.checkpoint, err = openCheckpoint(checkpointFile, pipeline.rootDir, pipeline.gitRepo.CommitSHA, resume, fromStage)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, pipeline.sm, err)
+		return err
 	}
 
-	// Render build context sections into image fields in the compose file if needed
-	if composeSpecHasBuildContext {
-		dockerPathsToImageUrls := make(map[string]string)
-		for service, imageUrl := range versionTaggedImageUrls {
-			dockerPathsToImageUrls[dockerfilePaths[service]] = imageUrl
-		}
-		fileData = []byte(utils.ReplaceBuildContext(string(fileData), dockerPathsToImageUrls))
+	stages := buildFromRepoStages()
+	start, end, err := selectStages(stages, pipeline.checkpoint, resume, fromStage, toStage)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, pipeline.sm, err)
+		return err
 	}
 
-	spinner.UpdateMessage("Rendering compose spec: complete")
-	spinner.Complete()
-
-	// Step 15: Building service from the compose spec
-	spinner = sm.AddSpinner("Building service from the compose spec")
-
-	// If we're in dry-run mode, save the compose spec to a file with '-dry-run' suffix
-	if dryRun {
-		// Get the file extension
-		fileExt := filepath.Ext(file)
-		baseName := file[:len(file)-len(fileExt)]
-		dryRunFile := fmt.Sprintf("%s-dry-run%s", baseName, fileExt)
-
-		// Write the compose spec to the dry-run file
-		err = os.WriteFile(dryRunFile, fileData, 0600)
-		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
-			return err
-		}
-
-		spinner.UpdateMessage(fmt.Sprintf("Dry run: Wrote compose spec to %s", dryRunFile))
-		spinner.Complete()
-		sm.Stop()
-		fmt.Printf("Dry run completed. Final compose spec written to %s\n", dryRunFile)
-		return nil
+	if plan {
+		pipeline.sm.Stop()
+		fmt.Println()
+		return pipeline.printPlan(os.Stdout, stages, start, end)
 	}
 
-	// Skip service build if flag is set
-	if skipServiceBuild {
-		spinner.UpdateMessage("Skipping service build (--skip-service-build flag is set)")
-		spinner.Complete()
-		sm.Stop()
-		fmt.Println("Service build was skipped. No service was created.")
+	if start >= len(stages) {
+		pipeline.sm.Stop()
+		utils.PrintSuccess(fmt.Sprintf("All stages completed according to the checkpoint %s, nothing to resume.", checkpointFile))
 		return nil
 	}
 
-	// Use custom service name if provided, otherwise use repo name
-	serviceNameToUse := repoName
-	if serviceName != "" {
-		serviceNameToUse = serviceName
-	}
-
-	// Prepare release description pointer
-	var releaseDescriptionPtr *string
-	if releaseDescription != "" {
-		releaseDescriptionPtr = &releaseDescription
-	}
+	// Step 0: Validate user is currently logged in
+	spinner = pipeline.sm.AddSpinner("Checking if user is logged in")
+	time.Sleep(1 * time.Second) // Add a delay to show the spinner
+	spinner.Complete()
+	pipeline.sm.Stop()
 
-	// Build the service
-	serviceID, devEnvironmentID, devPlanID, undefinedResources, err := buildService(
-		cmd.Context(),
-		fileData,
-		token,
-		serviceNameToUse,
-		DockerComposeSpecType,
-		nil,
-		nil,
-		nil,
-		nil,
-		true,
-		true,
-		releaseDescriptionPtr,
-		false,
-	)
+	pipeline.token, err = common.GetTokenWithLogin()
 	if err != nil {
-		utils.HandleSpinnerError(spinner, sm, err)
+		utils.PrintError(err)
 		return err
 	}
 
-	spinner.UpdateMessage(fmt.Sprintf("Building service from the compose spec: built service %s (service ID: %s)", serviceNameToUse, serviceID))
-	spinner.Complete()
+	pipeline.restartSpinners()
 
-	// Print warning if there are any undefined resources
-	if len(undefinedResources) > 0 {
-		sm.Stop()
-
-		utils.PrintWarning("The following resources appear in the service plan but were not defined in the spec:")
-		for resourceName, resourceID := range undefinedResources {
-			utils.PrintWarning(fmt.Sprintf("  %s: %s", resourceName, resourceID))
-		}
-		utils.PrintWarning("These resources were not processed during the build. If you no longer need them, please deprecate and remove them from the service plan manually in UI or using the API.")
-
-		sm = ysmrr.NewSpinnerManager()
-		sm.Start()
-	}
-
-	// Skip environment promotion if flag is set
-	var prodEnvironmentID string
-	if skipEnvironmentPromotion {
-		spinner = sm.AddSpinner("Skipping environment promotion (--skip-environment-promotion flag is set)")
-		spinner.Complete()
-	} else {
-		// Step 16: Check if the production environment is set up
-		spinner = sm.AddSpinner("Checking if the production environment is set up")
+	// Only check the registry auth prerequisites if the images will be built
+	if start == 0 && stages[0].SkipReason(pipeline) == "" {
+		spinner = pipeline.sm.AddSpinner(fmt.Sprintf("Checking prerequisites for %s registry auth", authProvider.Name()))
 		time.Sleep(1 * time.Second) // Add a delay to show the spinner
-		prodEnvironmentID, err = checkIfProdEnvExists(cmd.Context(), token, serviceID)
-		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
-			return err
-		}
-		yesOrNo = "No"
-		if prodEnvironmentID != "" {
-			yesOrNo = "Yes"
-		}
-		spinner.UpdateMessage(fmt.Sprintf("Checking if the production environment is set up: %s", yesOrNo))
-		spinner.Complete()
-		// Step 17: Create a production environment if it does not exist
-		if prodEnvironmentID == "" {
-			spinner = sm.AddSpinner("Creating a production environment")
-			prodEnvironmentID, err = createProdEnv(cmd.Context(), token, serviceID, devEnvironmentID)
-			if err != nil {
-				utils.HandleSpinnerError(spinner, sm, err)
-				return err
-			}
-			spinner.UpdateMessage(fmt.Sprintf("Creating a production environment: created environment %s (environment ID: %s)", DefaultProdEnvName, prodEnvironmentID))
-			spinner.Complete()
-		}
-
-		// Step 18: Promote the service to the production environment
-		spinner = sm.AddSpinner(fmt.Sprintf("Promoting the service to the %s environment", DefaultProdEnvName))
-		err = dataaccess.PromoteServiceEnvironment(cmd.Context(), token, serviceID, devEnvironmentID)
-		if err != nil {
-			utils.PrintError(err)
-			return err
-		}
-		spinner.UpdateMessage("Promoting the service to the production environment: Success")
-		spinner.Complete()
-
-		// Step 19: Set this service plan as the default service plan in production
-		spinner = sm.AddSpinner("Setting the service plan as the default service plan in production")
-
-		// Describe the dev product tier
-		var devProductTier *openapiclient.DescribeProductTierResult
-		devProductTier, err = dataaccess.DescribeProductTier(cmd.Context(), token, serviceID, devPlanID)
-		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
-			return err
-		}
-
-		// Find the production plan with the same name as the dev plan
-		var prodPlanID string
-		service, err := dataaccess.DescribeService(cmd.Context(), token, serviceID)
-		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
-			return err
-		}
-		for _, env := range service.ServiceEnvironments {
-			if env.Id != prodEnvironmentID {
-				continue
-			}
-			for _, plan := range env.ServicePlans {
-				if plan.Name == devProductTier.Name {
-					prodPlanID = plan.ProductTierID
-					break
-				}
-			}
-		}
-
-		// Find the latest version of the production plan
-		targetVersion, err := dataaccess.FindLatestVersion(cmd.Context(), token, serviceID, prodPlanID)
+		err = authProvider.Preflight()
 		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
+			utils.HandleSpinnerError(spinner, pipeline.sm, err)
 			return err
 		}
-
-		// Set the default service plan
-		_, err = dataaccess.SetDefaultServicePlan(cmd.Context(), token, serviceID, prodPlanID, targetVersion)
-		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
-			return err
-		}
-		spinner.UpdateMessage("Setting current version as the default service plan version in production: Success")
+		spinner.UpdateMessage(fmt.Sprintf("Checking prerequisites for %s registry auth: Yes", authProvider.Name()))
 		spinner.Complete()
 	}
 
-	// Step 20: Initialize the SaaS Portal
-	var prodEnvironment *openapiclient.DescribeServiceEnvironmentResult
-
-	if skipSaasPortalInit || skipEnvironmentPromotion {
-		// Skip SaaS Portal initialization if either flag is set
-		spinner = sm.AddSpinner("Skipping SaaS Portal initialization")
-		if skipSaasPortalInit {
-			spinner.UpdateMessage("Skipping SaaS Portal initialization (--skip-saas-portal-init flag is set)")
-		} else {
-			spinner.UpdateMessage("Skipping SaaS Portal initialization (--skip-environment-promotion flag is set)")
-		}
-		spinner.Complete()
-	} else if config.IsProd() && !skipEnvironmentPromotion && prodEnvironmentID != "" {
-		prodEnvironment, err = dataaccess.DescribeServiceEnvironment(cmd.Context(), token, serviceID, prodEnvironmentID)
-		if err != nil {
-			utils.PrintError(err)
-			return err
-		}
-
-		if !checkIfSaaSPortalReady(prodEnvironment) {
-			spinner = sm.AddSpinner("Initializing the SaaS Portal. This may take a few minutes.")
-
-			for {
-				prodEnvironment, err = dataaccess.DescribeServiceEnvironment(cmd.Context(), token, serviceID, prodEnvironmentID)
-				if err != nil {
-					utils.PrintError(err)
-					return err
-				}
+	// Run the stages
+	if err = pipeline.run(stages, start, end); err != nil {
+		pipeline.sm.Stop()
+		utils.PrintError(err)
+		return err
+	}
 
-				if checkIfSaaSPortalReady(prodEnvironment) {
-					break
-				}
+	pipeline.sm.Stop()
 
-				time.Sleep(5 * time.Second)
-			}
+	outputs := pipeline.checkpoint.Outputs
 
-			spinner.Complete()
-		}
+	if dryRun {
+		fmt.Printf("Dry run completed. Final compose spec written to %s\n", pipeline.dryRunFile())
+		return nil
+	}
 
-		// Step 21: Retrieve the SaaS Portal URL
-		spinner = sm.AddSpinner("Retrieving the SaaS Portal URL")
-		time.Sleep(1 * time.Second) // Add a delay to show the spinner
-		spinner.Complete()
+	if end < len(stages)-1 {
+		fmt.Println()
+		utils.PrintSuccess(fmt.Sprintf("Completed the stages up to %s. Run 'omctl build-from-repo --from-stage %s' to continue.", stages[end].Name, stages[end+1].Name))
+		return nil
 	}
 
-	sm.Stop()
+	if outputs.ServiceID == "" {
+		fmt.Println("Service build was skipped. No service was created.")
+		return nil
+	}
 
 	println()
 	println()
 	println()
 	fmt.Println("Congratulations! Your service has been successfully built and deployed.")
-	if config.IsProd() && !skipSaasPortalInit && !skipEnvironmentPromotion && prodEnvironment != nil {
-		utils.PrintURL("You can access the SaaS Portal at", getSaaSPortalURL(prodEnvironment, serviceID, prodEnvironmentID))
+	if outputs.SaaSPortalURL != "" && !skipSaasPortalInit && !skipEnvironmentPromotion {
+		utils.PrintURL("You can access the SaaS Portal at", outputs.SaaSPortalURL)
 	}
 
 	println()
@@ -1161,9 +669,9 @@ x-omnistrate-image-registry-attributes:
 	gcpAccountUnverified := false
 	var unverifiedAwsAccountConfigID, unverifiedGcpAccountConfigID string
 	if awsAccountID != "" || gcpProjectID != "" {
-		accounts, err := dataaccess.ListAccounts(cmd.Context(), token, "all")
+		accounts, err := dataaccess.ListAccounts(cmd.Context(), pipeline.token, "all")
 		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
+			utils.PrintError(err)
 			return err
 		}
 
@@ -1185,7 +693,7 @@ x-omnistrate-image-registry-attributes:
 		fmt.Println("Next steps:")
 		fmt.Printf("1.")
 		if awsAccountUnverified {
-			account, err := dataaccess.DescribeAccount(cmd.Context(), token, unverifiedAwsAccountConfigID)
+			account, err := dataaccess.DescribeAccount(cmd.Context(), pipeline.token, unverifiedAwsAccountConfigID)
 			if err != nil {
 				utils.PrintError(err)
 				return err
@@ -1196,7 +704,7 @@ x-omnistrate-image-registry-attributes:
 		}
 
 		if gcpAccountUnverified {
-			account, err := dataaccess.DescribeAccount(cmd.Context(), token, unverifiedGcpAccountConfigID)
+			account, err := dataaccess.DescribeAccount(cmd.Context(), pipeline.token, unverifiedGcpAccountConfigID)
 			if err != nil {
 				utils.PrintError(err)
 				return err
@@ -1220,6 +728,53 @@ x-omnistrate-image-registry-attributes:
 
 // Helper functions
 
+// imageLabels returns the OCI labels that link the image to the repository and the revision it was built from.
+func imageLabels(gitRepo utils.GitRepository) []string {
+	labels := []string{fmt.Sprintf("org.opencontainers.image.source=%s", gitRepo.Remote.WebURL())}
+	if gitRepo.CommitSHA != "" {
+		labels = append(labels, fmt.Sprintf("org.opencontainers.image.revision=%s", gitRepo.CommitSHA))
+	}
+	if gitRepo.Branch != "" {
+		labels = append(labels, fmt.Sprintf("%s=%s", gitBranchLabel, gitRepo.Branch))
+	}
+	return labels
+}
+
+// describeGitRevision returns a short description of the checked out revision, e.g. main@1a2b3c4.
+func describeGitRevision(gitRepo utils.GitRepository) string {
+	switch {
+	case gitRepo.CommitSHA == "" && gitRepo.Branch == "":
+		return "no commits"
+	case gitRepo.CommitSHA == "":
+		return fmt.Sprintf("%s, no commits", gitRepo.Branch)
+	case gitRepo.Branch == "":
+		return fmt.Sprintf("detached@%s", gitRepo.ShortCommitSHA())
+	default:
+		return fmt.Sprintf("%s@%s", gitRepo.Branch, gitRepo.ShortCommitSHA())
+	}
+}
+
+// releaseDescriptionWithBuildInfo appends the git branch and commit, and the digest each pushed image tag
+// pointed to, to the release description.
+func releaseDescriptionWithBuildInfo(releaseDescription string, gitRepo utils.GitRepository, images map[string]builtImage) string {
+	var buildInfo []string
+	if gitRepo.CommitSHA != "" {
+		buildInfo = append(buildInfo, fmt.Sprintf("built from %s", describeGitRevision(gitRepo)))
+	}
+	if imageDigests := imageDigestsDescription(images); imageDigests != "" {
+		buildInfo = append(buildInfo, imageDigests)
+	}
+	if len(buildInfo) == 0 {
+		return releaseDescription
+	}
+
+	if releaseDescription == "" {
+		return strings.Join(buildInfo, "; ")
+	}
+
+	return fmt.Sprintf("%s (%s)", releaseDescription, strings.Join(buildInfo, "; "))
+}
+
 func checkIfProdEnvExists(ctx context.Context, token string, serviceID string) (string, error) {
 	prodEnvironment, err := dataaccess.FindEnvironment(ctx, token, serviceID, "prod")
 	if errors.As(err, &dataaccess.ErrEnvironmentNotFound) {
@@ -1337,7 +892,7 @@ func RenderEnvFileAndInterpolateVariables(fileData []byte, rootDir string, file
 	// Replace `$` with `$$` to avoid interpolation. Do not replace for `${...}` since it's used to specify variable interpolations
 	fileData = []byte(strings.ReplaceAll(string(fileData), "$", "$$"))   // Escape $ to $$
 	fileData = []byte(strings.ReplaceAll(string(fileData), "$${", "${")) // Unescape $${ to ${ for variable interpolation
-	fileData = []byte(strings.ReplaceAll(string(fileData), "${{ secrets.GitHubPAT }}", "$${{ secrets.GitHubPAT }}"))
+	fileData = []byte(strings.ReplaceAll(string(fileData), "${{ secrets.", "$${{ secrets."))
 
 	// Write the compose spec to a temporary file
 	tempFile := filepath.Join(rootDir, filepath.Base(file)+".tmp")
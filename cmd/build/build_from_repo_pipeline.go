@@ -0,0 +1,523 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	StageDockerBuild    = "docker-build"
+	StageGenerateSpec   = "generate-spec"
+	StageBuildService   = "build-service"
+	StageCreateProdEnv  = "create-prod-env"
+	StagePromote        = "promote"
+	StageSetDefault     = "set-default"
+	StageSaaSPortalInit = "saas-portal-init"
+
+	stageStatusCompleted = "completed"
+	stageStatusFailed    = "failed"
+	stageStatusSkipped   = "skipped"
+
+	checkpointDirName = "build-from-repo"
+)
+
+// buildFromRepoOptions holds the validated flags of the build-from-repo command.
+type buildFromRepoOptions struct {
+	envVars                  []string
+	deploymentType           string
+	awsAccountID             string
+	gcpProjectID             string
+	gcpProjectNumber         string
+	file                     string
+	serviceName              string
+	releaseDescription       string
+	pinImageDigests          bool
+	platforms                []string
+	buildParallelism         int
+	dryRun                   bool
+	skipDockerBuild          bool
+	skipServiceBuild         bool
+	skipEnvironmentPromotion bool
+	skipSaasPortalInit       bool
+	registry                 string
+	imageNameTemplate        string
+	authProvider             registryAuthProvider
+}
+
+// buildFromRepoPipeline holds the state shared by the stages of build-from-repo.
+type buildFromRepoPipeline struct {
+	opts       buildFromRepoOptions
+	sm         ysmrr.SpinnerManager
+	token      string
+	checkpoint *buildFromRepoCheckpoint
+
+	rootDir                    string
+	gitRepo                    utils.GitRepository
+	composeSpecExists          bool
+	composeSpecHasBuildContext bool
+	fileData                   []byte
+	project                    *types.Project
+	dockerfilePaths            map[string]string // service -> absolute dockerfile path
+	registryCreds              registryCredentials
+}
+
+// buildFromRepoStage is a named, checkpointed step of build-from-repo.
+type buildFromRepoStage struct {
+	Name        string
+	Description string
+	// SkipReason returns why the stage doesn't apply to this run, or an empty string if it does.
+	SkipReason func(p *buildFromRepoPipeline) string
+	// Requires validates that the outputs of earlier stages the stage depends on are available.
+	Requires func(p *buildFromRepoPipeline) error
+	Run      func(p *buildFromRepoPipeline) error
+}
+
+// buildFromRepoCheckpoint is persisted after every stage so a failed run can be resumed.
+type buildFromRepoCheckpoint struct {
+	RepoDir   string                            `json:"repoDir"`
+	CommitSHA string                            `json:"commitSHA,omitempty"`
+	Stages    map[string]*buildFromRepoStageRun `json:"stages"`
+	Outputs   buildFromRepoOutputs              `json:"outputs"`
+	UpdatedAt string                            `json:"updatedAt"`
+
+	path string
+}
+
+type buildFromRepoStageRun struct {
+	Status      string `json:"status"`
+	StartedAt   string `json:"startedAt,omitempty"`
+	CompletedAt string `json:"completedAt,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// buildFromRepoOutputs are the outputs of the stages that later stages depend on. Secrets are never recorded.
+type buildFromRepoOutputs struct {
+	Images            map[string]builtImage `json:"images,omitempty"` // dockerfile path relative to the repo root -> image
+	RegistryUsername  string                `json:"registryUsername,omitempty"`
+	ServiceID         string                `json:"serviceID,omitempty"`
+	DevEnvironmentID  string                `json:"devEnvironmentID,omitempty"`
+	DevPlanID         string                `json:"devPlanID,omitempty"`
+	ProdEnvironmentID string                `json:"prodEnvironmentID,omitempty"`
+	ProdPlanID        string                `json:"prodPlanID,omitempty"`
+	ProdPlanVersion   string                `json:"prodPlanVersion,omitempty"`
+	SaaSPortalURL     string                `json:"saasPortalURL,omitempty"`
+}
+
+type builtImage struct {
+	Service  string `json:"service"`
+	ImageURL string `json:"imageUrl"`      // Image URL with the tag referenced by the compose spec
+	Tag      string `json:"tag,omitempty"` // Mutable image reference the image was pushed with
+	Digest   string `json:"digest,omitempty"`
+}
+
+func buildFromRepoStages() []buildFromRepoStage {
+	return []buildFromRepoStage{
+		{
+			Name:        StageDockerBuild,
+			Description: "Build and push the Docker images",
+			SkipReason: func(p *buildFromRepoPipeline) string {
+				if !p.buildsImages() {
+					return "compose spec has no build contexts"
+				}
+				if p.opts.skipDockerBuild {
+					return "--skip-docker-build flag is set"
+				}
+				return ""
+			},
+			Run: (*buildFromRepoPipeline).runDockerBuildStage,
+		},
+		{
+			Name:        StageGenerateSpec,
+			Description: "Generate or update the compose spec",
+			SkipReason: func(p *buildFromRepoPipeline) string {
+				if !p.buildsImages() {
+					return "compose spec has no build contexts"
+				}
+				return ""
+			},
+			Requires: (*buildFromRepoPipeline).requireImages,
+			Run:      (*buildFromRepoPipeline).runGenerateSpecStage,
+		},
+		{
+			Name:        StageBuildService,
+			Description: "Build the service from the compose spec",
+			SkipReason: func(p *buildFromRepoPipeline) string {
+				if !p.opts.dryRun && p.opts.skipServiceBuild {
+					return "--skip-service-build flag is set"
+				}
+				return ""
+			},
+			Requires: (*buildFromRepoPipeline).requireImages,
+			Run:      (*buildFromRepoPipeline).runBuildServiceStage,
+		},
+		{
+			Name:        StageCreateProdEnv,
+			Description: fmt.Sprintf("Create the %s environment if it doesn't exist", DefaultProdEnvName),
+			SkipReason:  (*buildFromRepoPipeline).promotionSkipReason,
+			Requires: func(p *buildFromRepoPipeline) error {
+				return p.requireOutputs(StageBuildService, p.checkpoint.Outputs.ServiceID, p.checkpoint.Outputs.DevEnvironmentID)
+			},
+			Run: (*buildFromRepoPipeline).runCreateProdEnvStage,
+		},
+		{
+			Name:        StagePromote,
+			Description: fmt.Sprintf("Promote the service to the %s environment", DefaultProdEnvName),
+			SkipReason:  (*buildFromRepoPipeline).promotionSkipReason,
+			Requires: func(p *buildFromRepoPipeline) error {
+				if err := p.requireOutputs(StageBuildService, p.checkpoint.Outputs.ServiceID, p.checkpoint.Outputs.DevEnvironmentID); err != nil {
+					return err
+				}
+				return p.requireOutputs(StageCreateProdEnv, p.checkpoint.Outputs.ProdEnvironmentID)
+			},
+			Run: (*buildFromRepoPipeline).runPromoteStage,
+		},
+		{
+			Name:        StageSetDefault,
+			Description: fmt.Sprintf("Set the released version as the default in the %s environment", DefaultProdEnvName),
+			SkipReason:  (*buildFromRepoPipeline).promotionSkipReason,
+			Requires: func(p *buildFromRepoPipeline) error {
+				if err := p.requireOutputs(StageBuildService, p.checkpoint.Outputs.ServiceID, p.checkpoint.Outputs.DevPlanID); err != nil {
+					return err
+				}
+				return p.requireOutputs(StageCreateProdEnv, p.checkpoint.Outputs.ProdEnvironmentID)
+			},
+			Run: (*buildFromRepoPipeline).runSetDefaultStage,
+		},
+		{
+			Name:        StageSaaSPortalInit,
+			Description: "Initialize the SaaS Portal",
+			SkipReason: func(p *buildFromRepoPipeline) string {
+				if reason := p.promotionSkipReason(); reason != "" {
+					return reason
+				}
+				if p.opts.skipSaasPortalInit {
+					return "--skip-saas-portal-init flag is set"
+				}
+				if !config.IsProd() {
+					return "SaaS Portal is only initialized in production"
+				}
+				return ""
+			},
+			Requires: func(p *buildFromRepoPipeline) error {
+				if err := p.requireOutputs(StageBuildService, p.checkpoint.Outputs.ServiceID); err != nil {
+					return err
+				}
+				return p.requireOutputs(StageCreateProdEnv, p.checkpoint.Outputs.ProdEnvironmentID)
+			},
+			Run: (*buildFromRepoPipeline).runSaaSPortalInitStage,
+		},
+	}
+}
+
+// buildFromRepoStageNames returns the names of the stages in execution order.
+func buildFromRepoStageNames() []string {
+	names := make([]string, 0)
+	for _, stage := range buildFromRepoStages() {
+		names = append(names, stage.Name)
+	}
+	return names
+}
+
+// selectStages returns the index range [start, end] of the stages to run.
+func selectStages(stages []buildFromRepoStage, checkpoint *buildFromRepoCheckpoint, resume bool, fromStage, toStage string) (start, end int, err error) {
+	stageIndex := func(name string) (int, error) {
+		for i, stage := range stages {
+			if stage.Name == name {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("invalid stage %s. Options: %s", name, strings.Join(buildFromRepoStageNames(), ", "))
+	}
+
+	start, end = 0, len(stages)-1
+
+	if fromStage != "" {
+		if start, err = stageIndex(fromStage); err != nil {
+			return
+		}
+	} else if resume {
+		start = len(stages)
+		for i, stage := range stages {
+			// Stages skipped in the previous run don't apply to it, so they are as done as the completed ones
+			if run, ok := checkpoint.Stages[stage.Name]; !ok || (run.Status != stageStatusCompleted && run.Status != stageStatusSkipped) {
+				start = i
+				break
+			}
+		}
+	}
+
+	if toStage != "" {
+		if end, err = stageIndex(toStage); err != nil {
+			return
+		}
+	}
+
+	if start > end && start < len(stages) {
+		err = fmt.Errorf("--from-stage %s comes after --to-stage %s", stages[start].Name, stages[end].Name)
+	}
+
+	return
+}
+
+// printPlan prints the stages that would run without running them.
+func (p *buildFromRepoPipeline) printPlan(w io.Writer, stages []buildFromRepoStage, start, end int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "STAGE\tACTION\tDETAILS"); err != nil {
+		return err
+	}
+
+	// Outputs of earlier stages are only checked for the first stage that runs, the others get them from the stages before
+	runsEarlierStage := false
+	for i, stage := range stages {
+		action, details := "run", stage.Description
+		switch {
+		case i < start || i > end:
+			action, details = "not selected", p.stageStatusDescription(stage.Name)
+		default:
+			if reason := stage.SkipReason(p); reason != "" {
+				action, details = "skip", reason
+			} else if stage.Requires != nil && !runsEarlierStage {
+				if err := stage.Requires(p); err != nil {
+					action, details = "blocked", err.Error()
+				}
+			}
+		}
+		if action == "run" {
+			runsEarlierStage = true
+		}
+
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", stage.Name, action, details); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (p *buildFromRepoPipeline) stageStatusDescription(stageName string) string {
+	run, ok := p.checkpoint.Stages[stageName]
+	if !ok {
+		return "never run"
+	}
+	if run.Status == stageStatusFailed {
+		return fmt.Sprintf("failed at %s: %s", run.CompletedAt, run.Error)
+	}
+	if run.CompletedAt != "" {
+		return fmt.Sprintf("%s at %s", run.Status, run.CompletedAt)
+	}
+	return run.Status
+}
+
+// run executes the selected stages, recording the result of each stage in the checkpoint.
+func (p *buildFromRepoPipeline) run(stages []buildFromRepoStage, start, end int) error {
+	for i := start; i <= end && i < len(stages); i++ {
+		stage := stages[i]
+
+		if reason := stage.SkipReason(p); reason != "" {
+			spinner := p.sm.AddSpinner(fmt.Sprintf("Skipping stage %s (%s)", stage.Name, reason))
+			spinner.Complete()
+			p.checkpoint.Stages[stage.Name] = &buildFromRepoStageRun{Status: stageStatusSkipped, CompletedAt: checkpointTimestamp()}
+			if err := p.saveCheckpoint(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if stage.Requires != nil {
+			if err := stage.Requires(p); err != nil {
+				return errors.Wrapf(err, "cannot run stage %s", stage.Name)
+			}
+		}
+
+		stageRun := &buildFromRepoStageRun{StartedAt: checkpointTimestamp()}
+		p.checkpoint.Stages[stage.Name] = stageRun
+
+		err := stage.Run(p)
+		stageRun.CompletedAt = checkpointTimestamp()
+		if err != nil {
+			stageRun.Status = stageStatusFailed
+			stageRun.Error = err.Error()
+			if saveErr := p.saveCheckpoint(); saveErr != nil {
+				utils.PrintWarning(fmt.Sprintf("failed to save checkpoint: %v", saveErr))
+			}
+			return errors.Wrapf(err, "stage %s failed, fix the problem and rerun with --resume to continue from this stage", stage.Name)
+		}
+
+		stageRun.Status = stageStatusCompleted
+		if err = p.saveCheckpoint(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fail marks the spinner as failed and returns the error to the stage runner.
+func (p *buildFromRepoPipeline) fail(spinner *ysmrr.Spinner, err error) error {
+	if spinner != nil {
+		spinner.Error()
+	}
+	return err
+}
+
+// restartSpinners starts a new spinner manager after output has been written directly to the terminal.
+func (p *buildFromRepoPipeline) restartSpinners() {
+	p.sm = ysmrr.NewSpinnerManager()
+	p.sm.Start()
+}
+
+// buildsImages reports whether the repository has Dockerfiles to build, either because there's no compose
+// spec yet or because the compose spec has build contexts.
+func (p *buildFromRepoPipeline) buildsImages() bool {
+	return !p.composeSpecExists || p.composeSpecHasBuildContext
+}
+
+func (p *buildFromRepoPipeline) promotionSkipReason() string {
+	switch {
+	case p.opts.dryRun:
+		return "--dry-run flag is set"
+	case p.opts.skipServiceBuild:
+		return "--skip-service-build flag is set"
+	case p.opts.skipEnvironmentPromotion:
+		return "--skip-environment-promotion flag is set"
+	default:
+		return ""
+	}
+}
+
+// requireImages checks that every Dockerfile has an image from the docker-build stage, unless docker build is skipped,
+// in which case placeholder image URLs are used.
+func (p *buildFromRepoPipeline) requireImages() error {
+	if !p.buildsImages() || p.opts.skipDockerBuild {
+		return nil
+	}
+
+	for service, dockerfilePath := range p.dockerfilePaths {
+		if _, ok := p.imageForDockerfile(dockerfilePath); !ok {
+			return fmt.Errorf("no image built for service %s, run stage %s first", service, StageDockerBuild)
+		}
+	}
+
+	return nil
+}
+
+func (p *buildFromRepoPipeline) requireOutputs(stageName string, outputs ...string) error {
+	for _, output := range outputs {
+		if output == "" {
+			return fmt.Errorf("missing outputs of stage %s, run it first", stageName)
+		}
+	}
+	return nil
+}
+
+func (p *buildFromRepoPipeline) imageForDockerfile(dockerfilePath string) (builtImage, bool) {
+	image, ok := p.checkpoint.Outputs.Images[p.relativeToRoot(dockerfilePath)]
+	return image, ok
+}
+
+func (p *buildFromRepoPipeline) setImageForDockerfile(dockerfilePath string, image builtImage) {
+	if p.checkpoint.Outputs.Images == nil {
+		p.checkpoint.Outputs.Images = make(map[string]builtImage)
+	}
+	p.checkpoint.Outputs.Images[p.relativeToRoot(dockerfilePath)] = image
+}
+
+func (p *buildFromRepoPipeline) relativeToRoot(path string) string {
+	relPath, err := filepath.Rel(p.rootDir, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// defaultCheckpointPath returns the checkpoint file for the repository, stored in the omnistrate-ctl config directory.
+func defaultCheckpointPath(rootDir string) string {
+	return filepath.Join(config.ConfigDir(), checkpointDirName, utils.HashPasswordSha256(rootDir)[:16]+".json")
+}
+
+// newCheckpoint returns an empty checkpoint for the repository.
+func newCheckpoint(path, rootDir, commitSHA string) *buildFromRepoCheckpoint {
+	return &buildFromRepoCheckpoint{
+		RepoDir:   rootDir,
+		CommitSHA: commitSHA,
+		Stages:    make(map[string]*buildFromRepoStageRun),
+		path:      path,
+	}
+}
+
+// openCheckpoint returns the checkpoint of the run. Only --resume and --from-stage use the outputs of an earlier run, so
+// any other run, including one with only --to-stage, starts at the first stage with a new checkpoint.
+func openCheckpoint(path, rootDir, commitSHA string, resume bool, fromStage string) (*buildFromRepoCheckpoint, error) {
+	if !resume && fromStage == "" {
+		return newCheckpoint(path, rootDir, commitSHA), nil
+	}
+
+	checkpoint, found, err := loadCheckpoint(path, rootDir, commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	if resume && !found {
+		return nil, fmt.Errorf("no checkpoint found at %s, nothing to resume", path)
+	}
+	return checkpoint, nil
+}
+
+// loadCheckpoint reads the checkpoint file. It returns an empty checkpoint if the file doesn't exist, and an error if
+// the checkpoint was recorded for another commit, since its outputs, such as the images, may not match the repository.
+func loadCheckpoint(path, rootDir, commitSHA string) (*buildFromRepoCheckpoint, bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return newCheckpoint(path, rootDir, commitSHA), false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read checkpoint")
+	}
+
+	checkpoint := newCheckpoint(path, rootDir, commitSHA)
+	if err = json.Unmarshal(data, checkpoint); err != nil {
+		return nil, false, errors.Wrapf(err, "invalid checkpoint file %s", path)
+	}
+	if checkpoint.Stages == nil {
+		checkpoint.Stages = make(map[string]*buildFromRepoStageRun)
+	}
+	if checkpoint.CommitSHA != commitSHA {
+		return nil, false, fmt.Errorf("the checkpoint was recorded for commit %s but the repository is at %s, rerun without --resume or --from-stage", checkpoint.CommitSHA, commitSHA)
+	}
+
+	return checkpoint, true, nil
+}
+
+func (p *buildFromRepoPipeline) saveCheckpoint() error {
+	// Dry runs don't create anything worth resuming
+	if p.opts.dryRun {
+		return nil
+	}
+
+	p.checkpoint.UpdatedAt = checkpointTimestamp()
+	data, err := json.MarshalIndent(p.checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(p.checkpoint.path), config.DefaultPermissions); err != nil {
+		return errors.Wrap(err, "failed to create checkpoint directory")
+	}
+
+	return os.WriteFile(p.checkpoint.path, data, 0600)
+}
+
+func checkpointTimestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,103 @@
+package build
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectStages(t *testing.T) {
+	stages := buildFromRepoStages()
+	checkpoint := newCheckpoint("", "", "")
+	checkpoint.Stages[StageDockerBuild] = &buildFromRepoStageRun{Status: stageStatusCompleted}
+	checkpoint.Stages[StageGenerateSpec] = &buildFromRepoStageRun{Status: stageStatusSkipped}
+	checkpoint.Stages[StageBuildService] = &buildFromRepoStageRun{Status: stageStatusFailed}
+
+	start, end, err := selectStages(stages, checkpoint, false, "", "")
+	require.NoError(t, err)
+	require.Equal(t, 0, start)
+	require.Equal(t, len(stages)-1, end)
+
+	start, _, err = selectStages(stages, checkpoint, true, "", "")
+	require.NoError(t, err)
+	require.Equal(t, StageBuildService, stages[start].Name)
+
+	start, end, err = selectStages(stages, checkpoint, false, StageCreateProdEnv, StageSetDefault)
+	require.NoError(t, err)
+	require.Equal(t, StageCreateProdEnv, stages[start].Name)
+	require.Equal(t, StageSetDefault, stages[end].Name)
+
+	_, _, err = selectStages(stages, checkpoint, false, StagePromote, StageDockerBuild)
+	require.Error(t, err)
+
+	_, _, err = selectStages(stages, checkpoint, false, "unknown", "")
+	require.ErrorContains(t, err, "invalid stage unknown")
+
+	for _, stage := range stages {
+		checkpoint.Stages[stage.Name] = &buildFromRepoStageRun{Status: stageStatusCompleted}
+	}
+	start, _, err = selectStages(stages, checkpoint, true, "", "")
+	require.NoError(t, err)
+	require.Equal(t, len(stages), start)
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkpoints", "repo.json")
+
+	checkpoint, found, err := loadCheckpoint(path, dir, "abc")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	p := &buildFromRepoPipeline{rootDir: dir, checkpoint: checkpoint}
+	p.setImageForDockerfile(filepath.Join(dir, "api", "Dockerfile"), builtImage{Service: "api", ImageURL: "ghcr.io/org/repo-api:sha-123", Digest: "sha256:123"})
+	p.checkpoint.Outputs.ServiceID = "s-123"
+	p.checkpoint.Stages[StageDockerBuild] = &buildFromRepoStageRun{Status: stageStatusCompleted}
+	require.NoError(t, p.saveCheckpoint())
+
+	loaded, found, err := loadCheckpoint(path, dir, "abc")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "abc", loaded.CommitSHA)
+	require.Equal(t, "s-123", loaded.Outputs.ServiceID)
+	require.Equal(t, stageStatusCompleted, loaded.Stages[StageDockerBuild].Status)
+	require.Contains(t, loaded.Outputs.Images, "api/Dockerfile")
+
+	_, _, err = loadCheckpoint(path, dir, "def")
+	require.EqualError(t, err, "the checkpoint was recorded for commit abc but the repository is at def, rerun without --resume or --from-stage")
+
+	p.checkpoint = loaded
+	image, ok := p.imageForDockerfile(filepath.Join(dir, "api", "Dockerfile"))
+	require.True(t, ok)
+	require.Equal(t, "sha256:123", image.Digest)
+}
+
+func TestOpenCheckpointAfterCommitChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "repo.json")
+
+	p := &buildFromRepoPipeline{rootDir: dir, checkpoint: newCheckpoint(path, dir, "abc")}
+	p.checkpoint.Outputs.ServiceID = "s-123"
+	p.checkpoint.Stages[StageDockerBuild] = &buildFromRepoStageRun{Status: stageStatusCompleted}
+	require.NoError(t, p.saveCheckpoint())
+
+	// --to-stage alone runs from the first stage, so it starts a new checkpoint for the new commit
+	checkpoint, err := openCheckpoint(path, dir, "def", false, "")
+	require.NoError(t, err)
+	require.Equal(t, "def", checkpoint.CommitSHA)
+	require.Empty(t, checkpoint.Stages)
+	require.Empty(t, checkpoint.Outputs.ServiceID)
+
+	_, err = openCheckpoint(path, dir, "def", true, "")
+	require.ErrorContains(t, err, "the checkpoint was recorded for commit abc")
+	_, err = openCheckpoint(path, dir, "def", false, StageBuildService)
+	require.ErrorContains(t, err, "the checkpoint was recorded for commit abc")
+
+	checkpoint, err = openCheckpoint(path, dir, "abc", true, "")
+	require.NoError(t, err)
+	require.Equal(t, "s-123", checkpoint.Outputs.ServiceID)
+
+	_, err = openCheckpoint(filepath.Join(dir, "missing.json"), dir, "abc", true, "")
+	require.ErrorContains(t, err, "nothing to resume")
+}
@@ -0,0 +1,605 @@
+package build
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclient "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+	"github.com/pkg/errors"
+)
+
+// runDockerBuildStage builds the images of all Dockerfiles and pushes them to the registry.
+func (p *buildFromRepoPipeline) runDockerBuildStage() (err error) {
+	// Check if the Dockerfile exists
+	for _, dockerfilePath := range p.dockerfilePaths {
+		spinner := p.sm.AddSpinner(fmt.Sprintf("Checking if %s exists in the repository", dockerfilePath))
+		time.Sleep(1 * time.Second) // Add a delay to show the spinner
+
+		if _, err = os.Stat(dockerfilePath); os.IsNotExist(err) {
+			return p.fail(spinner, errors.New(fmt.Sprintf("%s not found in the repository", dockerfilePath)))
+		}
+
+		spinner.UpdateMessage(fmt.Sprintf("Checking if %s exists in the repository: Yes", dockerfilePath))
+		spinner.Complete()
+	}
+
+	// Check if Docker is installed
+	spinner := p.sm.AddSpinner("Checking if Docker installed")
+	time.Sleep(1 * time.Second)                   // Add a delay to show the spinner
+	err = exec.Command("docker", "version").Run() // Simple way to check if Docker is available
+	if err != nil {
+		return p.fail(spinner, err)
+	}
+	spinner.UpdateMessage("Checking if Docker installed: Yes")
+	spinner.Complete()
+
+	// Check if the Docker daemon is running
+	spinner = p.sm.AddSpinner("Checking if Docker daemon is running")
+	time.Sleep(1 * time.Second)                // Add a delay to show the spinner
+	err = exec.Command("docker", "info").Run() // Simple way to check if Docker is available
+	if err != nil {
+		return p.fail(spinner, err)
+	}
+	spinner.UpdateMessage("Checking if Docker daemon is running: Yes")
+	spinner.Complete()
+
+	// Retrieve the registry credentials
+	if err = p.resolveRegistryCredentials(true); err != nil {
+		return err
+	}
+
+	spinner = p.sm.AddSpinner(fmt.Sprintf("Retrieving %s username", p.opts.registry))
+	time.Sleep(1 * time.Second) // Add a delay to show the spinner
+	spinner.UpdateMessage(fmt.Sprintf("Retrieving %s username: %s", p.opts.registry, p.registryCreds.Username))
+	spinner.Complete()
+
+	// Label the docker image with the repository source and revision
+	spinner = p.sm.AddSpinner("Labeling Docker image with the repository source and revision")
+	spinner.UpdateMessage(fmt.Sprintf("Labeling Docker image with the repository source and revision: %s (%s)", p.gitRepo.Remote.WebURL(), describeGitRevision(p.gitRepo)))
+	spinner.Complete()
+
+	// Login to the container registry
+	if p.opts.authProvider.LoginRequired() {
+		spinner = p.sm.AddSpinner(fmt.Sprintf("Logging in to %s", p.opts.registry))
+		spinner.Complete()
+		p.sm.Stop()
+		loginCmd := exec.Command("docker", "login", p.opts.registry, "--username", p.registryCreds.Username, "--password-stdin")
+		loginCmd.Stdin = strings.NewReader(p.registryCreds.Password)
+
+		// Redirect stdout and stderr to the terminal
+		loginCmd.Stdout = os.Stdout
+		loginCmd.Stderr = os.Stderr
+
+		fmt.Printf("Invoking 'docker login %s --username %s --password-stdin'...\n", p.opts.registry, p.registryCreds.Username)
+		err = loginCmd.Run()
+		p.restartSpinners()
+		if err != nil {
+			return err
+		}
+	} else {
+		spinner = p.sm.AddSpinner(fmt.Sprintf("Using existing docker credentials for %s", p.opts.registry))
+		spinner.Complete()
+	}
+
+	// Render the image names
+	jobs := make(map[string]imageBuildJob)
+	dockerfilePathsArr := p.dockerfilePathsArr()
+	for service, dockerfilePath := range p.dockerfilePaths {
+		var imageUrl string
+		imageUrl, err = renderImageName(p.opts.imageNameTemplate, imageNameData{
+			Registry: p.opts.registry,
+			Owner:    p.gitRepo.Remote.Owner,
+			Repo:     p.gitRepo.Remote.Repo,
+			Service:  service,
+			Label:    utils.GetFirstDifferentSegmentInFilePaths(dockerfilePath, dockerfilePathsArr),
+		})
+		if err != nil {
+			return err
+		}
+		jobs[service] = imageBuildJob{Service: service, DockerfilePath: dockerfilePath, ImageURL: imageUrl}
+	}
+
+	// Build and push the images concurrently. In dry-run mode, the images are only built locally.
+	buildOpts := imageBuildOptions{
+		platforms: p.opts.platforms,
+		labels:    imageLabels(p.gitRepo),
+		push:      !p.opts.dryRun,
+	}
+	parallelism := min(p.opts.buildParallelism, len(jobs))
+
+	spinner = p.sm.AddSpinner(fmt.Sprintf("Building %d Docker image(s) for %s with up to %d build(s) in parallel", len(jobs), strings.Join(p.opts.platforms, ","), parallelism))
+	spinner.Complete()
+	p.sm.Stop()
+
+	images, err := runImageBuilds(context.Background(), imageBuildJobsByService(jobs), parallelism, os.Stdout, dockerImageBuilder(buildOpts))
+	p.restartSpinners()
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		p.setImageForDockerfile(jobs[image.Service].DockerfilePath, image)
+		if p.opts.dryRun {
+			spinner = p.sm.AddSpinner(fmt.Sprintf("Dry run: Using local image tag %s (skipping push)", image.ImageURL))
+		} else {
+			spinner = p.sm.AddSpinner(fmt.Sprintf("Pushed %s (digest: %s)", image.ImageURL, image.Digest))
+		}
+		spinner.Complete()
+	}
+
+	return nil
+}
+
+// runGenerateSpecStage generates the compose spec from the image if the repository has none, or adds the
+// deployment and registry sections to the existing compose spec.
+func (p *buildFromRepoPipeline) runGenerateSpecStage() error {
+	if p.opts.skipDockerBuild {
+		if err := p.usePlaceholderImages(); err != nil {
+			return err
+		}
+	}
+
+	// The registry password is needed to inspect the pushed image when generating the compose spec from it
+	if !p.composeSpecExists && p.registryCreds.Password == "" {
+		if err := p.resolveRegistryCredentials(true); err != nil {
+			return err
+		}
+	}
+
+	spinner := p.sm.AddSpinner("Generating compose spec from the Docker image")
+	if !p.composeSpecExists {
+		// Parse the environment variables
+		var formattedEnvVars []openapiclient.EnvironmentVariable
+		for _, envVar := range p.opts.envVars {
+			if envVar == "[]" {
+				continue
+			}
+			envVarParts := strings.Split(envVar, "=")
+			if len(envVarParts) != 2 {
+				return p.fail(spinner, errors.New("invalid environment variable format"))
+			}
+			formattedEnvVars = append(formattedEnvVars, openapiclient.EnvironmentVariable{
+				Key:   envVarParts[0],
+				Value: envVarParts[1],
+			})
+		}
+
+		image, _ := p.imageForDockerfile(p.dockerfilePaths[defaultServiceName])
+
+		// Generate compose spec from image
+		generateComposeSpecRequest := openapiclient.GenerateComposeSpecFromContainerImageRequest2{
+			ImageRegistry:        p.opts.registry,
+			Image:                strings.TrimPrefix(image.ImageURL, p.opts.registry+"/"),
+			Username:             utils.ToPtr(p.registryCreds.Username),
+			Password:             utils.ToPtr(p.registryCreds.Password),
+			EnvironmentVariables: formattedEnvVars,
+		}
+
+		generateComposeSpecRes, err := dataaccess.GenerateComposeSpecFromContainerImage(context.Background(), p.token, generateComposeSpecRequest)
+		if err != nil {
+			return p.fail(spinner, err)
+		}
+
+		// Decode the base64 encoded file content
+		fileData, err := base64.StdEncoding.DecodeString(generateComposeSpecRes.FileContent)
+		if err != nil {
+			return p.fail(spinner, err)
+		}
+
+		// Replace the actual registry password with the secret placeholder
+		if p.registryCreds.Password != "" {
+			fileData = []byte(strings.ReplaceAll(string(fileData), p.registryCreds.Password, p.opts.authProvider.SecretPlaceholder()))
+		}
+
+		// Replace the image tag with build tag
+		fileData = []byte(strings.ReplaceAll(string(fileData), fmt.Sprintf("image: %s", image.ImageURL), "build:\n      context: .\n      dockerfile: Dockerfile"))
+
+		// Append the deployment section to the compose spec
+		if fileData, err = p.appendDeploymentSection(fileData); err != nil {
+			return p.fail(spinner, err)
+		}
+
+		p.fileData = fileData
+	} else {
+		var err error
+
+		// Append the deployment section to the compose spec if it doesn't exist
+		if !strings.Contains(string(p.fileData), "deployment:") {
+			if p.fileData, err = p.appendDeploymentSection(p.fileData); err != nil {
+				return p.fail(spinner, err)
+			}
+		}
+
+		// Append the image registry attributes to the compose spec if it doesn't exist
+		if !strings.Contains(string(p.fileData), "x-omnistrate-image-registry-attributes") {
+			p.fileData = append(p.fileData, []byte(registryAttributesSection(p.opts.registry, p.registryUsername(), p.opts.authProvider.SecretPlaceholder()))...)
+		}
+	}
+
+	// Write the compose spec to a file
+	if err := os.WriteFile(p.opts.file, p.fileData, 0600); err != nil {
+		return p.fail(spinner, err)
+	}
+	spinner.UpdateMessage(fmt.Sprintf("Generating compose spec from the Docker image: saved to %s", p.opts.file))
+	spinner.Complete()
+
+	return nil
+}
+
+// runBuildServiceStage renders the compose spec and builds the service from it. In dry-run mode, the rendered
+// compose spec is written to a local file instead.
+func (p *buildFromRepoPipeline) runBuildServiceStage() error {
+	if p.opts.skipDockerBuild && p.composeSpecHasBuildContext {
+		if err := p.usePlaceholderImages(); err != nil {
+			return err
+		}
+	}
+
+	// Get the registry credentials if needed
+	if strings.Contains(string(p.fileData), p.opts.authProvider.SecretPlaceholder()) && p.registryCreds.Password == "" {
+		if err := p.resolveRegistryCredentials(true); err != nil {
+			return err
+		}
+	}
+
+	// Render the compose file: variable interpolation (if env_file appears), registry secret replacement, build context replacement
+	spinner := p.sm.AddSpinner("Rendering compose spec")
+
+	fileData := p.fileData
+	if strings.Contains(string(fileData), "env_file:") {
+		var err error
+		fileData, err = RenderEnvFileAndInterpolateVariables(fileData, p.rootDir, p.opts.file, p.sm, nil)
+		if err != nil {
+			return p.fail(spinner, err)
+		}
+	}
+
+	// Render the registry secret placeholder in the compose file if needed
+	if strings.Contains(string(fileData), p.opts.authProvider.SecretPlaceholder()) {
+		fileData = []byte(strings.ReplaceAll(string(fileData), p.opts.authProvider.SecretPlaceholder(), p.registryCreds.Password))
+	}
+
+	// Render build context sections into image fields in the compose file if needed
+	if p.composeSpecHasBuildContext {
+		dockerPathsToImageUrls := make(map[string]string)
+		for _, dockerfilePath := range p.dockerfilePaths {
+			image, _ := p.imageForDockerfile(dockerfilePath)
+			if p.opts.pinImageDigests {
+				dockerPathsToImageUrls[dockerfilePath] = image.PinnedImageURL()
+			} else {
+				dockerPathsToImageUrls[dockerfilePath] = image.ImageURL
+			}
+		}
+		fileData = []byte(utils.ReplaceBuildContext(string(fileData), dockerPathsToImageUrls))
+	}
+
+	spinner.UpdateMessage("Rendering compose spec: complete")
+	spinner.Complete()
+
+	// Building service from the compose spec
+	spinner = p.sm.AddSpinner("Building service from the compose spec")
+
+	// If we're in dry-run mode, save the compose spec to a file with '-dry-run' suffix
+	if p.opts.dryRun {
+		dryRunFile := p.dryRunFile()
+
+		// Write the compose spec to the dry-run file
+		if err := os.WriteFile(dryRunFile, fileData, 0600); err != nil {
+			return p.fail(spinner, err)
+		}
+
+		spinner.UpdateMessage(fmt.Sprintf("Dry run: Wrote compose spec to %s", dryRunFile))
+		spinner.Complete()
+		return nil
+	}
+
+	// Use custom service name if provided, otherwise use repo name
+	serviceNameToUse := p.serviceName()
+
+	// Prepare release description pointer, recording the git revision and image digests the service was built from
+	releaseDescription := releaseDescriptionWithBuildInfo(p.opts.releaseDescription, p.gitRepo, p.checkpoint.Outputs.Images)
+	var releaseDescriptionPtr *string
+	if releaseDescription != "" {
+		releaseDescriptionPtr = &releaseDescription
+	}
+
+	// Build the service
+	serviceID, devEnvironmentID, devPlanID, undefinedResources, err := buildService(
+		context.Background(),
+		fileData,
+		p.token,
+		serviceNameToUse,
+		DockerComposeSpecType,
+		nil,
+		nil,
+		nil,
+		nil,
+		true,
+		true,
+		releaseDescriptionPtr,
+		false,
+	)
+	if err != nil {
+		return p.fail(spinner, err)
+	}
+
+	p.checkpoint.Outputs.ServiceID = serviceID
+	p.checkpoint.Outputs.DevEnvironmentID = devEnvironmentID
+	p.checkpoint.Outputs.DevPlanID = devPlanID
+
+	spinner.UpdateMessage(fmt.Sprintf("Building service from the compose spec: built service %s (service ID: %s)", serviceNameToUse, serviceID))
+	spinner.Complete()
+
+	// Print warning if there are any undefined resources
+	if len(undefinedResources) > 0 {
+		p.sm.Stop()
+
+		utils.PrintWarning("The following resources appear in the service plan but were not defined in the spec:")
+		for resourceName, resourceID := range undefinedResources {
+			utils.PrintWarning(fmt.Sprintf("  %s: %s", resourceName, resourceID))
+		}
+		utils.PrintWarning("These resources were not processed during the build. If you no longer need them, please deprecate and remove them from the service plan manually in UI or using the API.")
+
+		p.restartSpinners()
+	}
+
+	return nil
+}
+
+// runCreateProdEnvStage creates the production environment if it doesn't exist yet.
+func (p *buildFromRepoPipeline) runCreateProdEnvStage() error {
+	outputs := &p.checkpoint.Outputs
+
+	// Check if the production environment is set up
+	spinner := p.sm.AddSpinner("Checking if the production environment is set up")
+	time.Sleep(1 * time.Second) // Add a delay to show the spinner
+	prodEnvironmentID, err := checkIfProdEnvExists(context.Background(), p.token, outputs.ServiceID)
+	if err != nil {
+		return p.fail(spinner, err)
+	}
+	yesOrNo := "No"
+	if prodEnvironmentID != "" {
+		yesOrNo = "Yes"
+	}
+	spinner.UpdateMessage(fmt.Sprintf("Checking if the production environment is set up: %s", yesOrNo))
+	spinner.Complete()
+
+	// Create a production environment if it does not exist
+	if prodEnvironmentID == "" {
+		spinner = p.sm.AddSpinner("Creating a production environment")
+		prodEnvironmentID, err = createProdEnv(context.Background(), p.token, outputs.ServiceID, outputs.DevEnvironmentID)
+		if err != nil {
+			return p.fail(spinner, err)
+		}
+		spinner.UpdateMessage(fmt.Sprintf("Creating a production environment: created environment %s (environment ID: %s)", DefaultProdEnvName, prodEnvironmentID))
+		spinner.Complete()
+	}
+
+	outputs.ProdEnvironmentID = prodEnvironmentID
+	return nil
+}
+
+// runPromoteStage promotes the service from the dev environment to the production environment.
+func (p *buildFromRepoPipeline) runPromoteStage() error {
+	spinner := p.sm.AddSpinner(fmt.Sprintf("Promoting the service to the %s environment", DefaultProdEnvName))
+	err := dataaccess.PromoteServiceEnvironment(context.Background(), p.token, p.checkpoint.Outputs.ServiceID, p.checkpoint.Outputs.DevEnvironmentID)
+	if err != nil {
+		return p.fail(spinner, err)
+	}
+	spinner.UpdateMessage("Promoting the service to the production environment: Success")
+	spinner.Complete()
+
+	return nil
+}
+
+// runSetDefaultStage sets the latest version of the promoted plan as the default in the production environment.
+func (p *buildFromRepoPipeline) runSetDefaultStage() error {
+	ctx := context.Background()
+	outputs := &p.checkpoint.Outputs
+
+	spinner := p.sm.AddSpinner("Setting the service plan as the default service plan in production")
+
+	// Describe the dev product tier
+	devProductTier, err := dataaccess.DescribeProductTier(ctx, p.token, outputs.ServiceID, outputs.DevPlanID)
+	if err != nil {
+		return p.fail(spinner, err)
+	}
+
+	// Find the production plan with the same name as the dev plan
+	var prodPlanID string
+	service, err := dataaccess.DescribeService(ctx, p.token, outputs.ServiceID)
+	if err != nil {
+		return p.fail(spinner, err)
+	}
+	for _, env := range service.ServiceEnvironments {
+		if env.Id != outputs.ProdEnvironmentID {
+			continue
+		}
+		for _, plan := range env.ServicePlans {
+			if plan.Name == devProductTier.Name {
+				prodPlanID = plan.ProductTierID
+				break
+			}
+		}
+	}
+
+	// Find the latest version of the production plan
+	targetVersion, err := dataaccess.FindLatestVersion(ctx, p.token, outputs.ServiceID, prodPlanID)
+	if err != nil {
+		return p.fail(spinner, err)
+	}
+
+	// Set the default service plan
+	_, err = dataaccess.SetDefaultServicePlan(ctx, p.token, outputs.ServiceID, prodPlanID, targetVersion)
+	if err != nil {
+		return p.fail(spinner, err)
+	}
+
+	outputs.ProdPlanID = prodPlanID
+	outputs.ProdPlanVersion = targetVersion
+
+	spinner.UpdateMessage("Setting current version as the default service plan version in production: Success")
+	spinner.Complete()
+
+	return nil
+}
+
+// runSaaSPortalInitStage waits for the SaaS Portal of the production environment to be ready.
+func (p *buildFromRepoPipeline) runSaaSPortalInitStage() error {
+	ctx := context.Background()
+	outputs := &p.checkpoint.Outputs
+
+	prodEnvironment, err := dataaccess.DescribeServiceEnvironment(ctx, p.token, outputs.ServiceID, outputs.ProdEnvironmentID)
+	if err != nil {
+		return err
+	}
+
+	if !checkIfSaaSPortalReady(prodEnvironment) {
+		spinner := p.sm.AddSpinner("Initializing the SaaS Portal. This may take a few minutes.")
+
+		for {
+			prodEnvironment, err = dataaccess.DescribeServiceEnvironment(ctx, p.token, outputs.ServiceID, outputs.ProdEnvironmentID)
+			if err != nil {
+				return p.fail(spinner, err)
+			}
+
+			if checkIfSaaSPortalReady(prodEnvironment) {
+				break
+			}
+
+			time.Sleep(5 * time.Second)
+		}
+
+		spinner.Complete()
+	}
+
+	// Retrieve the SaaS Portal URL
+	spinner := p.sm.AddSpinner("Retrieving the SaaS Portal URL")
+	time.Sleep(1 * time.Second) // Add a delay to show the spinner
+	outputs.SaaSPortalURL = getSaaSPortalURL(prodEnvironment, outputs.ServiceID, outputs.ProdEnvironmentID)
+	spinner.Complete()
+
+	return nil
+}
+
+// resolveRegistryCredentials resolves the registry credentials through the auth provider and records the username.
+func (p *buildFromRepoPipeline) resolveRegistryCredentials(interactive bool) (err error) {
+	p.sm, p.registryCreds, err = p.opts.authProvider.Credentials(p.sm, p.opts.registry, interactive)
+	if err != nil {
+		return err
+	}
+	if p.registryCreds.Username != "" {
+		p.checkpoint.Outputs.RegistryUsername = p.registryCreds.Username
+	}
+	return nil
+}
+
+// registryUsername returns the registry username, looking it up without prompting if it's not known yet.
+func (p *buildFromRepoPipeline) registryUsername() string {
+	if p.registryCreds.Username == "" && p.checkpoint.Outputs.RegistryUsername == "" {
+		if err := p.resolveRegistryCredentials(false); err != nil {
+			utils.PrintWarning(fmt.Sprintf("failed to look up the %s username: %v", p.opts.registry, err))
+		}
+	}
+	if p.registryCreds.Username != "" {
+		return p.registryCreds.Username
+	}
+	return p.checkpoint.Outputs.RegistryUsername
+}
+
+// usePlaceholderImages sets the image URLs the compose spec references when the docker build is skipped.
+func (p *buildFromRepoPipeline) usePlaceholderImages() error {
+	spinner := p.sm.AddSpinner(fmt.Sprintf("Getting %s username for compose spec", p.opts.registry))
+	username := p.registryUsername()
+	if username != "" {
+		spinner.UpdateMessage(fmt.Sprintf("Getting %s username for compose spec: %s", p.opts.registry, username))
+	} else {
+		spinner.UpdateMessage(fmt.Sprintf("Credentials for %s not found, will prompt if needed later", p.opts.registry))
+	}
+	spinner.Complete()
+
+	dockerfilePathsArr := p.dockerfilePathsArr()
+	for service, dockerfilePath := range p.dockerfilePaths {
+		if _, ok := p.imageForDockerfile(dockerfilePath); ok {
+			continue
+		}
+
+		imageUrl, err := renderImageName(p.opts.imageNameTemplate, imageNameData{
+			Registry: p.opts.registry,
+			Owner:    p.gitRepo.Remote.Owner,
+			Repo:     p.gitRepo.Remote.Repo,
+			Service:  service,
+			Label:    utils.GetFirstDifferentSegmentInFilePaths(dockerfilePath, dockerfilePathsArr),
+		})
+		if err != nil {
+			return err
+		}
+		p.setImageForDockerfile(dockerfilePath, builtImage{Service: service, ImageURL: fmt.Sprintf("%s:latest", imageUrl)})
+	}
+
+	return nil
+}
+
+// appendDeploymentSection appends the deployment section for the --deployment-type flag to the compose spec.
+func (p *buildFromRepoPipeline) appendDeploymentSection(fileData []byte) ([]byte, error) {
+	switch p.opts.deploymentType {
+	case "hosted":
+		fileData = append(fileData, []byte("  deployment:\n")...)
+		fileData = append(fileData, []byte("    hostedDeployment:\n")...)
+	case "byoa":
+		fileData = append(fileData, []byte("  deployment:\n")...)
+		fileData = append(fileData, []byte("    byoaDeployment:\n")...)
+	}
+
+	if p.opts.deploymentType != "" {
+		if p.opts.awsAccountID != "" {
+			fileData = append(fileData, []byte(fmt.Sprintf("      AwsAccountId: '%s'\n", p.opts.awsAccountID))...)
+			awsBootstrapRoleAccountARN := fmt.Sprintf("arn:aws:iam::%s:role/omnistrate-bootstrap-role", p.opts.awsAccountID)
+			fileData = append(fileData, []byte(fmt.Sprintf("      AwsBootstrapRoleAccountArn: '%s'\n", awsBootstrapRoleAccountARN))...)
+		}
+		if p.opts.gcpProjectID != "" {
+			fileData = append(fileData, []byte(fmt.Sprintf("      GcpProjectId: '%s'\n", p.opts.gcpProjectID))...)
+			fileData = append(fileData, []byte(fmt.Sprintf("      GcpProjectNumber: '%s'\n", p.opts.gcpProjectNumber))...)
+
+			// Get organization id
+			user, err := dataaccess.DescribeUser(context.Background(), p.token)
+			if err != nil {
+				return nil, err
+			}
+
+			gcpServiceAccountEmail := fmt.Sprintf("bootstrap-%s@%s.iam.gserviceaccount.com", *user.OrgId, p.opts.gcpProjectID)
+			fileData = append(fileData, []byte(fmt.Sprintf("      GcpServiceAccountEmail: '%s'\n", gcpServiceAccountEmail))...)
+		}
+	}
+
+	return fileData, nil
+}
+
+func (p *buildFromRepoPipeline) dockerfilePathsArr() []string {
+	dockerfilePathsArr := make([]string, 0)
+	for _, dockerfilePath := range p.dockerfilePaths {
+		dockerfilePathsArr = append(dockerfilePathsArr, dockerfilePath)
+	}
+	return dockerfilePathsArr
+}
+
+func (p *buildFromRepoPipeline) serviceName() string {
+	if p.opts.serviceName != "" {
+		return p.opts.serviceName
+	}
+	return p.gitRepo.Remote.Repo
+}
+
+func (p *buildFromRepoPipeline) dryRunFile() string {
+	fileExt := filepath.Ext(p.opts.file)
+	baseName := p.opts.file[:len(p.opts.file)-len(fileExt)]
+	return fmt.Sprintf("%s-dry-run%s", baseName, fileExt)
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
 )
 
 func TestRenderEnvFileAndInterpolateVariables(t *testing.T) {
@@ -30,3 +31,28 @@ func TestRenderEnvFileAndInterpolateVariables(t *testing.T) {
 	require.NoError(t, err, "Error rendering env file and interpolating variables: %v", err)
 	require.Equal(t, strings.ReplaceAll(string(result), " ", ""), strings.ReplaceAll(string(expectedFileData), " ", ""), "Rendered file content does not match expected content")
 }
+
+func TestReleaseDescriptionWithBuildInfo(t *testing.T) {
+	gitRepo := utils.GitRepository{
+		Remote:    utils.GitRemote{Host: "gitlab.com", Owner: "team", Repo: "service"},
+		CommitSHA: "0123456789abcdef0123456789abcdef01234567",
+		Branch:    "main",
+	}
+
+	require.Equal(t, "built from main@0123456", releaseDescriptionWithBuildInfo("", gitRepo, nil))
+	require.Equal(t, "v1.0.0 (built from main@0123456)", releaseDescriptionWithBuildInfo("v1.0.0", gitRepo, nil))
+
+	images := map[string]builtImage{
+		"Dockerfile": {Service: "web", ImageURL: "ghcr.io/team/service:sha-abc", Tag: "ghcr.io/team/service", Digest: "sha256:abc"},
+	}
+	require.Equal(t, "v1.0.0 (built from main@0123456; images: ghcr.io/team/service=sha256:abc)", releaseDescriptionWithBuildInfo("v1.0.0", gitRepo, images))
+
+	gitRepo.Branch = ""
+	require.Equal(t, "v1.0.0 (built from detached@0123456)", releaseDescriptionWithBuildInfo("v1.0.0", gitRepo, nil))
+
+	gitRepo.CommitSHA = ""
+	require.Equal(t, "v1.0.0", releaseDescriptionWithBuildInfo("v1.0.0", gitRepo, nil))
+	require.Equal(t, "images: ghcr.io/team/service=sha256:abc", releaseDescriptionWithBuildInfo("", gitRepo, images))
+
+	require.Equal(t, []string{"org.opencontainers.image.source=https://gitlab.com/team/service"}, imageLabels(gitRepo))
+}
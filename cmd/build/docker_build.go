@@ -0,0 +1,258 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	DefaultBuildParallelism = 4
+	buildLogTailLines       = 20
+)
+
+var imageDigestRegex = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// imageBuildJob is the Docker image of a compose service to build and push.
+type imageBuildJob struct {
+	Service        string
+	DockerfilePath string // Absolute path; the directory of the Dockerfile is the build context
+	ImageURL       string // Image URL without a tag
+}
+
+// imageBuildOptions are the settings shared by all image builds of a run.
+type imageBuildOptions struct {
+	platforms []string
+	labels    []string
+	push      bool // When false, the image is loaded into the local image store instead of pushed
+}
+
+// imageBuildFunc builds a single image, writing the build output to out.
+type imageBuildFunc func(ctx context.Context, job imageBuildJob, out io.Writer) (builtImage, error)
+
+// imageBuildError reports the service whose image failed to build along with the last lines of its build log.
+type imageBuildError struct {
+	Service string
+	Err     error
+	LogTail []string
+}
+
+func (e *imageBuildError) Error() string {
+	msg := fmt.Sprintf("failed to build the image for service %s: %v", e.Service, e.Err)
+	if len(e.LogTail) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s\nLast %d lines of the build log:\n  %s", msg, len(e.LogTail), strings.Join(e.LogTail, "\n  "))
+}
+
+func (e *imageBuildError) Unwrap() error {
+	return e.Err
+}
+
+// runImageBuilds runs the builds with at most parallelism builds at a time. The output of each build is
+// written to out line by line, prefixed with the service name. The first failing build cancels the others.
+// The built images are returned in the order of the jobs.
+func runImageBuilds(ctx context.Context, jobs []imageBuildJob, parallelism int, out io.Writer, build imageBuildFunc) ([]builtImage, error) {
+	if parallelism < 1 {
+		return nil, fmt.Errorf("parallelism must be at least 1, got %d", parallelism)
+	}
+
+	var outMu sync.Mutex
+	prefixWidth := 0
+	for _, job := range jobs {
+		prefixWidth = max(prefixWidth, len(job.Service))
+	}
+
+	images := make([]builtImage, len(jobs))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(parallelism)
+	for i, job := range jobs {
+		g.Go(func() error {
+			// Don't start builds after another build failed
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			w := newPrefixWriter(&outMu, out, fmt.Sprintf("[%-*s] ", prefixWidth, job.Service), buildLogTailLines)
+			image, err := build(ctx, job, w)
+			w.Flush()
+			if err != nil {
+				return &imageBuildError{Service: job.Service, Err: err, LogTail: w.Tail()}
+			}
+
+			images[i] = image
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// imageBuildJobsByService returns the jobs sorted by service name, so the builds start in a stable order.
+func imageBuildJobsByService(jobs map[string]imageBuildJob) []imageBuildJob {
+	sorted := make([]imageBuildJob, 0, len(jobs))
+	for _, job := range jobs {
+		sorted = append(sorted, job)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Service < sorted[j].Service
+	})
+	return sorted
+}
+
+// dockerBuildArgs returns the arguments of the single buildx invocation that builds the image for all platforms.
+func dockerBuildArgs(job imageBuildJob, opts imageBuildOptions, metadataFile string) []string {
+	args := []string{"buildx", "build", "--pull", "--progress", "plain", "--platform", strings.Join(opts.platforms, ","), "-f", job.DockerfilePath, "-t", job.ImageURL}
+	for _, label := range opts.labels {
+		args = append(args, "--label", label)
+	}
+	if opts.push {
+		args = append(args, "--push", "--metadata-file", metadataFile)
+	} else {
+		args = append(args, "--load")
+	}
+	return append(args, filepath.Dir(job.DockerfilePath))
+}
+
+// dockerImageBuilder returns a build function that builds the image with docker buildx and, when pushing,
+// tags the pushed image with its digest.
+func dockerImageBuilder(opts imageBuildOptions) imageBuildFunc {
+	return func(ctx context.Context, job imageBuildJob, out io.Writer) (image builtImage, err error) {
+		metadataFile, err := os.CreateTemp("", "omctl-build-metadata-*.json")
+		if err != nil {
+			return
+		}
+		metadataFile.Close()
+		defer os.Remove(metadataFile.Name())
+
+		if err = runDocker(ctx, out, dockerBuildArgs(job, opts, metadataFile.Name())...); err != nil {
+			return
+		}
+
+		if !opts.push {
+			image = builtImage{Service: job.Service, ImageURL: fmt.Sprintf("%s:latest", job.ImageURL)}
+			return
+		}
+
+		digest, err := readImageDigest(metadataFile.Name())
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(out, "Pushed %s with digest %s\n", job.ImageURL, digest)
+
+		// Tag the pushed image with its digest. imagetools works on the registry, so multi-platform images
+		// don't need to be loaded locally.
+		imageURLWithDigestTag := fmt.Sprintf("%s:sha-%s", job.ImageURL, strings.TrimPrefix(digest, "sha256:"))
+		if err = runDocker(ctx, out, "buildx", "imagetools", "create", "--tag", imageURLWithDigestTag, fmt.Sprintf("%s@%s", job.ImageURL, digest)); err != nil {
+			return
+		}
+
+		image = builtImage{Service: job.Service, ImageURL: imageURLWithDigestTag, Tag: job.ImageURL, Digest: digest}
+		return
+	}
+}
+
+func runDocker(ctx context.Context, out io.Writer, args ...string) error {
+	fmt.Fprintf(out, "Invoking 'docker %s'...\n", strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// readImageDigest reads the digest of the pushed image from the buildx metadata file.
+func readImageDigest(metadataFile string) (string, error) {
+	data, err := os.ReadFile(metadataFile)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the build metadata")
+	}
+
+	var metadata struct {
+		Digest string `json:"containerimage.digest"`
+	}
+	if err = json.Unmarshal(data, &metadata); err != nil {
+		return "", errors.Wrap(err, "failed to parse the build metadata")
+	}
+	if !imageDigestRegex.MatchString(metadata.Digest) {
+		return "", errors.New("unable to retrieve the digest")
+	}
+
+	return metadata.Digest, nil
+}
+
+// prefixWriter writes complete lines to out with a prefix and keeps the last lines written. Writers sharing
+// the same mutex don't interleave their lines.
+type prefixWriter struct {
+	mu       *sync.Mutex
+	out      io.Writer
+	prefix   string
+	pending  []byte
+	tail     []string
+	tailSize int
+}
+
+func newPrefixWriter(mu *sync.Mutex, out io.Writer, prefix string, tailSize int) *prefixWriter {
+	return &prefixWriter{mu: mu, out: out, prefix: prefix, tailSize: tailSize}
+}
+
+func (w *prefixWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, b...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(string(w.pending[:i]))
+		w.pending = w.pending[i+1:]
+	}
+
+	return len(b), nil
+}
+
+// Flush writes the last line if it isn't terminated by a newline.
+func (w *prefixWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) > 0 {
+		w.writeLine(string(w.pending))
+		w.pending = nil
+	}
+}
+
+// Tail returns the last lines written.
+func (w *prefixWriter) Tail() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string(nil), w.tail...)
+}
+
+func (w *prefixWriter) writeLine(line string) {
+	line = strings.TrimRight(line, "\r")
+	fmt.Fprintf(w.out, "%s%s\n", w.prefix, line)
+
+	w.tail = append(w.tail, line)
+	if len(w.tail) > w.tailSize {
+		w.tail = w.tail[len(w.tail)-w.tailSize:]
+	}
+}
@@ -0,0 +1,127 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var mu sync.Mutex
+	var out bytes.Buffer
+	w := newPrefixWriter(&mu, &out, "[api] ", 2)
+
+	_, err := w.Write([]byte("step 1\nstep"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte(" 2\r\nstep 3"))
+	require.NoError(t, err)
+	require.Equal(t, "[api] step 1\n[api] step 2\n", out.String())
+
+	w.Flush()
+	require.Equal(t, "[api] step 1\n[api] step 2\n[api] step 3\n", out.String())
+	require.Equal(t, []string{"step 2", "step 3"}, w.Tail())
+}
+
+func TestDockerBuildArgs(t *testing.T) {
+	job := imageBuildJob{Service: "api", DockerfilePath: filepath.Join("/repo", "api", "Dockerfile"), ImageURL: "ghcr.io/org/repo-api"}
+	opts := imageBuildOptions{platforms: []string{"linux/amd64", "linux/arm64"}, labels: []string{"a=b"}, push: true}
+
+	args := dockerBuildArgs(job, opts, "/tmp/metadata.json")
+	require.Equal(t, []string{
+		"buildx", "build", "--pull", "--progress", "plain", "--platform", "linux/amd64,linux/arm64",
+		"-f", filepath.Join("/repo", "api", "Dockerfile"), "-t", "ghcr.io/org/repo-api", "--label", "a=b",
+		"--push", "--metadata-file", "/tmp/metadata.json", filepath.Join("/repo", "api"),
+	}, args)
+
+	opts.push = false
+	args = dockerBuildArgs(job, opts, "/tmp/metadata.json")
+	require.Contains(t, args, "--load")
+	require.NotContains(t, args, "--push")
+}
+
+func TestReadImageDigest(t *testing.T) {
+	dir := t.TempDir()
+	metadataFile := filepath.Join(dir, "metadata.json")
+
+	require.NoError(t, os.WriteFile(metadataFile, []byte(`{"containerimage.digest": "`+testDigest+`"}`), 0600))
+	digest, err := readImageDigest(metadataFile)
+	require.NoError(t, err)
+	require.Equal(t, testDigest, digest)
+
+	require.NoError(t, os.WriteFile(metadataFile, []byte(`{}`), 0600))
+	_, err = readImageDigest(metadataFile)
+	require.Error(t, err)
+}
+
+func TestRunImageBuilds(t *testing.T) {
+	jobs := imageBuildJobsByService(map[string]imageBuildJob{
+		"worker": {Service: "worker", ImageURL: "ghcr.io/org/repo-worker"},
+		"api":    {Service: "api", ImageURL: "ghcr.io/org/repo-api"},
+		"web":    {Service: "web", ImageURL: "ghcr.io/org/repo-web"},
+	})
+
+	var running, maxRunning int32
+	var out bytes.Buffer
+	images, err := runImageBuilds(context.Background(), jobs, 2, &out, func(ctx context.Context, job imageBuildJob, w io.Writer) (builtImage, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprintf(w, "building %s\n", job.ImageURL)
+		return builtImage{Service: job.Service, ImageURL: job.ImageURL + ":sha-1"}, nil
+	})
+	require.NoError(t, err)
+	require.LessOrEqual(t, maxRunning, int32(2))
+	require.Equal(t, []string{"api", "web", "worker"}, []string{images[0].Service, images[1].Service, images[2].Service})
+	require.Contains(t, out.String(), "[api   ] building ghcr.io/org/repo-api\n")
+	require.Contains(t, out.String(), "[worker] building ghcr.io/org/repo-worker\n")
+}
+
+func TestRunImageBuildsCancelsOnFailure(t *testing.T) {
+	jobs := imageBuildJobsByService(map[string]imageBuildJob{
+		"api":    {Service: "api"},
+		"worker": {Service: "worker"},
+	})
+
+	var out bytes.Buffer
+	_, err := runImageBuilds(context.Background(), jobs, 2, &out, func(ctx context.Context, job imageBuildJob, w io.Writer) (builtImage, error) {
+		if job.Service == "api" {
+			fmt.Fprint(w, "step 1\nERROR: failed to solve")
+			return builtImage{}, errors.New("exit status 1")
+		}
+
+		// Wait for the failing build to cancel this one
+		select {
+		case <-ctx.Done():
+			return builtImage{}, ctx.Err()
+		case <-time.After(5 * time.Second):
+			return builtImage{}, errors.New("build was not cancelled")
+		}
+	})
+
+	var buildErr *imageBuildError
+	require.ErrorAs(t, err, &buildErr)
+	require.Equal(t, "api", buildErr.Service)
+	require.Equal(t, []string{"step 1", "ERROR: failed to solve"}, buildErr.LogTail)
+	require.True(t, strings.Contains(err.Error(), "failed to build the image for service api: exit status 1"))
+	require.True(t, strings.HasSuffix(err.Error(), "  ERROR: failed to solve"))
+
+	_, err = runImageBuilds(context.Background(), jobs, 0, &out, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,106 @@
+package build
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/pkg/errors"
+)
+
+var digestPinnedImageRegex = regexp.MustCompile(`^[^@\s]+@sha256:[0-9a-f]{64}$`)
+
+// isDigestPinned reports whether the image reference is pinned by digest, e.g. ghcr.io/org/repo@sha256:<digest>.
+func isDigestPinned(image string) bool {
+	return digestPinnedImageRegex.MatchString(strings.TrimSpace(image))
+}
+
+// imageRepository strips the tag and digest from an image reference. The registry port is kept.
+func imageRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	lastSlash := strings.LastIndex(image, "/")
+	if lastColon := strings.LastIndex(image, ":"); lastColon > lastSlash {
+		image = image[:lastColon]
+	}
+	return image
+}
+
+// PinnedImageURL returns the image reference pinned by digest, or the tagged image URL if the digest is unknown.
+func (i builtImage) PinnedImageURL() string {
+	if i.Digest == "" {
+		return i.ImageURL
+	}
+	return fmt.Sprintf("%s@%s", imageRepository(i.ImageURL), i.Digest)
+}
+
+// imageDigestsDescription describes which digest each pushed tag pointed to at build time, sorted by tag.
+func imageDigestsDescription(images map[string]builtImage) string {
+	var mappings []string
+	for _, image := range images {
+		if image.Digest == "" {
+			continue
+		}
+		tag := image.Tag
+		if tag == "" {
+			tag = image.ImageURL
+		}
+		mappings = append(mappings, fmt.Sprintf("%s=%s", tag, image.Digest))
+	}
+	if len(mappings) == 0 {
+		return ""
+	}
+
+	sort.Strings(mappings)
+	return fmt.Sprintf("images: %s", strings.Join(mappings, ", "))
+}
+
+// findUnpinnedComposeImages returns the services of a compose spec whose image is not pinned by digest,
+// mapped to their image reference. Services without an image, such as the ones only defining a build, are skipped.
+func findUnpinnedComposeImages(fileData []byte) (map[string]string, error) {
+	parsedYaml, err := loader.ParseYAML(fileData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse compose spec")
+	}
+
+	services, ok := parsedYaml["services"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	unpinned := make(map[string]string)
+	for name, service := range services {
+		serviceConfig, _ := service.(map[string]interface{})
+		image, ok := serviceConfig["image"].(string)
+		if ok && !isDigestPinned(image) {
+			unpinned[name] = image
+		}
+	}
+
+	return unpinned, nil
+}
+
+// verifyComposeImagesPinned returns an error listing every service whose image is not pinned by digest.
+func verifyComposeImagesPinned(fileData []byte) error {
+	unpinned, err := findUnpinnedComposeImages(fileData)
+	if err != nil {
+		return err
+	}
+	if len(unpinned) == 0 {
+		return nil
+	}
+
+	services := make([]string, 0, len(unpinned))
+	for service := range unpinned {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	var details []string
+	for _, service := range services {
+		details = append(details, fmt.Sprintf("%s (%s)", service, unpinned[service]))
+	}
+
+	return fmt.Errorf("the following services use images that are not pinned by digest (image@sha256:<digest>): %s", strings.Join(details, ", "))
+}
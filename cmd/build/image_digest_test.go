@@ -0,0 +1,85 @@
+package build
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestImageRepository(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"ghcr.io/org/repo", "ghcr.io/org/repo"},
+		{"ghcr.io/org/repo:sha-123", "ghcr.io/org/repo"},
+		{"ghcr.io/org/repo@" + testDigest, "ghcr.io/org/repo"},
+		{"ghcr.io/org/repo:v1@" + testDigest, "ghcr.io/org/repo"},
+		{"registry.example.com:5000/repo:v1", "registry.example.com:5000/repo"},
+		{"registry.example.com:5000/repo", "registry.example.com:5000/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			require.Equal(t, tt.expected, imageRepository(tt.image))
+		})
+	}
+}
+
+func TestPinnedImageURL(t *testing.T) {
+	image := builtImage{Service: "web", ImageURL: "registry.example.com:5000/org/repo:sha-0123", Digest: testDigest}
+	require.Equal(t, "registry.example.com:5000/org/repo@"+testDigest, image.PinnedImageURL())
+	require.True(t, isDigestPinned(image.PinnedImageURL()))
+
+	image.Digest = ""
+	require.Equal(t, "registry.example.com:5000/org/repo:sha-0123", image.PinnedImageURL())
+	require.False(t, isDigestPinned(image.PinnedImageURL()))
+}
+
+func TestImageDigestsDescription(t *testing.T) {
+	require.Equal(t, "", imageDigestsDescription(nil))
+	require.Equal(t, "", imageDigestsDescription(map[string]builtImage{
+		"Dockerfile": {Service: "web", ImageURL: "ghcr.io/org/repo:latest"},
+	}))
+
+	description := imageDigestsDescription(map[string]builtImage{
+		"worker/Dockerfile": {Service: "worker", ImageURL: "ghcr.io/org/repo-worker:sha-2", Tag: "ghcr.io/org/repo-worker", Digest: "sha256:2"},
+		"api/Dockerfile":    {Service: "api", ImageURL: "ghcr.io/org/repo-api:sha-1", Tag: "ghcr.io/org/repo-api", Digest: "sha256:1"},
+	})
+	require.Equal(t, "images: ghcr.io/org/repo-api=sha256:1, ghcr.io/org/repo-worker=sha256:2", description)
+}
+
+func TestVerifyComposeImagesPinned(t *testing.T) {
+	pinned := `
+services:
+  api:
+    image: ghcr.io/org/api@` + testDigest + `
+  db:
+    image: postgres:16@` + testDigest + `
+  worker:
+    build:
+      context: .
+`
+	require.NoError(t, verifyComposeImagesPinned([]byte(pinned)))
+
+	unpinned := `
+services:
+  api:
+    image: ghcr.io/org/api:latest
+  db:
+    image: postgres@sha256:short
+  worker:
+    build:
+      context: .
+  cache:
+    image: redis@` + testDigest + `
+`
+	err := verifyComposeImagesPinned([]byte(unpinned))
+	require.Error(t, err)
+	require.True(t, strings.HasSuffix(err.Error(), "api (ghcr.io/org/api:latest), db (postgres@sha256:short)"), err.Error())
+
+	require.Error(t, verifyComposeImagesPinned([]byte("services: [")))
+}
@@ -0,0 +1,379 @@
+package build
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/mitchellh/go-homedir"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultRegistry          = "ghcr.io"
+	DefaultImageNameTemplate = "{{.Registry}}/{{.Owner}}/{{.Repo}}{{if .Label}}-{{.Label}}{{end}}"
+
+	RegistryAuthGitHubPAT        = "github-pat"
+	RegistryAuthDockerConfig     = "docker-config"
+	RegistryAuthPasswordStdin    = "password-stdin"
+	RegistryAuthCredentialHelper = "credential-helper"
+
+	gitHubPATSecretPlaceholder        = "${{ secrets.GitHubPAT }}"
+	registryPasswordSecretPlaceholder = "${{ secrets.RegistryPassword }}"
+)
+
+var validRegistryAuthTypes = []string{RegistryAuthGitHubPAT, RegistryAuthDockerConfig, RegistryAuthPasswordStdin, RegistryAuthCredentialHelper}
+
+// registryCredentials are used to push images to the registry and to let Omnistrate pull them.
+type registryCredentials struct {
+	Username string
+	Password string
+}
+
+// registryAuthProvider resolves the credentials for the container registry that build-from-repo pushes to.
+type registryAuthProvider interface {
+	// Name returns the identifier accepted by --registry-auth.
+	Name() string
+	// Preflight checks that the tooling the provider relies on is available.
+	Preflight() error
+	// Credentials resolves the credentials for the registry. When interactive is false the provider
+	// must not prompt the user and may return empty credentials.
+	Credentials(sm ysmrr.SpinnerManager, registry string, interactive bool) (ysmrr.SpinnerManager, registryCredentials, error)
+	// LoginRequired reports whether docker login has to be run with the resolved credentials before pushing.
+	LoginRequired() bool
+	// SecretPlaceholder returns the placeholder that replaces the password in the compose spec written to disk.
+	SecretPlaceholder() string
+}
+
+// imageNameData is the data available to the --image-name-template flag.
+type imageNameData struct {
+	Registry string
+	Owner    string
+	Repo     string
+	Service  string
+	Label    string
+}
+
+func newRegistryAuthProvider(authType, registry, username, credentialHelper string, resetPAT bool) (registryAuthProvider, error) {
+	if authType == "" {
+		authType = RegistryAuthDockerConfig
+		if registry == DefaultRegistry {
+			authType = RegistryAuthGitHubPAT
+		}
+	}
+
+	switch authType {
+	case RegistryAuthGitHubPAT:
+		return &gitHubPATAuthProvider{resetPAT: resetPAT}, nil
+	case RegistryAuthDockerConfig:
+		return &dockerConfigAuthProvider{}, nil
+	case RegistryAuthPasswordStdin:
+		if username == "" {
+			return nil, errors.New("--registry-username is required with --registry-auth password-stdin")
+		}
+		return &passwordStdinAuthProvider{username: username, stdin: os.Stdin}, nil
+	case RegistryAuthCredentialHelper:
+		if credentialHelper == "" {
+			return nil, errors.New("--registry-credential-helper is required with --registry-auth credential-helper")
+		}
+		return &credentialHelperAuthProvider{helper: credentialHelper}, nil
+	default:
+		return nil, fmt.Errorf("invalid registry auth type: %s. Options: %s", authType, strings.Join(validRegistryAuthTypes, ", "))
+	}
+}
+
+// renderImageName renders the image repository URL for a service using the image name template.
+func renderImageName(nameTemplate string, data imageNameData) (string, error) {
+	tmpl, err := template.New("image-name").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid image name template")
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to render image name template")
+	}
+
+	imageName := strings.ToLower(strings.TrimSpace(buf.String()))
+	if !strings.HasPrefix(imageName, strings.ToLower(data.Registry)+"/") {
+		return "", fmt.Errorf("image name %s rendered from template must start with the registry %s", imageName, data.Registry)
+	}
+
+	return imageName, nil
+}
+
+// registryAttributesSection returns the x-omnistrate-image-registry-attributes section for the registry.
+func registryAttributesSection(registry, username, passwordPlaceholder string) string {
+	return fmt.Sprintf(`
+x-omnistrate-image-registry-attributes:
+  %s:
+    auth:
+      password: %s
+      username: %s
+`, registry, passwordPlaceholder, username)
+}
+
+// gitHubPATAuthProvider authenticates with a GitHub Personal Access Token. This is the default for ghcr.io.
+type gitHubPATAuthProvider struct {
+	resetPAT bool
+}
+
+func (p *gitHubPATAuthProvider) Name() string {
+	return RegistryAuthGitHubPAT
+}
+
+func (p *gitHubPATAuthProvider) Preflight() error {
+	if config.IsGithubTokenEnvVarConfigured() {
+		return nil
+	}
+	return exec.Command("gh", "version").Run()
+}
+
+func (p *gitHubPATAuthProvider) Credentials(sm ysmrr.SpinnerManager, registry string, interactive bool) (ysmrr.SpinnerManager, registryCredentials, error) {
+	var creds registryCredentials
+	var err error
+
+	if interactive {
+		sm, creds.Password, err = getOrCreatePAT(sm, p.resetPAT)
+		if err != nil {
+			return sm, creds, err
+		}
+	} else {
+		creds.Password, err = config.LookupGitHubPersonalAccessToken()
+		if err != nil && !errors.As(err, &config.ErrGitHubPATNotFound) {
+			return sm, creds, err
+		}
+		if err != nil {
+			return sm, creds, nil
+		}
+	}
+
+	if config.IsGithubTokenEnvVarConfigured() {
+		creds.Username = config.GithubTokenUserName
+		return sm, creds, nil
+	}
+
+	ghUsernameOutput, err := exec.Command("gh", "api", "user", "-q", ".login").Output()
+	if err != nil {
+		return sm, creds, err
+	}
+	creds.Username = strings.TrimSpace(string(ghUsernameOutput))
+
+	return sm, creds, nil
+}
+
+func (p *gitHubPATAuthProvider) LoginRequired() bool {
+	return true
+}
+
+func (p *gitHubPATAuthProvider) SecretPlaceholder() string {
+	return gitHubPATSecretPlaceholder
+}
+
+// dockerConfigAuthProvider reuses the credentials docker already has for the registry, either stored in the
+// docker config file or provided by the credential helper / credential store configured there.
+type dockerConfigAuthProvider struct{}
+
+// dockerConfigFile is the subset of ~/.docker/config.json used to resolve registry credentials.
+type dockerConfigFile struct {
+	Auths map[string]struct {
+		Auth string `json:"auth,omitempty"`
+	} `json:"auths,omitempty"`
+	CredsStore  string            `json:"credsStore,omitempty"`
+	CredHelpers map[string]string `json:"credHelpers,omitempty"`
+}
+
+func (p *dockerConfigAuthProvider) Name() string {
+	return RegistryAuthDockerConfig
+}
+
+func (p *dockerConfigAuthProvider) Preflight() error {
+	return nil
+}
+
+func (p *dockerConfigAuthProvider) Credentials(sm ysmrr.SpinnerManager, registry string, interactive bool) (ysmrr.SpinnerManager, registryCredentials, error) {
+	configPath, err := dockerConfigPath()
+	if err != nil {
+		return sm, registryCredentials{}, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) && !interactive {
+			return sm, registryCredentials{}, nil
+		}
+		return sm, registryCredentials{}, errors.Wrapf(err, "failed to read docker config %s", configPath)
+	}
+
+	creds, err := credentialsFromDockerConfig(data, registry, lookupCredentialHelper)
+	if err != nil && !interactive {
+		return sm, registryCredentials{}, nil
+	}
+
+	return sm, creds, err
+}
+
+func (p *dockerConfigAuthProvider) LoginRequired() bool {
+	return false
+}
+
+func (p *dockerConfigAuthProvider) SecretPlaceholder() string {
+	return registryPasswordSecretPlaceholder
+}
+
+// passwordStdinAuthProvider reads the registry password from stdin, similar to docker login --password-stdin.
+type passwordStdinAuthProvider struct {
+	username string
+	password string
+	stdin    io.Reader
+}
+
+func (p *passwordStdinAuthProvider) Name() string {
+	return RegistryAuthPasswordStdin
+}
+
+func (p *passwordStdinAuthProvider) Preflight() error {
+	return nil
+}
+
+func (p *passwordStdinAuthProvider) Credentials(sm ysmrr.SpinnerManager, registry string, interactive bool) (ysmrr.SpinnerManager, registryCredentials, error) {
+	if p.password == "" {
+		passwordFromStdin, err := io.ReadAll(p.stdin)
+		if err != nil {
+			return sm, registryCredentials{}, errors.Wrap(err, "failed to read registry password from stdin")
+		}
+		p.password = strings.TrimRight(string(passwordFromStdin), "\r\n")
+		if p.password == "" {
+			return sm, registryCredentials{}, errors.New("no registry password provided on stdin")
+		}
+	}
+
+	return sm, registryCredentials{Username: p.username, Password: p.password}, nil
+}
+
+func (p *passwordStdinAuthProvider) LoginRequired() bool {
+	return true
+}
+
+func (p *passwordStdinAuthProvider) SecretPlaceholder() string {
+	return registryPasswordSecretPlaceholder
+}
+
+// credentialHelperAuthProvider resolves credentials from a docker credential helper, e.g. ecr-login or gcloud.
+type credentialHelperAuthProvider struct {
+	helper string
+}
+
+func (p *credentialHelperAuthProvider) Name() string {
+	return RegistryAuthCredentialHelper
+}
+
+func (p *credentialHelperAuthProvider) Preflight() error {
+	if _, err := exec.LookPath(credentialHelperBinary(p.helper)); err != nil {
+		return fmt.Errorf("credential helper %s not found in PATH", credentialHelperBinary(p.helper))
+	}
+	return nil
+}
+
+func (p *credentialHelperAuthProvider) Credentials(sm ysmrr.SpinnerManager, registry string, interactive bool) (ysmrr.SpinnerManager, registryCredentials, error) {
+	creds, err := lookupCredentialHelper(p.helper, registry)
+	return sm, creds, err
+}
+
+func (p *credentialHelperAuthProvider) LoginRequired() bool {
+	// The helper is not necessarily configured for the registry in the docker config, so docker has to log in
+	// with the credentials it resolved.
+	return true
+}
+
+func (p *credentialHelperAuthProvider) SecretPlaceholder() string {
+	return registryPasswordSecretPlaceholder
+}
+
+func dockerConfigPath() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+
+	dir, err := homedir.Expand("~/.docker")
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// credentialsFromDockerConfig resolves the credentials for the registry from the docker config file content. Per-registry
+// credential helpers take precedence over inline auths, which take precedence over the global credential store.
+func credentialsFromDockerConfig(data []byte, registry string, helperLookup func(helper, registry string) (registryCredentials, error)) (registryCredentials, error) {
+	var dockerConfig dockerConfigFile
+	if err := json.Unmarshal(data, &dockerConfig); err != nil {
+		return registryCredentials{}, errors.Wrap(err, "failed to parse docker config")
+	}
+
+	if helper, ok := dockerConfig.CredHelpers[registry]; ok && helper != "" {
+		return helperLookup(helper, registry)
+	}
+
+	for _, key := range []string{registry, "https://" + registry, "http://" + registry} {
+		authEntry, ok := dockerConfig.Auths[key]
+		if !ok || authEntry.Auth == "" {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(authEntry.Auth)
+		if err != nil {
+			return registryCredentials{}, errors.Wrapf(err, "failed to decode docker config auth for %s", registry)
+		}
+
+		username, password, found := strings.Cut(string(decoded), ":")
+		if !found {
+			return registryCredentials{}, fmt.Errorf("invalid docker config auth for %s", registry)
+		}
+
+		return registryCredentials{Username: username, Password: password}, nil
+	}
+
+	if dockerConfig.CredsStore != "" {
+		return helperLookup(dockerConfig.CredsStore, registry)
+	}
+
+	return registryCredentials{}, fmt.Errorf("no credentials found for %s in docker config, run 'docker login %s' first", registry, registry)
+}
+
+func credentialHelperBinary(helper string) string {
+	return "docker-credential-" + helper
+}
+
+// lookupCredentialHelper runs the docker credential helper protocol's get command for the registry.
+func lookupCredentialHelper(helper, registry string) (registryCredentials, error) {
+	helperCmd := exec.Command(credentialHelperBinary(helper), "get")
+	helperCmd.Stdin = strings.NewReader(registry)
+
+	var stdout, stderr bytes.Buffer
+	helperCmd.Stdout = &stdout
+	helperCmd.Stderr = &stderr
+
+	if err := helperCmd.Run(); err != nil {
+		return registryCredentials{}, fmt.Errorf("credential helper %s failed for %s: %s", credentialHelperBinary(helper), registry, strings.TrimSpace(stdout.String()+stderr.String()))
+	}
+
+	var res struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		return registryCredentials{}, errors.Wrapf(err, "failed to parse output of credential helper %s", credentialHelperBinary(helper))
+	}
+
+	return registryCredentials{Username: res.Username, Password: res.Secret}, nil
+}
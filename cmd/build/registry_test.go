@@ -0,0 +1,119 @@
+package build
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderImageName(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		data        imageNameData
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "Default template without label",
+			template: DefaultImageNameTemplate,
+			data:     imageNameData{Registry: "ghcr.io", Owner: "Omnistrate-OSS", Repo: "my-app"},
+			expected: "ghcr.io/omnistrate-oss/my-app",
+		},
+		{
+			name:     "Default template with label",
+			template: DefaultImageNameTemplate,
+			data:     imageNameData{Registry: "ghcr.io", Owner: "omnistrate-oss", Repo: "my-app", Label: "Backend"},
+			expected: "ghcr.io/omnistrate-oss/my-app-backend",
+		},
+		{
+			name:     "ECR template",
+			template: "{{.Registry}}/{{.Repo}}/{{.Service}}",
+			data:     imageNameData{Registry: "123456789012.dkr.ecr.us-east-1.amazonaws.com", Repo: "my-app", Service: "api"},
+			expected: "123456789012.dkr.ecr.us-east-1.amazonaws.com/my-app/api",
+		},
+		{
+			name:        "Template outside of registry",
+			template:    "docker.io/{{.Owner}}/{{.Repo}}",
+			data:        imageNameData{Registry: "ghcr.io", Owner: "omnistrate-oss", Repo: "my-app"},
+			expectError: true,
+		},
+		{
+			name:        "Unknown field",
+			template:    "{{.Registry}}/{{.Project}}",
+			data:        imageNameData{Registry: "ghcr.io"},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			imageName, err := renderImageName(test.template, test.data)
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, imageName)
+		})
+	}
+}
+
+func TestCredentialsFromDockerConfig(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot$ci:secret:with:colons"))
+	helperLookup := func(helper, registry string) (registryCredentials, error) {
+		return registryCredentials{Username: helper, Password: registry}, nil
+	}
+
+	tests := []struct {
+		name        string
+		config      string
+		registry    string
+		expected    registryCredentials
+		expectError bool
+	}{
+		{
+			name:     "Inline auth",
+			config:   `{"auths": {"harbor.example.com": {"auth": "` + auth + `"}}}`,
+			registry: "harbor.example.com",
+			expected: registryCredentials{Username: "robot$ci", Password: "secret:with:colons"},
+		},
+		{
+			name:     "Inline auth with scheme",
+			config:   `{"auths": {"https://harbor.example.com": {"auth": "` + auth + `"}}}`,
+			registry: "harbor.example.com",
+			expected: registryCredentials{Username: "robot$ci", Password: "secret:with:colons"},
+		},
+		{
+			name:     "Per-registry credential helper takes precedence",
+			config:   `{"auths": {"us-docker.pkg.dev": {"auth": "` + auth + `"}}, "credHelpers": {"us-docker.pkg.dev": "gcloud"}}`,
+			registry: "us-docker.pkg.dev",
+			expected: registryCredentials{Username: "gcloud", Password: "us-docker.pkg.dev"},
+		},
+		{
+			name:     "Credential store fallback",
+			config:   `{"auths": {"ghcr.io": {}}, "credsStore": "desktop"}`,
+			registry: "ghcr.io",
+			expected: registryCredentials{Username: "desktop", Password: "ghcr.io"},
+		},
+		{
+			name:        "No credentials",
+			config:      `{"auths": {"ghcr.io": {"auth": "` + auth + `"}}}`,
+			registry:    "harbor.example.com",
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			creds, err := credentialsFromDockerConfig([]byte(test.config), test.registry, helperLookup)
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, creds)
+		})
+	}
+}
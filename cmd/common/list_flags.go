@@ -0,0 +1,65 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	SortByFlag    string = "sort-by"
+	LimitFlag     string = "limit"
+	OffsetFlag    string = "offset"
+	ColumnsFlag   string = "columns"
+	NoHeadersFlag string = "no-headers"
+)
+
+// AddListFlags adds the flags for sorting, paginating and selecting the columns of list output. The keys are the
+// JSON field names of the listed model, as returned by utils.GetSupportedFilterKeys.
+func AddListFlags(cmd *cobra.Command, supportedKeys []string) {
+	cmd.Flags().String(SortByFlag, "", fmt.Sprintf("Sort by a field, optionally followed by ',desc' for descending order, e.g. status,desc. Numbers and timestamps are compared by value. Supported fields: %s", strings.Join(supportedKeys, ",")))
+	cmd.Flags().Int(LimitFlag, 0, "Maximum number of results to print, after filtering and sorting. 0 prints all results")
+	cmd.Flags().Int(OffsetFlag, 0, "Number of results to skip, after filtering and sorting")
+	cmd.Flags().String(ColumnsFlag, "", "Comma-separated list of fields to print, in order, e.g. instance_id,status. Defaults to all fields")
+	cmd.Flags().Bool(NoHeadersFlag, false, "Don't print the column headers in text and table output, e.g. for piping into other tools")
+}
+
+// GetListOptions reads and validates the flags added by AddListFlags.
+func GetListOptions(cmd *cobra.Command, supportedKeys []string) (opts utils.ListOptions, err error) {
+	sortBy, err := cmd.Flags().GetString(SortByFlag)
+	if err != nil {
+		return
+	}
+	if opts.SortBy, opts.SortDescending, err = utils.ParseSortBy(sortBy, supportedKeys); err != nil {
+		return
+	}
+
+	if opts.Limit, err = cmd.Flags().GetInt(LimitFlag); err != nil {
+		return
+	}
+	if opts.Limit < 0 {
+		err = fmt.Errorf("--%s must not be negative", LimitFlag)
+		return
+	}
+
+	if opts.Offset, err = cmd.Flags().GetInt(OffsetFlag); err != nil {
+		return
+	}
+	if opts.Offset < 0 {
+		err = fmt.Errorf("--%s must not be negative", OffsetFlag)
+		return
+	}
+
+	columns, err := cmd.Flags().GetString(ColumnsFlag)
+	if err != nil {
+		return
+	}
+	if opts.Columns, err = utils.ParseColumns(columns, supportedKeys); err != nil {
+		return
+	}
+
+	opts.NoHeaders, err = cmd.Flags().GetBool(NoHeadersFlag)
+	return
+}
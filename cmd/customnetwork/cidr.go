@@ -0,0 +1,153 @@
+package customnetwork
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+)
+
+// prefixLengthLimits are the network sizes each cloud provider accepts for a VPC or virtual network.
+var prefixLengthLimits = map[string]struct{ min, max int }{
+	"aws":   {16, 28},
+	"azure": {8, 29},
+	"gcp":   {8, 29},
+}
+
+// reservedPrefixes can't be used as network ranges.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// parseNetworkCIDR parses an IPv4 network CIDR block and checks that the cloud provider accepts it.
+func parseNetworkCIDR(cidr, cloudProvider string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR block %s: %w", cidr, err)
+	}
+	if !prefix.Addr().Is4() {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR block %s: only IPv4 networks are supported", cidr)
+	}
+	if prefix.Masked() != prefix {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR block %s: host bits are set, did you mean %s?", cidr, prefix.Masked())
+	}
+
+	for _, reserved := range reservedPrefixes {
+		if reserved.Overlaps(prefix) {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR block %s: overlaps the reserved range %s", cidr, reserved)
+		}
+	}
+
+	if limits, ok := prefixLengthLimits[strings.ToLower(cloudProvider)]; ok {
+		if prefix.Bits() < limits.min || prefix.Bits() > limits.max {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR block %s: %s networks must be between /%d and /%d", cidr, cloudProvider, limits.min, limits.max)
+		}
+	}
+
+	return prefix, nil
+}
+
+// findOverlappingNetworks returns the networks whose CIDR block overlaps the prefix. Networks with an unparsable CIDR
+// block are ignored.
+func findOverlappingNetworks(prefix netip.Prefix, networks []model.CustomNetwork) (overlapping []model.CustomNetwork) {
+	for _, network := range networks {
+		existing, err := netip.ParsePrefix(network.CIDR)
+		if err != nil {
+			continue
+		}
+		if existing.Overlaps(prefix) {
+			overlapping = append(overlapping, network)
+		}
+	}
+	return
+}
+
+// overlapError describes the networks a CIDR block overlaps with.
+func overlapError(prefix netip.Prefix, overlapping []model.CustomNetwork) error {
+	descriptions := make([]string, 0, len(overlapping))
+	for _, network := range overlapping {
+		description := fmt.Sprintf("%s (%s)", network.CustomNetworkID, network.CIDR)
+		if network.CustomNetworkName != "" {
+			description = fmt.Sprintf("%s %s (%s)", network.CustomNetworkID, network.CustomNetworkName, network.CIDR)
+		}
+		descriptions = append(descriptions, description)
+	}
+	return fmt.Errorf("CIDR block %s overlaps with existing custom networks in the same cloud provider and region: %s",
+		prefix, strings.Join(descriptions, ", "))
+}
+
+// planCIDRBlocks returns the first count blocks with the given prefix length inside the supernet that don't overlap
+// any of the used prefixes.
+func planCIDRBlocks(supernet netip.Prefix, bits, count int, used []netip.Prefix) ([]netip.Prefix, error) {
+	if !supernet.Addr().Is4() {
+		return nil, fmt.Errorf("only IPv4 supernets are supported")
+	}
+	supernet = supernet.Masked()
+	if bits < supernet.Bits() || bits > 32 {
+		return nil, fmt.Errorf("block size /%d must be between /%d and /32 for supernet %s", bits, supernet.Bits(), supernet)
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("count must be at least 1")
+	}
+
+	blockSize := uint64(1) << (32 - bits)
+	start := uint64(ipv4ToUint32(supernet.Addr()))
+	end := start + uint64(1)<<(32-supernet.Bits())
+
+	var planned []netip.Prefix
+	for address := start; address < end && len(planned) < count; {
+		candidate := netip.PrefixFrom(uint32ToIPv4(uint32(address)), bits)
+
+		next := address + blockSize
+		free := true
+		for _, prefix := range used {
+			if !prefix.Overlaps(candidate) {
+				continue
+			}
+			free = false
+			// Skip past the used prefix, to the next aligned block
+			usedEnd := uint64(ipv4ToUint32(prefix.Masked().Addr())) + uint64(1)<<(32-prefix.Bits())
+			if usedEnd > next {
+				next = (usedEnd + blockSize - 1) / blockSize * blockSize
+			}
+		}
+
+		if free {
+			planned = append(planned, candidate)
+		}
+		address = next
+	}
+
+	if len(planned) < count {
+		return planned, fmt.Errorf("only %d free /%d blocks found in %s, %d requested", len(planned), bits, supernet, count)
+	}
+	return planned, nil
+}
+
+func formatCIDRBlock(prefix netip.Prefix) model.CIDRBlock {
+	first := ipv4ToUint32(prefix.Addr())
+	addresses := uint64(1) << (32 - prefix.Bits())
+	return model.CIDRBlock{
+		CIDR:      prefix.String(),
+		FirstIP:   prefix.Addr().String(),
+		LastIP:    uint32ToIPv4(first + uint32(addresses-1)).String(),
+		Addresses: addresses,
+	}
+}
+
+func ipv4ToUint32(addr netip.Addr) uint32 {
+	a4 := addr.As4()
+	return binary.BigEndian.Uint32(a4[:])
+}
+
+func uint32ToIPv4(value uint32) netip.Addr {
+	var a4 [4]byte
+	binary.BigEndian.PutUint32(a4[:], value)
+	return netip.AddrFrom4(a4)
+}
@@ -0,0 +1,97 @@
+package customnetwork
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetworkCIDR(t *testing.T) {
+	tests := []struct {
+		cidr             string
+		cloudProvider    string
+		expectedErrorMsg string
+	}{
+		{cidr: "10.0.0.0/16", cloudProvider: "aws"},
+		{cidr: "10.0.0.0/8", cloudProvider: "gcp"},
+		{cidr: "192.168.4.0/24", cloudProvider: "unknown"},
+		{cidr: "10.0.0.0", cloudProvider: "aws", expectedErrorMsg: "invalid CIDR block 10.0.0.0"},
+		{cidr: "fd00::/64", cloudProvider: "aws", expectedErrorMsg: "only IPv4 networks are supported"},
+		{cidr: "10.0.1.0/16", cloudProvider: "aws", expectedErrorMsg: "host bits are set, did you mean 10.0.0.0/16?"},
+		{cidr: "169.254.0.0/24", cloudProvider: "aws", expectedErrorMsg: "overlaps the reserved range 169.254.0.0/16"},
+		{cidr: "10.0.0.0/8", cloudProvider: "AWS", expectedErrorMsg: "AWS networks must be between /16 and /28"},
+		{cidr: "10.0.0.0/30", cloudProvider: "azure", expectedErrorMsg: "azure networks must be between /8 and /29"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cidr+" "+test.cloudProvider, func(t *testing.T) {
+			_, err := parseNetworkCIDR(test.cidr, test.cloudProvider)
+			if test.expectedErrorMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.expectedErrorMsg)
+			}
+		})
+	}
+}
+
+func TestFindOverlappingNetworks(t *testing.T) {
+	require := require.New(t)
+
+	networks := []model.CustomNetwork{
+		{CustomNetworkID: "cn-1", CustomNetworkName: "customer-a", CIDR: "10.0.0.0/16"},
+		{CustomNetworkID: "cn-2", CIDR: "10.1.0.0/16"},
+		{CustomNetworkID: "cn-3", CIDR: "10.0.128.0/20"},
+		{CustomNetworkID: "cn-4", CIDR: "invalid"},
+	}
+
+	prefix := netip.MustParsePrefix("10.0.128.0/17")
+	overlapping := findOverlappingNetworks(prefix, networks)
+	require.Len(overlapping, 2)
+	require.EqualError(overlapError(prefix, overlapping), "CIDR block 10.0.128.0/17 overlaps with existing custom networks in the same cloud provider and region: cn-1 customer-a (10.0.0.0/16), cn-3 (10.0.128.0/20)")
+
+	require.Empty(findOverlappingNetworks(netip.MustParsePrefix("10.2.0.0/16"), networks))
+}
+
+func TestPlanCIDRBlocks(t *testing.T) {
+	require := require.New(t)
+
+	used := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/20"),
+		netip.MustParsePrefix("10.0.20.0/22"),
+		netip.MustParsePrefix("10.0.64.0/18"),
+		netip.MustParsePrefix("192.168.0.0/16"),
+	}
+	planned, err := planCIDRBlocks(netip.MustParsePrefix("10.0.0.0/16"), 20, 5, used)
+	require.NoError(err)
+	require.Equal([]netip.Prefix{
+		netip.MustParsePrefix("10.0.32.0/20"),
+		netip.MustParsePrefix("10.0.48.0/20"),
+		netip.MustParsePrefix("10.0.128.0/20"),
+		netip.MustParsePrefix("10.0.144.0/20"),
+		netip.MustParsePrefix("10.0.160.0/20"),
+	}, planned)
+
+	// A used supernet of the whole range leaves no room
+	planned, err = planCIDRBlocks(netip.MustParsePrefix("10.0.0.0/16"), 24, 1, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+	require.EqualError(err, "only 0 free /24 blocks found in 10.0.0.0/16, 1 requested")
+	require.Empty(planned)
+
+	planned, err = planCIDRBlocks(netip.MustParsePrefix("10.0.0.0/30"), 31, 3, nil)
+	require.EqualError(err, "only 2 free /31 blocks found in 10.0.0.0/30, 3 requested")
+	require.Len(planned, 2)
+
+	_, err = planCIDRBlocks(netip.MustParsePrefix("10.0.0.0/16"), 8, 1, nil)
+	require.EqualError(err, "block size /8 must be between /16 and /32 for supernet 10.0.0.0/16")
+}
+
+func TestFormatCIDRBlock(t *testing.T) {
+	require.Equal(t, model.CIDRBlock{
+		CIDR:      "10.0.16.0/20",
+		FirstIP:   "10.0.16.0",
+		LastIP:    "10.0.31.255",
+		Addresses: 4096,
+	}, formatCIDRBlock(netip.MustParsePrefix("10.0.16.0/20")))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
 	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
 	"github.com/spf13/cobra"
@@ -15,15 +16,23 @@ import (
 
 const (
 	createExample = `# Create a custom network for specific cloud provider and region 
-omctl custom-network create --cloud-provider=[cloud-provider-name] --region=[cloud-provider-region] --cidr=[cidr-block] --name=[friendly-network-name]`
+omctl custom-network create --cloud-provider=[cloud-provider-name] --region=[cloud-provider-region] --cidr=[cidr-block] --name=[friendly-network-name]
+
+# Find a free CIDR block first, then create the network with it
+omctl custom-network plan --cloud-provider=aws --region=us-east-1 --supernet=10.0.0.0/8 --size=/20
+omctl custom-network create --cloud-provider=aws --region=us-east-1 --cidr=10.0.16.0/20 --name=customer-a`
 )
 
 var CustomNetworkID string
 
 var createCmd = &cobra.Command{
-	Use:          "create [flags]",
-	Short:        "Create a custom network",
-	Long:         `This command helps you create a new custom network.`,
+	Use:   "create [flags]",
+	Short: "Create a custom network",
+	Long: `This command helps you create a new custom network.
+
+The CIDR block is validated before the network is created: it must be an IPv4 network without host bits set, outside
+reserved ranges, and of a size the cloud provider supports (/16 to /28 on AWS, /8 to /29 on Azure and GCP). It must
+also not overlap any existing custom network in the same cloud provider and region, unless --allow-overlap is set.`,
 	Example:      createExample,
 	RunE:         runCreate,
 	SilenceUsage: true,
@@ -34,6 +43,7 @@ func init() {
 	createCmd.Flags().StringP(RegionFlag, "", "", "Region for the custom network (format is cloud provider specific)")
 	createCmd.Flags().StringP(CidrFlag, "", "", "Network CIDR block")
 	createCmd.Flags().StringP(NameFlag, "", "", "Optional friendly name for the custom network")
+	createCmd.Flags().Bool(AllowOverlapFlag, false, "Create the custom network even if its CIDR block overlaps an existing custom network in the same cloud provider and region")
 
 	err := createCmd.MarkFlagRequired(CloudProviderFlag)
 	if err != nil {
@@ -57,6 +67,7 @@ func runCreate(cmd *cobra.Command, args []string) (err error) {
 	region, _ := cmd.Flags().GetString(RegionFlag)
 	cidr, _ := cmd.Flags().GetString(CidrFlag)
 	name, _ := cmd.Flags().GetString(NameFlag)
+	allowOverlap, _ := cmd.Flags().GetBool(AllowOverlapFlag)
 	output, _ := cmd.Flags().GetString(common.OutputFlag)
 
 	// Validate parameters
@@ -81,6 +92,14 @@ func runCreate(cmd *cobra.Command, args []string) (err error) {
 		sm.Start()
 	}
 
+	// Check for overlaps with the existing custom networks in the same cloud provider and region
+	if !allowOverlap {
+		if err = checkNetworkOverlaps(cmd.Context(), token, cloudProvider, region, cidr); err != nil {
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+	}
+
 	var newNetwork *openapiclientfleet.FleetCustomNetwork
 	newNetwork, err = createCustomNetwork(cmd.Context(), token, cloudProvider, region, cidr, name)
 	if err != nil {
@@ -114,9 +133,51 @@ func validateCreateArguments(cloudProvider, region, cidr string) error {
 	if cidr == "" {
 		return fmt.Errorf("please provide network CIDR block")
 	}
+	if _, err := parseNetworkCIDR(cidr, cloudProvider); err != nil {
+		return err
+	}
 	return nil
 }
 
+func checkNetworkOverlaps(ctx context.Context, token, cloudProvider, region, cidr string) error {
+	prefix, err := parseNetworkCIDR(cidr, cloudProvider)
+	if err != nil {
+		return err
+	}
+
+	existingNetworks, err := listCustomNetworks(ctx, token, cloudProvider, region)
+	if err != nil {
+		return err
+	}
+
+	if overlapping := findOverlappingNetworks(prefix, existingNetworks); len(overlapping) > 0 {
+		return overlapError(prefix, overlapping)
+	}
+	return nil
+}
+
+// listCustomNetworks lists the custom networks, optionally restricted to a cloud provider and region.
+func listCustomNetworks(ctx context.Context, token, cloudProvider, region string) ([]model.CustomNetwork, error) {
+	var cloudProviderParam, regionParam *string
+	if cloudProvider != "" {
+		cloudProviderParam = utils.ToPtr(cloudProvider)
+	}
+	if region != "" {
+		regionParam = utils.ToPtr(region)
+	}
+
+	listResult, err := dataaccess.FleetListCustomNetworks(ctx, token, cloudProviderParam, regionParam)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make([]model.CustomNetwork, 0, len(listResult.CustomNetworks))
+	for _, customNetwork := range listResult.CustomNetworks {
+		networks = append(networks, formatCustomNetwork(utils.ToPtr(customNetwork)))
+	}
+	return networks, nil
+}
+
 func createCustomNetwork(ctx context.Context, token, cloudProvider, region, cidr, name string) (
 	*openapiclientfleet.FleetCustomNetwork, error) {
 	var nameApiParam *string
@@ -21,6 +21,7 @@ func init() {
 	Cmd.AddCommand(createCmd)
 	Cmd.AddCommand(updateCmd)
 	Cmd.AddCommand(deleteCmd)
+	Cmd.AddCommand(planCmd)
 }
 
 func run(cmd *cobra.Command, args []string) {
@@ -7,4 +7,9 @@ const (
 	NameFlag            string = "name"
 	CustomNetworkIDFlag string = "custom-network-id"
 	FilterFlag          string = "filter"
+	AllowOverlapFlag    string = "allow-overlap"
+	SupernetFlag        string = "supernet"
+	SizeFlag            string = "size"
+	CountFlag           string = "count"
+	ExcludeFlag         string = "exclude"
 )
@@ -18,7 +18,10 @@ const (
 omctl custom-network list 
 
 # List custom networks for a specific cloud provider and region  
-omctl custom-network list --filter="cloud_provider:aws,region:us-east-1"`
+omctl custom-network list --filter="cloud_provider:aws,region:us-east-1"
+
+# List custom networks in AWS or GCP us regions
+omctl custom-network list --filter="cloud_provider in (aws, gcp) and region like us-*"`
 )
 
 var listCmd = &cobra.Command{
@@ -31,7 +34,8 @@ var listCmd = &cobra.Command{
 }
 
 func init() {
-	listCmd.Flags().StringArrayP(FilterFlag, "f", []string{}, "Filter to apply to the list of custom networks. E.g.: key1:value1,key2:value2, which filters custom networks where key1 equals value1 and key2 equals value2. Allow use of multiple filters to form the logical OR operation. Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.CustomNetwork{}), ",")+". Check the examples for more details.")
+	listCmd.Flags().StringArrayP(FilterFlag, "f", []string{}, "Filter to apply to the list of custom networks, e.g. \"cloud_provider:aws and region like us-*\". "+utils.FilterExpressionSyntax+" Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.CustomNetwork{}, utils.ScalarFieldsOnly), ",")+". Check the examples for more details.")
+	common.AddListFlags(listCmd, utils.GetSupportedFilterKeys(model.CustomNetwork{}, utils.ScalarFieldsOnly))
 }
 
 func runList(cmd *cobra.Command, args []string) (err error) {
@@ -42,7 +46,14 @@ func runList(cmd *cobra.Command, args []string) (err error) {
 	output, _ := cmd.Flags().GetString(common.OutputFlag)
 
 	// Parse and validate filters
-	filterMaps, err := utils.ParseFilters(filters, utils.GetSupportedFilterKeys(model.CustomNetwork{}))
+	filterExpressions, err := utils.ParseFilterExpressions(filters, utils.GetSupportedFilterKeys(model.CustomNetwork{}, utils.ScalarFieldsOnly))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Parse sorting, pagination and column options
+	listOpts, err := common.GetListOptions(cmd, utils.GetSupportedFilterKeys(model.CustomNetwork{}, utils.ScalarFieldsOnly))
 	if err != nil {
 		utils.PrintError(err)
 		return err
@@ -76,7 +87,7 @@ func runList(cmd *cobra.Command, args []string) (err error) {
 	for _, customNetwork := range listResult.CustomNetworks {
 		var match bool
 		formattedCustomNetwork := formatCustomNetwork(utils.ToPtr(customNetwork))
-		match, err = utils.MatchesFilters(formattedCustomNetwork, filterMaps)
+		match, err = utils.MatchesFilterExpressions(formattedCustomNetwork, filterExpressions)
 		if err != nil {
 			utils.HandleSpinnerError(spinner, sm, err)
 			return
@@ -93,8 +104,15 @@ func runList(cmd *cobra.Command, args []string) (err error) {
 		utils.HandleSpinnerSuccess(spinner, sm, "Successfully listed custom networks")
 	}
 
+	// Sort and paginate the results
+	formattedCustomNetworks, err = utils.SortAndPaginate(formattedCustomNetworks, listOpts)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
 	// Print output
-	err = utils.PrintTextTableJsonArrayOutput(output, formattedCustomNetworks)
+	err = utils.PrintListOutput(output, formattedCustomNetworks, listOpts)
 	if err != nil {
 		utils.PrintError(err)
 		return err
@@ -0,0 +1,152 @@
+package customnetwork
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	planExample = `# Propose 5 free /20 blocks in 10.0.0.0/8 that don't overlap any custom network in AWS us-east-1
+omctl custom-network plan --cloud-provider=aws --region=us-east-1 --supernet=10.0.0.0/8 --size=/20 --count=5
+
+# Propose a free block that also avoids the ranges of peered customer networks
+omctl custom-network plan --supernet=172.16.0.0/12 --size=/16 --exclude=172.16.0.0/16,172.20.0.0/14`
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan [flags]",
+	Short: "Propose free CIDR blocks for new custom networks",
+	Long: `This command helps you allocate CIDR blocks for new custom networks. It proposes the first blocks of the requested
+size inside the supernet that don't overlap any existing custom network, in the given cloud provider and region if
+set, nor any of the --exclude ranges.`,
+	Example:      planExample,
+	RunE:         runPlan,
+	SilenceUsage: true,
+}
+
+func init() {
+	planCmd.Flags().String(SupernetFlag, "", "CIDR block to allocate the new networks from, e.g. 10.0.0.0/8")
+	planCmd.Flags().String(SizeFlag, "", "Prefix length of the proposed blocks, e.g. /20")
+	planCmd.Flags().Int(CountFlag, 1, "Number of blocks to propose")
+	planCmd.Flags().String(CloudProviderFlag, "", "Only consider the existing custom networks of this cloud provider, and check the block size against its limits")
+	planCmd.Flags().String(RegionFlag, "", "Only consider the existing custom networks in this region")
+	planCmd.Flags().StringSlice(ExcludeFlag, []string{}, "Additional CIDR blocks the proposed blocks must not overlap, e.g. peered networks")
+
+	err := planCmd.MarkFlagRequired(SupernetFlag)
+	if err != nil {
+		return
+	}
+	err = planCmd.MarkFlagRequired(SizeFlag)
+	if err != nil {
+		return
+	}
+}
+
+func runPlan(cmd *cobra.Command, args []string) (err error) {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Get flags
+	supernetFlag, _ := cmd.Flags().GetString(SupernetFlag)
+	sizeFlag, _ := cmd.Flags().GetString(SizeFlag)
+	count, _ := cmd.Flags().GetInt(CountFlag)
+	cloudProvider, _ := cmd.Flags().GetString(CloudProviderFlag)
+	region, _ := cmd.Flags().GetString(RegionFlag)
+	excludes, _ := cmd.Flags().GetStringSlice(ExcludeFlag)
+	output, _ := cmd.Flags().GetString(common.OutputFlag)
+
+	// Validate parameters
+	supernet, err := netip.ParsePrefix(strings.TrimSpace(supernetFlag))
+	if err != nil {
+		err = fmt.Errorf("invalid supernet %s: %w", supernetFlag, err)
+		utils.PrintError(err)
+		return
+	}
+	bits, err := parsePrefixLength(sizeFlag)
+	if err != nil {
+		utils.PrintError(err)
+		return
+	}
+	if limits, ok := prefixLengthLimits[strings.ToLower(cloudProvider)]; ok && (bits < limits.min || bits > limits.max) {
+		err = fmt.Errorf("%s networks must be between /%d and /%d", cloudProvider, limits.min, limits.max)
+		utils.PrintError(err)
+		return
+	}
+
+	var used []netip.Prefix
+	for _, exclude := range excludes {
+		prefix, parseErr := netip.ParsePrefix(strings.TrimSpace(exclude))
+		if parseErr != nil {
+			err = fmt.Errorf("invalid --%s CIDR block %s: %w", ExcludeFlag, exclude, parseErr)
+			utils.PrintError(err)
+			return
+		}
+		used = append(used, prefix)
+	}
+	used = append(used, reservedPrefixes...)
+
+	// Validate user is logged in
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not JSON
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != common.OutputTypeJson {
+		sm = ysmrr.NewSpinnerManager()
+		spinner = sm.AddSpinner("Planning CIDR blocks...")
+		sm.Start()
+	}
+
+	existingNetworks, err := listCustomNetworks(cmd.Context(), token, cloudProvider, region)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+	for _, network := range existingNetworks {
+		if prefix, parseErr := netip.ParsePrefix(network.CIDR); parseErr == nil {
+			used = append(used, prefix)
+		}
+	}
+
+	planned, err := planCIDRBlocks(supernet, bits, count, used)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("Found %d free CIDR blocks, checked against %d existing custom networks", len(planned), len(existingNetworks)))
+
+	blocks := make([]model.CIDRBlock, 0, len(planned))
+	for _, prefix := range planned {
+		blocks = append(blocks, formatCIDRBlock(prefix))
+	}
+
+	err = utils.PrintTextTableJsonArrayOutput(output, blocks)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	return
+}
+
+// parsePrefixLength parses a prefix length written as /20 or 20.
+func parsePrefixLength(size string) (int, error) {
+	bits, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(size), "/"))
+	if err != nil || bits < 0 || bits > 32 {
+		return 0, fmt.Errorf("invalid --%s %s, expected a prefix length such as /20", SizeFlag, size)
+	}
+	return bits, nil
+}
@@ -0,0 +1,133 @@
+package dashboard
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/rivo/tview"
+)
+
+// describeSelected shows the selected row as JSON. Instance deployments and deployment cells are described from the
+// API, with all their details, and the other rows as shown in the table.
+func (d *dashboard) describeSelected() {
+	selected, ok := d.rowAt(d.selectedIndex())
+	if !ok {
+		return
+	}
+
+	level := d.current().level
+	d.describe.SetTitle(fmt.Sprintf(" %s %s (Esc to go back) ", level, selected.key))
+	d.describe.SetText("Loading...")
+	d.describe.ScrollToBeginning()
+	d.pages.SwitchToPage(pageDescribe)
+	d.app.SetFocus(d.describe)
+
+	go func() {
+		var described any = selected.model
+		var err error
+		switch level {
+		case levelInstances:
+			instance := selected.scope.instance
+			described, err = dataaccess.DescribeResourceInstance(d.ctx, d.token, instance.ServiceId, instance.ServiceEnvironmentId, instance.Id)
+		case levelDeploymentCells:
+			described, err = dataaccess.DescribeHostCluster(d.ctx, d.token, selected.scope.hostClusterID)
+		}
+
+		text := ""
+		if err == nil {
+			var data []byte
+			if data, err = json.MarshalIndent(described, "", "  "); err == nil {
+				text = string(data)
+			}
+		}
+		if err != nil {
+			text = fmt.Sprintf("Failed to describe %s: %v", selected.key, err)
+		}
+
+		d.app.QueueUpdateDraw(func() {
+			d.describe.SetText(text)
+		})
+	}()
+}
+
+// instanceCommand runs an omctl command on the selected instance deployment, after confirming it if it changes the
+// instance deployment.
+func (d *dashboard) instanceCommand(action string, confirm bool, command ...string) {
+	selected, ok := d.rowAt(d.selectedIndex())
+	if !ok || d.current().level != levelInstances {
+		return
+	}
+
+	args := append(command, selected.key)
+	if !confirm {
+		// Interactive commands return to the dashboard when they exit
+		d.runCommand(false, args...)
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Do you want to %s instance deployment %s?", action, selected.key)).
+		AddButtons([]string{"Cancel", "Yes"}).
+		SetDoneFunc(func(_ int, label string) {
+			d.pages.RemovePage(pageConfirm)
+			d.app.SetFocus(d.table)
+			if label == "Yes" {
+				d.runCommand(true, args...)
+			}
+		})
+	d.pages.AddPage(pageConfirm, modal, true, true)
+	d.app.SetFocus(modal)
+}
+
+// upgradeStatus shows the status of the latest upgrade of the selected version, or of the version the instance
+// deployments are listed for.
+func (d *dashboard) upgradeStatus() {
+	upgradePathID := ""
+	switch d.current().level {
+	case levelVersions:
+		if selected, ok := d.rowAt(d.selectedIndex()); ok {
+			upgradePathID = selected.scope.upgradePathID
+		}
+	case levelInstances:
+		upgradePathID = d.current().scope.upgradePathID
+	default:
+		return
+	}
+
+	if upgradePathID == "" {
+		d.message = "[yellow]No upgrade to this version"
+		d.render()
+		return
+	}
+	d.runCommand(true, "upgrade", "status", "detail", upgradePathID)
+}
+
+// runCommand suspends the dashboard to run omctl with the given arguments in the terminal, waiting for Enter before
+// resuming if pause is set, so that the output can be read. The rows are reloaded afterward, since the command can
+// change them.
+func (d *dashboard) runCommand(pause bool, args ...string) {
+	d.app.Suspend(func() {
+		executable, err := os.Executable()
+		if err == nil {
+			command := exec.CommandContext(d.ctx, executable, args...)
+			command.Stdin = os.Stdin
+			command.Stdout = os.Stdout
+			command.Stderr = os.Stderr
+			err = command.Run()
+		}
+		if err != nil {
+			fmt.Printf("Failed to run omctl: %v\n", err)
+			pause = true
+		}
+
+		if pause {
+			fmt.Print("\nPress Enter to return to the dashboard...")
+			_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+		}
+	})
+	d.reload()
+}
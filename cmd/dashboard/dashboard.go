@@ -0,0 +1,427 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/rivo/tview"
+	"github.com/spf13/cobra"
+)
+
+const (
+	dashboardExample = `# Open the fleet dashboard
+omctl dashboard
+
+# Open the fleet dashboard, refreshing statuses every 30 seconds
+omctl dashboard --refresh 30s`
+
+	pageMain     = "main"
+	pageDescribe = "describe"
+	pageConfirm  = "confirm"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "dashboard [flags]",
+	Short: "Navigate your fleet in an interactive dashboard",
+	Long: `This command opens an interactive dashboard to navigate your fleet, from services to environments, plans,
+versions, instance deployments, and the deployment cells in the region of an instance deployment.
+
+Statuses are refreshed periodically. Press Enter to open the selected row and Esc to go back. Press / to filter the
+rows with a filter expression on the same keys as the matching list command, e.g. "status = FAILED" for instance
+deployments. ` + utils.FilterExpressionSyntax + `
+
+Hotkeys act on the selected row:
+  d  describe the selected row
+  b  debug the selected instance deployment
+  i  inspect the Kubernetes workloads of the selected instance deployment
+  r  restart the selected instance deployment
+  t  trigger a backup of the selected instance deployment
+  u  show the status of the latest upgrade of the selected version, or of the version of the instance deployments
+  Ctrl+R  refresh now
+  q  quit`,
+	Example:      dashboardExample,
+	RunE:         run,
+	SilenceUsage: true,
+}
+
+func init() {
+	Cmd.Args = cobra.NoArgs
+	Cmd.Flags().Duration("refresh", 10*time.Second, "Interval between refreshes of the statuses")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve flags
+	refresh, err := cmd.Flags().GetDuration("refresh")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	if refresh <= 0 {
+		err = fmt.Errorf("--refresh must be positive")
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	d := newDashboard(cmd.Context(), apiFleet{token: token}, token)
+	return d.run(refresh)
+}
+
+// frame is a level of the navigation stack, with the selection and filter to restore when going back to it.
+type frame struct {
+	level       level
+	scope       scope
+	selectedKey string
+	filter      string
+}
+
+// dashboard is the state of the TUI. It is only read and changed from the tview event loop, except by loads, which
+// hand their rows back through QueueUpdateDraw.
+type dashboard struct {
+	ctx   context.Context
+	fleet fleet
+	token string
+
+	app         *tview.Application
+	pages       *tview.Pages
+	header      *tview.TextView
+	table       *tview.Table
+	filterInput *tview.InputField
+	footer      *tview.TextView
+	describe    *tview.TextView
+
+	stack      []frame
+	rows       []row
+	visible    []row
+	expression *utils.FilterExpression
+	generation int
+	loading    bool
+	loadedAt   time.Time
+	message    string
+}
+
+func newDashboard(ctx context.Context, f fleet, token string) *dashboard {
+	d := &dashboard{
+		ctx:   ctx,
+		fleet: f,
+		token: token,
+		app:   tview.NewApplication(),
+		stack: []frame{{level: levelServices}},
+	}
+
+	d.header = tview.NewTextView().SetDynamicColors(true)
+	d.table = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	d.table.SetBorder(true)
+	d.filterInput = tview.NewInputField().SetLabel("/")
+	d.footer = tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignCenter)
+	d.describe = tview.NewTextView().SetScrollable(true).SetWrap(true)
+	d.describe.SetBorder(true)
+
+	d.filterInput.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			d.applyFilter(d.filterInput.GetText())
+		case tcell.KeyEscape:
+			d.filterInput.SetText(d.current().filter)
+		}
+		d.app.SetFocus(d.table)
+	})
+
+	main := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(d.header, 2, 0, false).
+		AddItem(d.table, 0, 1, true).
+		AddItem(d.filterInput, 1, 0, false).
+		AddItem(d.footer, 1, 0, false)
+
+	d.pages = tview.NewPages().
+		AddPage(pageMain, main, true, true).
+		AddPage(pageDescribe, d.describe, true, false)
+
+	d.table.SetSelectedFunc(func(rowIndex, _ int) { d.open(rowIndex) })
+	d.table.SetInputCapture(d.handleTableKey)
+	d.describe.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
+			d.pages.SwitchToPage(pageMain)
+			d.app.SetFocus(d.table)
+			return nil
+		}
+		return event
+	})
+	d.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlC {
+			d.app.Stop()
+			return nil
+		}
+		return event
+	})
+
+	return d
+}
+
+func (d *dashboard) run(refresh time.Duration) error {
+	ctx, cancel := context.WithCancel(d.ctx)
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(refresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.app.QueueUpdate(d.reload)
+			}
+		}
+	}()
+
+	d.render()
+	d.reload()
+
+	// Disable mouse to allow terminal text selection, as the other TUIs
+	if err := d.app.SetRoot(d.pages, true).EnableMouse(false).Run(); err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	return nil
+}
+
+func (d *dashboard) current() *frame {
+	return &d.stack[len(d.stack)-1]
+}
+
+func (d *dashboard) handleTableKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
+		d.back()
+		return nil
+	case tcell.KeyCtrlR:
+		d.reload()
+		return nil
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'q':
+			d.app.Stop()
+		case '/':
+			d.app.SetFocus(d.filterInput)
+		case 'd':
+			d.describeSelected()
+		case 'b':
+			d.instanceCommand("debug", false, "instance", "debug")
+		case 'i':
+			d.instanceCommand("inspect", false, "inspect")
+		case 'r':
+			d.instanceCommand("restart", true, "instance", "restart")
+		case 't':
+			d.instanceCommand("trigger a backup of", true, "instance", "trigger-backup")
+		case 'u':
+			d.upgradeStatus()
+		default:
+			return event
+		}
+		return nil
+	}
+	return event
+}
+
+// open drills down into the row at the given table row index.
+func (d *dashboard) open(rowIndex int) {
+	selected, ok := d.rowAt(rowIndex)
+	if !ok || d.current().level == levelDeploymentCells {
+		return
+	}
+
+	d.current().selectedKey = selected.key
+	d.stack = append(d.stack, frame{level: d.current().level + 1, scope: selected.scope})
+	d.navigated()
+}
+
+func (d *dashboard) back() {
+	if len(d.stack) == 1 {
+		return
+	}
+	d.stack = d.stack[:len(d.stack)-1]
+	d.navigated()
+}
+
+func (d *dashboard) navigated() {
+	d.generation++
+	d.loading = false
+	d.rows = nil
+	d.visible = nil
+	d.message = ""
+	d.filterInput.SetText(d.current().filter)
+	d.expression = nil
+	if d.current().filter != "" {
+		d.expression, _ = utils.ParseFilterExpression(d.current().filter, d.current().level.filterKeys())
+	}
+	d.render()
+	d.reload()
+}
+
+func (d *dashboard) applyFilter(filter string) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		d.current().filter = ""
+		d.expression = nil
+		d.message = ""
+		d.render()
+		return
+	}
+
+	expression, err := utils.ParseFilterExpression(filter, d.current().level.filterKeys())
+	if err != nil {
+		d.message = "[red]" + tview.Escape(strings.Split(err.Error(), "\n")[0])
+		d.render()
+		return
+	}
+	d.current().filter = filter
+	d.expression = expression
+	d.message = ""
+	d.render()
+}
+
+// reload loads the rows of the current level in the background, unless a load is already running.
+func (d *dashboard) reload() {
+	if d.loading {
+		return
+	}
+	d.loading = true
+	d.render()
+
+	current := *d.current()
+	generation := d.generation
+	go func() {
+		rows, err := loadRows(d.ctx, d.fleet, current.level, current.scope)
+		d.app.QueueUpdateDraw(func() {
+			if generation != d.generation {
+				// The user navigated away while loading
+				return
+			}
+			d.loading = false
+			if err != nil {
+				d.message = "[red]" + tview.Escape(err.Error())
+			} else {
+				d.rows = rows
+				d.loadedAt = time.Now()
+				if strings.HasPrefix(d.message, "[red]") {
+					d.message = ""
+				}
+			}
+			d.render()
+		})
+	}()
+}
+
+// render redraws the header, table and footer from the state, keeping the selected row.
+func (d *dashboard) render() {
+	current := d.current()
+	if selected, ok := d.rowAt(d.selectedIndex()); ok {
+		current.selectedKey = selected.key
+	}
+
+	visible, err := filterRows(d.rows, d.expression)
+	if err != nil {
+		d.message = "[red]" + tview.Escape(err.Error())
+		visible = d.rows
+	}
+	d.visible = visible
+
+	d.header.SetText(d.breadcrumb() + "\n" + d.status())
+
+	d.table.Clear()
+	title := fmt.Sprintf(" %s (%d) ", current.level, len(d.visible))
+	if current.filter != "" {
+		title = fmt.Sprintf(" %s (%d/%d) /%s ", current.level, len(d.visible), len(d.rows), tview.Escape(current.filter))
+	}
+	d.table.SetTitle(title)
+
+	selectedIndex := 1
+	for i, r := range d.visible {
+		headers, values := columns(r.model)
+		if i == 0 {
+			for column, header := range headers {
+				d.table.SetCell(0, column, tview.NewTableCell(header).SetTextColor(tcell.ColorYellow).SetSelectable(false).SetExpansion(1))
+			}
+		}
+		color := statusColor(rowStatus(r.model))
+		for column, value := range values {
+			d.table.SetCell(i+1, column, tview.NewTableCell(tview.Escape(value)).SetTextColor(color).SetExpansion(1))
+		}
+		if r.key == current.selectedKey {
+			selectedIndex = i + 1
+		}
+	}
+	if len(d.visible) > 0 {
+		d.table.Select(selectedIndex, 0)
+	}
+
+	d.footer.SetText(d.help())
+}
+
+func (d *dashboard) breadcrumb() string {
+	s := d.current().scope
+	parts := []string{"[yellow]Fleet[-]"}
+	for _, part := range []string{s.serviceName, s.environmentName, s.planName, s.version} {
+		if part != "" {
+			parts = append(parts, tview.Escape(part))
+		}
+	}
+	if s.instance != nil {
+		parts = append(parts, s.instance.Id)
+	}
+	return strings.Join(parts, " > ")
+}
+
+func (d *dashboard) status() string {
+	status := ""
+	switch {
+	case d.loading:
+		status = "[gray]Loading...[-]"
+	case !d.loadedAt.IsZero():
+		status = "[gray]Refreshed at " + d.loadedAt.Format(time.TimeOnly) + "[-]"
+	}
+	if d.message != "" {
+		status += "  " + d.message + "[-]"
+	}
+	return status
+}
+
+func (d *dashboard) help() string {
+	keys := []string{"Enter: open", "Esc: back", "/: filter", "d: describe"}
+	switch d.current().level {
+	case levelVersions:
+		keys = append(keys, "u: upgrade status")
+	case levelInstances:
+		keys = append(keys, "b: debug", "i: inspect", "r: restart", "t: backup", "u: upgrade status")
+	case levelDeploymentCells:
+		keys = keys[1:]
+	}
+	return strings.Join(append(keys, "Ctrl+R: refresh", "q: quit"), " | ")
+}
+
+func (d *dashboard) selectedIndex() int {
+	index, _ := d.table.GetSelection()
+	return index
+}
+
+// rowAt returns the row shown at the table row index, below the header row.
+func (d *dashboard) rowAt(index int) (row, bool) {
+	if index < 1 || index > len(d.visible) {
+		return row{}, false
+	}
+	return d.visible[index-1], true
+}
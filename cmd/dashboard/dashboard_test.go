@@ -0,0 +1,183 @@
+package dashboard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	openapiclientv1 "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFleet struct{}
+
+func (fakeFleet) services(context.Context) ([]openapiclientv1.DescribeServiceResult, error) {
+	return []openapiclientv1.DescribeServiceResult{
+		{Id: "s-2", Name: "redis"},
+		{Id: "s-1", Name: "Postgres", ServiceEnvironments: []openapiclientv1.ServiceEnvironment{
+			{Id: "se-2", Name: "Prod", Type: utils.ToPtr("PROD"), SourceEnvironmentName: utils.ToPtr("Dev"), ServicePlans: []openapiclientv1.ServicePlan{
+				{ProductTierID: "pt-2", Name: "Premium", TierType: "OMNISTRATE_DEDICATED_TENANCY", ModelType: "OMNISTRATE_HOSTED"},
+				{ProductTierID: "pt-1", Name: "Free"},
+			}},
+			{Id: "se-1", Name: "Dev", Type: utils.ToPtr("DEV")},
+		}},
+	}, nil
+}
+
+func (fakeFleet) versions(context.Context, string, string) ([]openapiclientv1.TierVersionSet, error) {
+	return []openapiclientv1.TierVersionSet{
+		{Version: "2.0", Status: "Preferred", LatestUpgradePathId: utils.ToPtr("upgrade-1")},
+		{Version: "1.0", Status: "Deprecated", Description: utils.ToPtr("first release")},
+	}, nil
+}
+
+func (fakeFleet) instances(context.Context) ([]openapiclientfleet.ResourceInstanceSearchRecord, error) {
+	newInstance := func(id, environmentID, version, status string) openapiclientfleet.ResourceInstanceSearchRecord {
+		return openapiclientfleet.ResourceInstanceSearchRecord{
+			Id:                   id,
+			ServiceId:            "s-1",
+			ServiceName:          "Postgres",
+			ServiceEnvironmentId: environmentID,
+			ProductTierId:        "pt-2",
+			ProductTierVersion:   utils.ToPtr(version),
+			CloudProvider:        "aws",
+			RegionCode:           "us-east-1",
+			Status:               status,
+		}
+	}
+	return []openapiclientfleet.ResourceInstanceSearchRecord{
+		newInstance("instance-2", "se-2", "2.0", "FAILED"),
+		newInstance("instance-1", "se-2", "2.0", "RUNNING"),
+		newInstance("instance-3", "se-2", "1.0", "RUNNING"),
+		newInstance("instance-4", "se-1", "2.0", "RUNNING"),
+	}, nil
+}
+
+func (fakeFleet) hostClusters(context.Context) ([]openapiclientfleet.HostCluster, error) {
+	return []openapiclientfleet.HostCluster{
+		{Id: "hc-1", Key: "dataplane-1", CloudProvider: "AWS", Region: "us-east-1", Status: "RUNNING"},
+		{Id: "hc-2", Key: "dataplane-2", CloudProvider: "aws", Region: "us-west-2"},
+		{Id: "hc-3", Key: "dataplane-3", CloudProvider: "gcp", Region: "us-east-1"},
+	}, nil
+}
+
+func TestLoadRows(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	rows, err := loadRows(ctx, fakeFleet{}, levelServices, scope{})
+	require.NoError(err)
+	require.Equal([]string{"s-1", "s-2"}, rowKeys(rows))
+	require.Equal(model.Service{ID: "s-1", Name: "Postgres", Environments: "Prod,Dev"}, rows[0].model)
+
+	rows, err = loadRows(ctx, fakeFleet{}, levelEnvironments, rows[0].scope)
+	require.NoError(err)
+	require.Equal([]string{"se-1", "se-2"}, rowKeys(rows))
+	require.Equal(model.Environment{EnvironmentID: "se-2", EnvironmentName: "Prod", EnvironmentType: "PROD", ServiceID: "s-1", ServiceName: "Postgres", SourceEnvName: "Dev"}, rows[1].model)
+
+	rows, err = loadRows(ctx, fakeFleet{}, levelPlans, rows[1].scope)
+	require.NoError(err)
+	require.Equal([]string{"pt-1", "pt-2"}, rowKeys(rows))
+	require.Equal("OMNISTRATE_DEDICATED_TENANCY", rows[1].model.(model.ServicePlan).DeploymentType)
+
+	rows, err = loadRows(ctx, fakeFleet{}, levelVersions, rows[1].scope)
+	require.NoError(err)
+	require.Equal([]string{"2.0", "1.0"}, rowKeys(rows))
+	require.Equal("upgrade-1", rows[0].scope.upgradePathID)
+	require.Equal(model.ServicePlanVersion{PlanID: "pt-2", PlanName: "Premium", ServiceID: "s-1", ServiceName: "Postgres", Environment: "Prod", Version: "1.0", ReleaseDescription: "first release", VersionSetStatus: "Deprecated"}, rows[1].model)
+
+	rows, err = loadRows(ctx, fakeFleet{}, levelInstances, rows[0].scope)
+	require.NoError(err)
+	require.Equal([]string{"instance-1", "instance-2"}, rowKeys(rows))
+	require.Equal("FAILED", rows[1].model.(model.Instance).Status)
+	require.Equal("instance-2", rows[1].scope.instance.Id)
+
+	rows, err = loadRows(ctx, fakeFleet{}, levelDeploymentCells, rows[1].scope)
+	require.NoError(err)
+	require.Equal([]string{"hc-1"}, rowKeys(rows))
+	require.Equal("hc-1", rows[0].scope.hostClusterID)
+}
+
+func TestFilterRows(t *testing.T) {
+	require := require.New(t)
+
+	rows, err := loadRows(context.Background(), fakeFleet{}, levelServices, scope{})
+	require.NoError(err)
+
+	expression, err := utils.ParseFilterExpression("name ilike post*", levelServices.filterKeys())
+	require.NoError(err)
+	filtered, err := filterRows(rows, expression)
+	require.NoError(err)
+	require.Equal([]string{"s-1"}, rowKeys(filtered))
+
+	filtered, err = filterRows(rows, nil)
+	require.NoError(err)
+	require.Len(filtered, 2)
+
+	_, err = utils.ParseFilterExpression("status = RUNNING", levelServices.filterKeys())
+	require.Error(err)
+}
+
+func TestColumnsAndStatus(t *testing.T) {
+	require := require.New(t)
+
+	headers, values := columns(model.DeploymentCellTableView{ID: "hc-1", CustomerEmail: utils.ToPtr("a@example.com"), CurrentNumberOfDeployments: 3, Status: "RUNNING"})
+	require.Equal([]string{"ID", "CUSTOMER EMAIL", "CUSTOMER ORGANIZATION NAME", "STATUS", "TYPE", "CLOUD PROVIDER", "REGION", "CURRENT NUMBER OF DEPLOYMENTS", "HEALTH STATUS"}, headers)
+	require.Equal([]string{"hc-1", "a@example.com", "", "RUNNING", "", "", "", "3", ""}, values)
+
+	require.Equal("RUNNING", rowStatus(model.DeploymentCellTableView{Status: "RUNNING", HealthStatus: "Status: FAILED"}))
+	require.Equal("Preferred", rowStatus(model.ServicePlanVersion{VersionSetStatus: "Preferred"}))
+	require.Empty(rowStatus(model.Service{}))
+
+	require.Equal(tcell.ColorGreen, statusColor("RUNNING"))
+	require.Equal(tcell.ColorRed, statusColor("UNHEALTHY"))
+	require.Equal(tcell.ColorYellow, statusColor("DEPLOYING"))
+	require.Equal(tcell.ColorGray, statusColor("Deprecated"))
+	require.Equal(tcell.ColorDefault, statusColor(""))
+}
+
+func TestDashboardNavigation(t *testing.T) {
+	require := require.New(t)
+
+	d := newDashboard(context.Background(), fakeFleet{}, "token")
+	rows, err := loadRows(context.Background(), fakeFleet{}, levelServices, scope{})
+	require.NoError(err)
+	d.rows = rows
+	d.render()
+
+	require.Equal(" Services (2) ", d.table.GetTitle())
+	require.Equal("NAME", d.table.GetCell(0, 1).Text)
+	require.Equal("Postgres", d.table.GetCell(1, 1).Text)
+
+	d.applyFilter("name = redis")
+	require.Equal(" Services (1/2) /name = redis ", d.table.GetTitle())
+	require.Equal("redis", d.table.GetCell(1, 1).Text)
+
+	d.applyFilter("plan = Free")
+	require.Contains(d.message, "unsupported filter key")
+	require.Equal("name = redis", d.current().filter)
+
+	d.applyFilter("")
+	d.table.Select(1, 0)
+	d.open(1)
+	require.Len(d.stack, 2)
+	require.Equal(levelEnvironments, d.current().level)
+	require.Equal("s-1", d.current().scope.serviceID)
+	require.Equal("[yellow]Fleet[-] > Postgres", d.breadcrumb())
+	require.Empty(d.visible)
+
+	d.back()
+	require.Len(d.stack, 1)
+	require.Equal("s-1", d.current().selectedKey)
+}
+
+func rowKeys(rows []row) []string {
+	keys := make([]string, 0, len(rows))
+	for _, r := range rows {
+		keys = append(keys, r.key)
+	}
+	return keys
+}
@@ -0,0 +1,361 @@
+package dashboard
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/deploymentcell"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	openapiclientv1 "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+)
+
+// level is a level of the fleet hierarchy navigated in the dashboard.
+type level int
+
+const (
+	levelServices level = iota
+	levelEnvironments
+	levelPlans
+	levelVersions
+	levelInstances
+	levelDeploymentCells
+)
+
+func (l level) String() string {
+	switch l {
+	case levelServices:
+		return "Services"
+	case levelEnvironments:
+		return "Environments"
+	case levelPlans:
+		return "Plans"
+	case levelVersions:
+		return "Versions"
+	case levelInstances:
+		return "Instances"
+	case levelDeploymentCells:
+		return "Deployment Cells"
+	}
+	return ""
+}
+
+// filterKeys returns the filter expression keys of the rows of the level.
+func (l level) filterKeys() []string {
+	switch l {
+	case levelServices:
+		return utils.GetSupportedFilterKeys(model.Service{}, utils.ScalarFieldsOnly)
+	case levelEnvironments:
+		return utils.GetSupportedFilterKeys(model.Environment{}, utils.ScalarFieldsOnly)
+	case levelPlans:
+		return utils.GetSupportedFilterKeys(model.ServicePlan{}, utils.ScalarFieldsOnly)
+	case levelVersions:
+		return utils.GetSupportedFilterKeys(model.ServicePlanVersion{}, utils.ScalarFieldsOnly)
+	case levelInstances:
+		return utils.GetSupportedFilterKeys(model.Instance{}, utils.ScalarFieldsOnly)
+	case levelDeploymentCells:
+		return utils.GetSupportedFilterKeys(model.DeploymentCellTableView{}, utils.ScalarFieldsOnly)
+	}
+	return nil
+}
+
+// scope is the selection leading to a level: the service, environment, plan, version and instance drilled into.
+type scope struct {
+	serviceID       string
+	serviceName     string
+	environmentID   string
+	environmentName string
+	planID          string
+	planName        string
+	version         string
+	upgradePathID   string
+	instance        *openapiclientfleet.ResourceInstanceSearchRecord
+	hostClusterID   string
+}
+
+// row is one row of a level: the model shown in the table and matched by filters, and the scope of the level below.
+type row struct {
+	key   string
+	model any
+	scope scope
+}
+
+// fleet is where the dashboard reads the fleet from.
+type fleet interface {
+	services(ctx context.Context) ([]openapiclientv1.DescribeServiceResult, error)
+	versions(ctx context.Context, serviceID, planID string) ([]openapiclientv1.TierVersionSet, error)
+	instances(ctx context.Context) ([]openapiclientfleet.ResourceInstanceSearchRecord, error)
+	hostClusters(ctx context.Context) ([]openapiclientfleet.HostCluster, error)
+}
+
+// apiFleet reads the fleet from the Omnistrate API.
+type apiFleet struct {
+	token string
+}
+
+func (f apiFleet) services(ctx context.Context) ([]openapiclientv1.DescribeServiceResult, error) {
+	res, err := dataaccess.ListServices(ctx, f.token)
+	if err != nil {
+		return nil, err
+	}
+	return res.Services, nil
+}
+
+func (f apiFleet) versions(ctx context.Context, serviceID, planID string) ([]openapiclientv1.TierVersionSet, error) {
+	res, err := dataaccess.ListVersions(ctx, f.token, serviceID, planID)
+	if err != nil {
+		return nil, err
+	}
+	return res.TierVersionSets, nil
+}
+
+func (f apiFleet) instances(ctx context.Context) ([]openapiclientfleet.ResourceInstanceSearchRecord, error) {
+	res, err := dataaccess.SearchInventory(ctx, f.token, "resourceinstance:i")
+	if err != nil {
+		return nil, err
+	}
+	return res.ResourceInstanceResults, nil
+}
+
+func (f apiFleet) hostClusters(ctx context.Context) ([]openapiclientfleet.HostCluster, error) {
+	res, err := dataaccess.ListHostClusters(ctx, f.token, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.GetHostClusters(), nil
+}
+
+// loadRows reads the rows of a level in the given scope.
+func loadRows(ctx context.Context, f fleet, l level, s scope) ([]row, error) {
+	switch l {
+	case levelServices, levelEnvironments, levelPlans:
+		services, err := f.services(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return serviceRows(services, l, s), nil
+
+	case levelVersions:
+		versions, err := f.versions(ctx, s.serviceID, s.planID)
+		if err != nil {
+			return nil, err
+		}
+		return versionRows(versions, s), nil
+
+	case levelInstances:
+		instances, err := f.instances(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return instanceRows(instances, s), nil
+
+	case levelDeploymentCells:
+		hostClusters, err := f.hostClusters(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return deploymentCellRows(hostClusters, s), nil
+	}
+	return nil, nil
+}
+
+// serviceRows returns the services, the environments of the scope service, or the plans of the scope environment.
+func serviceRows(services []openapiclientv1.DescribeServiceResult, l level, s scope) []row {
+	rows := make([]row, 0)
+	for _, service := range services {
+		if l == levelServices {
+			environments := make([]string, 0, len(service.ServiceEnvironments))
+			for _, environment := range service.ServiceEnvironments {
+				environments = append(environments, environment.Name)
+			}
+			rows = append(rows, row{
+				key: service.Id,
+				model: model.Service{
+					ID:           service.Id,
+					Name:         service.Name,
+					Environments: strings.Join(environments, ","),
+				},
+				scope: scope{serviceID: service.Id, serviceName: service.Name},
+			})
+			continue
+		}
+		if service.Id != s.serviceID {
+			continue
+		}
+
+		for _, environment := range service.ServiceEnvironments {
+			if l == levelEnvironments {
+				environmentScope := s
+				environmentScope.environmentID = environment.Id
+				environmentScope.environmentName = environment.Name
+				rows = append(rows, row{
+					key: environment.Id,
+					model: model.Environment{
+						EnvironmentID:   environment.Id,
+						EnvironmentName: environment.Name,
+						EnvironmentType: valueOrEmpty(environment.Type),
+						ServiceID:       service.Id,
+						ServiceName:     service.Name,
+						SourceEnvName:   valueOrEmpty(environment.SourceEnvironmentName),
+					},
+					scope: environmentScope,
+				})
+				continue
+			}
+			if environment.Id != s.environmentID {
+				continue
+			}
+
+			for _, plan := range environment.ServicePlans {
+				planScope := s
+				planScope.planID = plan.ProductTierID
+				planScope.planName = plan.Name
+				rows = append(rows, row{
+					key: plan.ProductTierID,
+					model: model.ServicePlan{
+						PlanID:         plan.ProductTierID,
+						PlanName:       plan.Name,
+						ServiceID:      service.Id,
+						ServiceName:    service.Name,
+						Environment:    environment.Name,
+						DeploymentType: plan.TierType,
+						TenancyType:    plan.ModelType,
+					},
+					scope: planScope,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return strings.ToLower(rowName(rows[i])) < strings.ToLower(rowName(rows[j]))
+	})
+	return rows
+}
+
+func rowName(r row) string {
+	switch m := r.model.(type) {
+	case model.Service:
+		return m.Name
+	case model.Environment:
+		return m.EnvironmentName
+	case model.ServicePlan:
+		return m.PlanName
+	}
+	return r.key
+}
+
+// versionRows returns the versions of the scope plan, in the order listed by the API.
+func versionRows(versions []openapiclientv1.TierVersionSet, s scope) []row {
+	rows := make([]row, 0, len(versions))
+	for _, version := range versions {
+		versionScope := s
+		versionScope.version = version.Version
+		versionScope.upgradePathID = valueOrEmpty(version.LatestUpgradePathId)
+		rows = append(rows, row{
+			key: version.Version,
+			model: model.ServicePlanVersion{
+				PlanID:             s.planID,
+				PlanName:           s.planName,
+				ServiceID:          s.serviceID,
+				ServiceName:        s.serviceName,
+				Environment:        s.environmentName,
+				Version:            version.Version,
+				ReleaseDescription: valueOrEmpty(version.Description),
+				VersionSetStatus:   version.Status,
+			},
+			scope: versionScope,
+		})
+	}
+	return rows
+}
+
+// instanceRows returns the instances of the scope plan version in the scope environment.
+func instanceRows(instances []openapiclientfleet.ResourceInstanceSearchRecord, s scope) []row {
+	rows := make([]row, 0)
+	for i := range instances {
+		instance := instances[i]
+		if instance.Id == "" || instance.ServiceId != s.serviceID || instance.ServiceEnvironmentId != s.environmentID ||
+			instance.ProductTierId != s.planID || instance.GetProductTierVersion() != s.version {
+			continue
+		}
+
+		instanceScope := s
+		instanceScope.instance = &instance
+		rows = append(rows, row{
+			key: instance.Id,
+			model: model.Instance{
+				InstanceID:     instance.Id,
+				Service:        instance.ServiceName,
+				Environment:    instance.ServiceEnvironmentName,
+				Plan:           instance.GetProductTierName(),
+				Version:        instance.GetProductTierVersion(),
+				Resource:       instance.ResourceName,
+				CloudProvider:  instance.CloudProvider,
+				Region:         instance.RegionCode,
+				Status:         instance.Status,
+				SubscriptionID: instance.GetSubscriptionId(),
+			},
+			scope: instanceScope,
+		})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].key < rows[j].key })
+	return rows
+}
+
+// deploymentCellRows returns the deployment cells in the cloud provider and region of the scope instance.
+func deploymentCellRows(hostClusters []openapiclientfleet.HostCluster, s scope) []row {
+	rows := make([]row, 0)
+	if s.instance == nil {
+		return rows
+	}
+
+	for i := range hostClusters {
+		hostCluster := hostClusters[i]
+		if !strings.EqualFold(hostCluster.CloudProvider, s.instance.CloudProvider) ||
+			!strings.EqualFold(hostCluster.Region, s.instance.RegionCode) {
+			continue
+		}
+
+		cellScope := s
+		cellScope.hostClusterID = hostCluster.Id
+		rows = append(rows, row{
+			key:   hostCluster.Id,
+			model: deploymentcell.FormatDeploymentCell(&hostCluster).ToTableView(),
+			scope: cellScope,
+		})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].key < rows[j].key })
+	return rows
+}
+
+// filterRows returns the rows matching the filter expression, all of them without expression.
+func filterRows(rows []row, expression *utils.FilterExpression) ([]row, error) {
+	if expression == nil {
+		return rows, nil
+	}
+
+	filtered := make([]row, 0, len(rows))
+	for _, r := range rows {
+		ok, err := utils.MatchesFilterExpressions(r.model, []*utils.FilterExpression{expression})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered, nil
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
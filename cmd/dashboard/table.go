@@ -0,0 +1,80 @@
+package dashboard
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// columns returns the headers and values of the columns of a model, one per JSON field, in the field order.
+func columns(m any) (headers []string, values []string) {
+	value := reflect.ValueOf(m)
+	if value.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		name := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		headers = append(headers, strings.ToUpper(strings.ReplaceAll(name, "_", " ")))
+		values = append(values, formatField(value.Field(i)))
+	}
+	return
+}
+
+func formatField(field reflect.Value) string {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return ""
+		}
+		field = field.Elem()
+	}
+	return fmt.Sprintf("%v", field.Interface())
+}
+
+// rowStatus returns the value of the status field of a model: status, or the first field ending with _status.
+func rowStatus(m any) string {
+	headers, values := columns(m)
+	status := ""
+	for i, header := range headers {
+		switch {
+		case header == "STATUS":
+			return values[i]
+		case status == "" && strings.HasSuffix(header, " STATUS"):
+			status = values[i]
+		}
+	}
+	return status
+}
+
+// statusColor returns the color of a row with the given status: green when healthy, red when failed, yellow while
+// changing.
+func statusColor(status string) tcell.Color {
+	status = strings.ToUpper(status)
+	switch {
+	case status == "":
+		return tcell.ColorDefault
+	case containsAny(status, "FAIL", "ERROR", "UNHEALTHY", "DEGRADED"):
+		return tcell.ColorRed
+	case containsAny(status, "RUNNING", "ACTIVE", "PREFERRED", "READY", "HEALTHY", "COMPLETE", "AVAILABLE"):
+		return tcell.ColorGreen
+	case containsAny(status, "DEPLOYING", "PENDING", "UPDATING", "STARTING", "STOPPING", "RESTARTING", "DELETING", "IN_PROGRESS", "PROVISIONING"):
+		return tcell.ColorYellow
+	case containsAny(status, "STOPPED", "DEPRECATED", "DELETED"):
+		return tcell.ColorGray
+	}
+	return tcell.ColorDefault
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
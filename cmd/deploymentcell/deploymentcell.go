@@ -29,7 +29,8 @@ func run(cmd *cobra.Command, args []string) {
 	}
 }
 
-func formatDeploymentCell(cluster *openapiclientfleet.HostCluster) model.DeploymentCell {
+// FormatDeploymentCell converts a host cluster to the deployment cell model.
+func FormatDeploymentCell(cluster *openapiclientfleet.HostCluster) model.DeploymentCell {
 	return model.DeploymentCell{
 		// Basic identification
 		ID:          cluster.GetId(),
@@ -2,6 +2,7 @@ package deploymentcell
 
 import (
 	"context"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -12,10 +13,22 @@ import (
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
 )
 
+const (
+	listExample = `# List all deployment cells
+omctl deployment-cell list
+
+# List the AWS deployment cells that are not healthy
+omctl deployment-cell list -f="cloud_provider:aws and health_status not like 'Status: HEALTHY*'"
+
+# List the deployment cells of customers, excluding those that are running
+omctl deployment-cell list -f="customer_email != '' and status not in (RUNNING)"`
+)
+
 var listCmd = &cobra.Command{
 	Use:          "list",
 	Short:        "List all deployment cells",
-	Long:         `List all deployment cells with their details.`,
+	Long:         `List all deployment cells with their details. You can filter for specific deployment cells by using the filter flag.`,
+	Example:      listExample,
 	RunE:         runList,
 	SilenceUsage: true,
 }
@@ -23,6 +36,7 @@ var listCmd = &cobra.Command{
 func init() {
 	listCmd.Flags().StringP("account-config-id", "a", "", "Filter by account config ID")
 	listCmd.Flags().StringP("region-id", "r", "", "Filter by region ID")
+	listCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of deployment cells, e.g. \"cloud_provider:aws and status != RUNNING\". "+utils.FilterExpressionSyntax+" Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.DeploymentCell{}, utils.ScalarFieldsOnly), ",")+". Check the examples for more details.")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -46,6 +60,19 @@ func runList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	filters, err := cmd.Flags().GetStringArray("filter")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Parse filters into expressions
+	filterExpressions, err := utils.ParseFilterExpressions(filters, utils.GetSupportedFilterKeys(model.DeploymentCell{}, utils.ScalarFieldsOnly))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
 	ctx := context.Background()
 	token, err := common.GetTokenWithLogin()
 	if err != nil {
@@ -70,8 +97,17 @@ func runList(cmd *cobra.Command, args []string) error {
 	// Convert to model structure
 	var deploymentCells []model.DeploymentCell
 	for _, cluster := range hostClusters.GetHostClusters() {
-		deploymentCell := formatDeploymentCell(&cluster)
-		deploymentCells = append(deploymentCells, deploymentCell)
+		deploymentCell := FormatDeploymentCell(&cluster)
+
+		// Check if the deployment cell matches the filters
+		ok, err := utils.MatchesFilterExpressions(deploymentCell, filterExpressions)
+		if err != nil {
+			utils.PrintError(err)
+			return err
+		}
+		if ok {
+			deploymentCells = append(deploymentCells, deploymentCell)
+		}
 	}
 
 	// Print output in requested format
@@ -72,7 +72,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			continue // Skip if customer email does not match
 		}
 
-		deploymentCell := formatDeploymentCell(&cluster)
+		deploymentCell := FormatDeploymentCell(&cluster)
 		deploymentCells = append(deploymentCells, deploymentCell)
 	}
 
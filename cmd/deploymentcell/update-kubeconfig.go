@@ -139,7 +139,7 @@ func runUpdateKubeConfig(cmd *cobra.Command, args []string) error {
 			continue // Skip if customer email does not match
 		}
 
-		deploymentCell := formatDeploymentCell(&cluster)
+		deploymentCell := FormatDeploymentCell(&cluster)
 		deploymentCells = append(deploymentCells, deploymentCell)
 	}
 
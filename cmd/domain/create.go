@@ -119,6 +119,7 @@ func runCreate(cmd *cobra.Command, args []string) error {
 
 	if output != "json" {
 		dataaccess.PrintNextStepVerifyDomainMsg(customDomain.ClusterEndpoint)
+		fmt.Printf("Then check the records with 'omctl domain verify %s --wait'.\n", args[0])
 	}
 
 	return nil
@@ -0,0 +1,301 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/pkg/errors"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	defaultResolver   = "8.8.8.8:53"
+	resolvConfPath    = "/etc/resolv.conf"
+	dnsQueryTimeout   = 5 * time.Second
+	maxDNSMessageSize = 4096
+)
+
+// DNS record statuses reported by verify.
+const (
+	recordStatusVerified      = "verified"
+	recordStatusMismatch      = "mismatch"
+	recordStatusNotPropagated = "not propagated"
+)
+
+// dnsRecord is a CNAME or A record of a DNS answer.
+type dnsRecord struct {
+	Name  string
+	Type  dnsmessage.Type
+	Value string
+	TTL   uint32
+}
+
+// dnsLookupFunc returns the records answering an A query for the name, including the CNAME chain leading to them.
+type dnsLookupFunc func(ctx context.Context, name string) ([]dnsRecord, error)
+
+// resolverAddress returns the host:port of the DNS server to query. An empty resolver selects the first nameserver
+// of /etc/resolv.conf, or a public resolver if there is none.
+func resolverAddress(resolver string) string {
+	if resolver == "" {
+		resolver = systemNameserver(resolvConfPath)
+		if resolver == "" {
+			return defaultResolver
+		}
+	}
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		return net.JoinHostPort(strings.Trim(resolver, "[]"), "53")
+	}
+	return resolver
+}
+
+func systemNameserver(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// newDNSLookup returns a lookup function querying the DNS server directly, so that record TTLs are available.
+func newDNSLookup(server string) dnsLookupFunc {
+	return func(ctx context.Context, name string) ([]dnsRecord, error) {
+		return queryDNS(ctx, server, name)
+	}
+}
+
+func queryDNS(ctx context.Context, server, name string) ([]dnsRecord, error) {
+	fqdn, err := dnsmessage.NewName(canonicalName(name) + ".")
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid domain name %s", name)
+	}
+
+	var id [2]byte
+	if _, err = rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: binary.BigEndian.Uint16(id[:]), RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: fqdn, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := exchangeDNS(ctx, "udp", server, packed)
+	if err == nil && response.Truncated {
+		response, err = exchangeDNS(ctx, "tcp", server, packed)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query %s for %s", server, name)
+	}
+	if response.ID != query.ID {
+		return nil, fmt.Errorf("failed to query %s for %s: mismatched response ID", server, name)
+	}
+
+	switch response.RCode {
+	case dnsmessage.RCodeSuccess, dnsmessage.RCodeNameError:
+	default:
+		return nil, fmt.Errorf("failed to query %s for %s: %s", server, name, response.RCode)
+	}
+
+	var records []dnsRecord
+	for _, answer := range response.Answers {
+		record := dnsRecord{
+			Name: canonicalName(answer.Header.Name.String()),
+			Type: answer.Header.Type,
+			TTL:  answer.Header.TTL,
+		}
+		switch body := answer.Body.(type) {
+		case *dnsmessage.CNAMEResource:
+			record.Value = canonicalName(body.CNAME.String())
+		case *dnsmessage.AResource:
+			record.Value = net.IP(body.A[:]).String()
+		default:
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func exchangeDNS(ctx context.Context, network, server string, query []byte) (*dnsmessage.Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, dnsQueryTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	buf := make([]byte, maxDNSMessageSize)
+	var n int
+	if network == "tcp" {
+		// DNS over TCP prefixes messages with their length
+		if _, err = conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(query))), query...)); err != nil {
+			return nil, err
+		}
+		var length [2]byte
+		if _, err = io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+		buf = make([]byte, binary.BigEndian.Uint16(length[:]))
+		n, err = io.ReadFull(conn, buf)
+	} else {
+		if _, err = conn.Write(query); err != nil {
+			return nil, err
+		}
+		n, err = conn.Read(buf)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response dnsmessage.Message
+	if err = response.Unpack(buf[:n]); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// resolveEndpointIPs returns the A records the cluster endpoint resolves to, which a flattened record at the zone
+// apex must match.
+func resolveEndpointIPs(ctx context.Context, lookup dnsLookupFunc, endpoint string) ([]string, error) {
+	records, err := lookup(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var ips []string
+	for _, record := range records {
+		if record.Type == dnsmessage.TypeA {
+			ips = append(ips, record.Value)
+		}
+	}
+	slices.Sort(ips)
+	return ips, nil
+}
+
+// checkDomainRecord checks that the name is a CNAME to the cluster endpoint, or resolves to the same A records for
+// DNS providers that flatten CNAME records at the zone apex.
+func checkDomainRecord(ctx context.Context, lookup dnsLookupFunc, name, endpoint string, endpointIPs []string) (model.DomainRecordCheck, error) {
+	name, endpoint = canonicalName(name), canonicalName(endpoint)
+	check := model.DomainRecordCheck{
+		Record:   name,
+		Expected: "CNAME " + endpoint,
+	}
+
+	records, err := lookup(ctx, name)
+	if err != nil {
+		return check, err
+	}
+
+	// Follow the CNAME chain of the name, which is verified if it leads to the endpoint
+	var cnames []dnsRecord
+	current := name
+	for {
+		i := slices.IndexFunc(records, func(r dnsRecord) bool {
+			return r.Type == dnsmessage.TypeCNAME && r.Name == current
+		})
+		if i < 0 || len(cnames) > len(records) {
+			break
+		}
+		cnames = append(cnames, records[i])
+		current = records[i].Value
+	}
+	if len(cnames) > 0 {
+		check.Type = "CNAME"
+		check.Value = cnames[0].Value
+		check.TTL = cnames[0].TTL
+		if slices.ContainsFunc(cnames, func(r dnsRecord) bool { return r.Value == endpoint }) {
+			check.Status = recordStatusVerified
+			return check, nil
+		}
+		check.Status = recordStatusMismatch
+		check.Message = fmt.Sprintf("CNAME points to %s instead of %s. Resolvers may keep the old record for up to %s", check.Value, endpoint, ttlDuration(check.TTL))
+		return check, nil
+	}
+
+	var ips []string
+	for _, record := range records {
+		if record.Type == dnsmessage.TypeA && record.Name == name {
+			ips = append(ips, record.Value)
+			if check.TTL == 0 || record.TTL < check.TTL {
+				check.TTL = record.TTL
+			}
+		}
+	}
+	if len(ips) == 0 {
+		check.Status = recordStatusNotPropagated
+		check.Message = fmt.Sprintf("No CNAME or A record found. Create a CNAME record to %s, new records can take a while to propagate", endpoint)
+		return check, nil
+	}
+
+	slices.Sort(ips)
+	check.Type = "A"
+	check.Value = strings.Join(ips, ",")
+	if len(endpointIPs) > 0 && !slices.ContainsFunc(ips, func(ip string) bool { return !slices.Contains(endpointIPs, ip) }) {
+		check.Status = recordStatusVerified
+		check.Message = "A records match the cluster endpoint (flattened CNAME)"
+		return check, nil
+	}
+	check.Status = recordStatusMismatch
+	check.Message = fmt.Sprintf("A records don't match %s (%s). Resolvers may keep the old records for up to %s", endpoint, strings.Join(endpointIPs, ","), ttlDuration(check.TTL))
+	return check, nil
+}
+
+// checkDomainRecords checks the apex and www records of the custom domain.
+func checkDomainRecords(ctx context.Context, lookup dnsLookupFunc, customDomain, endpoint string) ([]model.DomainRecordCheck, error) {
+	endpointIPs, err := resolveEndpointIPs(ctx, lookup, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []model.DomainRecordCheck
+	for _, name := range []string{customDomain, "www." + canonicalName(customDomain)} {
+		check, err := checkDomainRecord(ctx, lookup, name, endpoint, endpointIPs)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+func allRecordsVerified(checks []model.DomainRecordCheck) bool {
+	for _, check := range checks {
+		if check.Status != recordStatusVerified {
+			return false
+		}
+	}
+	return len(checks) > 0
+}
+
+func canonicalName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
+
+func ttlDuration(ttl uint32) time.Duration {
+	return time.Duration(ttl) * time.Second
+}
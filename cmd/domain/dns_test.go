@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startStubResolver answers A queries over UDP from the records, keyed by the queried name without trailing dot.
+func startStubResolver(t *testing.T, answers map[string][]dnsmessage.Resource) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err = query.Unpack(buf[:n]); err != nil {
+				continue
+			}
+			name := canonicalName(query.Questions[0].Name.String())
+			response := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionAvailable: true},
+				Questions: query.Questions,
+				Answers:   answers[name],
+			}
+			if response.Answers == nil {
+				response.RCode = dnsmessage.RCodeNameError
+			}
+			packed, err := response.Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func cnameRecord(name, target string, ttl uint32) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name + "."), Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET, TTL: ttl},
+		Body:   &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(target + ".")},
+	}
+}
+
+func aRecord(name string, ip [4]byte, ttl uint32) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name + "."), Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: ttl},
+		Body:   &dnsmessage.AResource{A: ip},
+	}
+}
+
+func TestCheckDomainRecords(t *testing.T) {
+	const endpoint = "cluster.omnistrate.cloud"
+
+	tests := []struct {
+		name     string
+		answers  map[string][]dnsmessage.Resource
+		expected []model.DomainRecordCheck
+	}{
+		{
+			name: "verified CNAME and flattened apex",
+			answers: map[string][]dnsmessage.Resource{
+				endpoint:        {aRecord(endpoint, [4]byte{10, 0, 0, 1}, 60), aRecord(endpoint, [4]byte{10, 0, 0, 2}, 60)},
+				"abc.cloud":     {aRecord("abc.cloud", [4]byte{10, 0, 0, 2}, 300)},
+				"www.abc.cloud": {cnameRecord("www.abc.cloud", endpoint, 3600), aRecord(endpoint, [4]byte{10, 0, 0, 1}, 60)},
+			},
+			expected: []model.DomainRecordCheck{
+				{Record: "abc.cloud", Expected: "CNAME " + endpoint, Type: "A", Value: "10.0.0.2", TTL: 300, Status: recordStatusVerified, Message: "A records match the cluster endpoint (flattened CNAME)"},
+				{Record: "www.abc.cloud", Expected: "CNAME " + endpoint, Type: "CNAME", Value: endpoint, TTL: 3600, Status: recordStatusVerified},
+			},
+		},
+		{
+			name: "mismatch and not propagated",
+			answers: map[string][]dnsmessage.Resource{
+				endpoint:    {aRecord(endpoint, [4]byte{10, 0, 0, 1}, 60)},
+				"abc.cloud": {cnameRecord("abc.cloud", "old.example.com", 7200), aRecord("old.example.com", [4]byte{192, 168, 0, 1}, 60)},
+			},
+			expected: []model.DomainRecordCheck{
+				{Record: "abc.cloud", Expected: "CNAME " + endpoint, Type: "CNAME", Value: "old.example.com", TTL: 7200, Status: recordStatusMismatch, Message: "CNAME points to old.example.com instead of cluster.omnistrate.cloud. Resolvers may keep the old record for up to 2h0m0s"},
+				{Record: "www.abc.cloud", Expected: "CNAME " + endpoint, Status: recordStatusNotPropagated, Message: "No CNAME or A record found. Create a CNAME record to cluster.omnistrate.cloud, new records can take a while to propagate"},
+			},
+		},
+		{
+			name: "A records of another host",
+			answers: map[string][]dnsmessage.Resource{
+				endpoint:        {aRecord(endpoint, [4]byte{10, 0, 0, 1}, 60)},
+				"abc.cloud":     {aRecord("abc.cloud", [4]byte{10, 0, 0, 1}, 300), aRecord("abc.cloud", [4]byte{10, 0, 0, 9}, 120)},
+				"www.abc.cloud": {cnameRecord("www.abc.cloud", "edge.example.com", 60), cnameRecord("edge.example.com", endpoint, 60)},
+			},
+			expected: []model.DomainRecordCheck{
+				{Record: "abc.cloud", Expected: "CNAME " + endpoint, Type: "A", Value: "10.0.0.1,10.0.0.9", TTL: 120, Status: recordStatusMismatch, Message: "A records don't match cluster.omnistrate.cloud (10.0.0.1). Resolvers may keep the old records for up to 2m0s"},
+				{Record: "www.abc.cloud", Expected: "CNAME " + endpoint, Type: "CNAME", Value: "edge.example.com", TTL: 60, Status: recordStatusVerified},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := startStubResolver(t, test.answers)
+
+			checks, err := checkDomainRecords(context.Background(), newDNSLookup(server), "ABC.cloud.", endpoint)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, checks)
+			require.Equal(t, test.expected[0].Status == recordStatusVerified && test.expected[1].Status == recordStatusVerified, allRecordsVerified(checks))
+		})
+	}
+}
+
+func TestResolverAddress(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("1.1.1.1:53", resolverAddress("1.1.1.1"))
+	require.Equal("127.0.0.1:5353", resolverAddress("127.0.0.1:5353"))
+	require.Equal("[::1]:53", resolverAddress("[::1]"))
+	require.NotEmpty(resolverAddress(""))
+
+	resolvConf := filepath.Join(t.TempDir(), "resolv.conf")
+	require.NoError(os.WriteFile(resolvConf, []byte("# comment\nsearch local\nnameserver 10.0.0.53\nnameserver 10.0.0.54\n"), 0600))
+	require.Equal("10.0.0.53", systemNameserver(resolvConf))
+	require.Empty(systemNameserver(filepath.Join(t.TempDir(), "missing")))
+}
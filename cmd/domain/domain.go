@@ -17,6 +17,7 @@ func init() {
 	Cmd.AddCommand(createCmd)
 	Cmd.AddCommand(deleteCmd)
 	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(verifyCmd)
 }
 
 func run(cmd *cobra.Command, args []string) {
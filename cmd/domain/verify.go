@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientv1 "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	verifyExample = `# Check the DNS records of a custom domain
+omctl domain verify abc.cloud
+
+# Wait until the DNS records point to the cluster endpoint
+omctl domain verify abc.cloud --wait --timeout 30m
+
+# Check the records against a specific DNS server
+omctl domain verify abc.cloud --resolver 1.1.1.1`
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [name] [flags]",
+	Short: "Check the DNS records of a Custom Domain",
+	Long: `This command checks that the DNS records of a Custom Domain point to its cluster endpoint.
+
+The domain and its www subdomain must be CNAME records to the cluster endpoint. At the zone apex, A records resolving
+to the same addresses as the cluster endpoint are also accepted, for DNS providers that flatten CNAME records.
+Each record is reported as verified, mismatch or not propagated, with its TTL, which is how long resolvers may keep
+serving an old record.
+
+The records are queried from the --resolver DNS server, which defaults to the first nameserver of /etc/resolv.conf.
+With --wait, the records are checked again every --interval until they are all verified.`,
+	Example:      verifyExample,
+	RunE:         runVerify,
+	SilenceUsage: true,
+}
+
+func init() {
+	verifyCmd.Args = cobra.ExactArgs(1) // Require exactly one argument
+
+	verifyCmd.Flags().String("resolver", "", "DNS server to query, as host or host:port. Defaults to the system resolver")
+	verifyCmd.Flags().Bool("wait", false, "Wait until all DNS records are verified")
+	verifyCmd.Flags().Duration("interval", 30*time.Second, "Polling interval with --wait")
+	verifyCmd.Flags().Duration("timeout", 30*time.Minute, "Maximum time to wait with --wait")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve flags
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	resolver, err := cmd.Flags().GetString("resolver")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	wait, err := cmd.Flags().GetBool("wait")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	interval, err := cmd.Flags().GetDuration("interval")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	if interval <= 0 {
+		err = errors.New("--interval must be positive")
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user is currently logged in
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not JSON
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		spinner = sm.AddSpinner("Checking DNS records...")
+		sm.Start()
+	}
+
+	customDomain, err := findDomain(cmd.Context(), token, args[0])
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	server := resolverAddress(resolver)
+	lookup := newDNSLookup(server)
+
+	ctx := cmd.Context()
+	if wait {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var checks []model.DomainRecordCheck
+	for {
+		current, err := checkDomainRecords(ctx, lookup, customDomain.CustomDomain, customDomain.ClusterEndpoint)
+		if err != nil && !wait {
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+		if err == nil {
+			checks = current
+			if !wait || allRecordsVerified(checks) {
+				break
+			}
+		}
+
+		// Query errors are retried with --wait, since resolvers can time out while records propagate
+		if spinner != nil {
+			spinner.UpdateMessage(fmt.Sprintf("Waiting for the DNS records of %s to point to %s (%s)...", customDomain.CustomDomain, customDomain.ClusterEndpoint, verifiedCount(checks)))
+		}
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("timed out after %s waiting for the DNS records of %s", timeout, customDomain.CustomDomain)
+			utils.HandleSpinnerError(spinner, sm, err)
+			if checks != nil {
+				_ = utils.PrintTextTableJsonArrayOutput(output, checks)
+			}
+			return err
+		case <-time.After(interval):
+		}
+	}
+
+	verified := allRecordsVerified(checks)
+	if verified {
+		utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("DNS records of %s point to %s (resolver %s)", customDomain.CustomDomain, customDomain.ClusterEndpoint, server))
+	} else {
+		utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("Checked the DNS records of %s (resolver %s): %s", customDomain.CustomDomain, server, verifiedCount(checks)))
+	}
+
+	err = utils.PrintTextTableJsonArrayOutput(output, checks)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	if !verified {
+		err = fmt.Errorf("DNS records of %s don't point to %s yet. Use --wait to wait for them to propagate", customDomain.CustomDomain, customDomain.ClusterEndpoint)
+		utils.PrintError(err)
+		return err
+	}
+
+	return nil
+}
+
+// findDomain returns the custom domain with the name, or with the domain itself.
+func findDomain(ctx context.Context, token, name string) (*openapiclientv1.CustomDomain, error) {
+	domains, err := dataaccess.ListDomains(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range domains.CustomDomains {
+		if d.Name == name || canonicalName(d.CustomDomain) == canonicalName(name) {
+			return &d, nil
+		}
+	}
+	return nil, errors.New("domain not found: " + name)
+}
+
+func verifiedCount(checks []model.DomainRecordCheck) string {
+	verified := 0
+	for _, check := range checks {
+		if check.Status == recordStatusVerified {
+			verified++
+		}
+	}
+	return fmt.Sprintf("%d/%d records verified", verified, len(checks))
+}
@@ -0,0 +1,367 @@
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientv1 "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	diffExample = `# Compare the Dev and Prod environments of a service
+omctl environment diff [service-name] --from=Dev --to=Prod
+
+# Compare environments by ID
+omctl environment diff --service-id=[service-id] --from=[environment-id] --to=[environment-id]`
+
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeChanged = "changed"
+
+	categoryPlan             = "plan"
+	categoryPreferredVersion = "preferred-version"
+	categoryFeature          = "feature"
+	categorySecret           = "secret"
+	categoryDomain           = "custom-domain"
+	categoryDeploymentConfig = "deployment-config"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [service-name] --from=[environment] --to=[environment] [flags]",
+	Short: "Compare two Service Environments",
+	Long: `This command compares two environments of your service, to find the drift between them.
+
+It compares the service plans present in each environment, the preferred version of each plan and the features
+enabled in it, the secrets by name only, the custom domains, and the deployment configuration. Secrets and custom
+domains are defined per environment type, so they only differ between environments of different types.
+
+Changes are reported from the --from environment to the --to environment: a plan only in the --to environment is
+added, and a plan only in the --from environment is removed.`,
+	Example:      diffExample,
+	RunE:         runDiff,
+	SilenceUsage: true,
+}
+
+// environmentSnapshot is what is compared between environments.
+type environmentSnapshot struct {
+	Name             string
+	Type             string
+	DeploymentConfig string
+	RolloutPriority  []string
+	// Plans are by plan name
+	Plans   map[string]planSnapshot
+	Secrets []string
+	// Domains are custom domains by name
+	Domains map[string]string
+}
+
+// planSnapshot is the preferred version of a plan in an environment, with its enabled features and their
+// configuration.
+type planSnapshot struct {
+	PreferredVersion string
+	Features         map[string]string
+}
+
+func init() {
+	diffCmd.Flags().String("service-id", "", "Service ID. Required if service name is not provided")
+	diffCmd.Flags().String("from", "", "Name or ID of the environment to compare from")
+	diffCmd.Flags().String("to", "", "Name or ID of the environment to compare to")
+
+	err := diffCmd.MarkFlagRequired("from")
+	if err != nil {
+		return
+	}
+	err = diffCmd.MarkFlagRequired("to")
+	if err != nil {
+		return
+	}
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve flags
+	output, _ := cmd.Flags().GetString("output")
+	serviceID, _ := cmd.Flags().GetString("service-id")
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+
+	// Validate input arguments
+	if len(args) == 0 && serviceID == "" {
+		err := fmt.Errorf("please provide the service name or the service ID")
+		utils.PrintError(err)
+		return err
+	}
+	if len(args) > 1 {
+		err := fmt.Errorf("invalid arguments: %s. Need 1 argument: [service-name]", strings.Join(args, " "))
+		utils.PrintError(err)
+		return err
+	}
+	var serviceName string
+	if len(args) == 1 {
+		serviceName = args[0]
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not JSON
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		spinner = sm.AddSpinner("Comparing environments...")
+		sm.Start()
+	}
+
+	// Find both environments
+	fromServiceID, fromServiceName, fromEnvironmentID, _, err := getServiceEnvironment(cmd.Context(), token, serviceID, serviceName, from, from)
+	if err != nil {
+		err = errors.Wrapf(err, "--from %s", from)
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+	serviceID, serviceName = fromServiceID, fromServiceName
+	_, _, toEnvironmentID, _, err := getServiceEnvironment(cmd.Context(), token, serviceID, "", to, to)
+	if err != nil {
+		err = errors.Wrapf(err, "--to %s", to)
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	service, err := dataaccess.DescribeService(cmd.Context(), token, serviceID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	fromSnapshot, err := snapshotEnvironment(cmd.Context(), token, service, fromEnvironmentID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+	toSnapshot, err := snapshotEnvironment(cmd.Context(), token, service, toEnvironmentID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	report := model.EnvironmentDiff{
+		ServiceID:       serviceID,
+		ServiceName:     serviceName,
+		FromEnvironment: fromSnapshot.Name,
+		ToEnvironment:   toSnapshot.Name,
+		Changes:         diffEnvironments(fromSnapshot, toSnapshot),
+	}
+
+	// Handle output based on format
+	if len(report.Changes) == 0 {
+		utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("No differences between %s and %s", report.FromEnvironment, report.ToEnvironment))
+	} else {
+		utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("Found %d differences from %s to %s", len(report.Changes), report.FromEnvironment, report.ToEnvironment))
+	}
+
+	if output == "json" {
+		return utils.PrintTextTableJsonOutput(output, report)
+	}
+	if len(report.Changes) > 0 {
+		return utils.PrintTextTableJsonArrayOutput(output, report.Changes)
+	}
+	return nil
+}
+
+// snapshotEnvironment retrieves what is compared of an environment of the service.
+func snapshotEnvironment(ctx context.Context, token string, service *openapiclientv1.DescribeServiceResult, environmentID string) (environmentSnapshot, error) {
+	environment, err := dataaccess.DescribeServiceEnvironment(ctx, token, service.Id, environmentID)
+	if err != nil {
+		return environmentSnapshot{}, err
+	}
+
+	snapshot := environmentSnapshot{
+		Name:    environment.Name,
+		Type:    environment.Type,
+		Plans:   make(map[string]planSnapshot),
+		Secrets: make([]string, 0),
+		Domains: make(map[string]string),
+	}
+
+	deploymentConfig, err := dataaccess.DescribeDeploymentConfig(ctx, token, environment.DeploymentConfigId)
+	if err != nil {
+		return environmentSnapshot{}, err
+	}
+	snapshot.DeploymentConfig = deploymentConfig.Name
+	snapshot.RolloutPriority = deploymentConfig.RolloutPriorityList
+
+	for _, plan := range findEnvironmentPlans(service, environmentID) {
+		versions, err := dataaccess.ListVersions(ctx, token, service.Id, plan.ProductTierID)
+		if err != nil {
+			return environmentSnapshot{}, err
+		}
+		snapshot.Plans[plan.Name] = newPlanSnapshot(versions.TierVersionSets)
+	}
+
+	secrets, err := dataaccess.ListSecrets(ctx, token, strings.ToLower(environment.Type))
+	if err != nil {
+		return environmentSnapshot{}, err
+	}
+	for _, secret := range secrets.Secrets {
+		snapshot.Secrets = append(snapshot.Secrets, secret.Name)
+	}
+
+	domains, err := dataaccess.ListDomains(ctx, token)
+	if err != nil {
+		return environmentSnapshot{}, err
+	}
+	for _, domain := range domains.CustomDomains {
+		if strings.EqualFold(domain.EnvironmentType, environment.Type) {
+			snapshot.Domains[domain.Name] = domain.CustomDomain
+		}
+	}
+
+	return snapshot, nil
+}
+
+// newPlanSnapshot returns the preferred version of a plan with its enabled features. Plans without a preferred version
+// have an empty preferred version and no features.
+func newPlanSnapshot(versions []openapiclientv1.TierVersionSet) planSnapshot {
+	snapshot := planSnapshot{Features: make(map[string]string)}
+	for _, version := range versions {
+		if version.Status != "Preferred" {
+			continue
+		}
+		snapshot.PreferredVersion = version.Version
+		for _, feature := range version.EnabledFeatures {
+			configuration := ""
+			if len(feature.Configuration) > 0 {
+				// Maps are marshaled with sorted keys, so equal configurations compare equal
+				data, err := json.Marshal(feature.Configuration)
+				if err == nil {
+					configuration = string(data)
+				}
+			}
+			snapshot.Features[feature.GetFeature()] = configuration
+		}
+		break
+	}
+	return snapshot
+}
+
+// diffEnvironments returns the changes from one environment to the other, by category and subject.
+func diffEnvironments(from, to environmentSnapshot) []model.EnvironmentChange {
+	changes := make([]model.EnvironmentChange, 0)
+
+	if from.DeploymentConfig != to.DeploymentConfig {
+		changes = append(changes, newChange(categoryDeploymentConfig, "name", from.DeploymentConfig, to.DeploymentConfig))
+	}
+	if strings.Join(from.RolloutPriority, ",") != strings.Join(to.RolloutPriority, ",") {
+		changes = append(changes, newChange(categoryDeploymentConfig, "rollout priority",
+			strings.Join(from.RolloutPriority, ", "), strings.Join(to.RolloutPriority, ", ")))
+	}
+
+	for _, planName := range unionKeys(from.Plans, to.Plans) {
+		fromPlan, inFrom := from.Plans[planName]
+		toPlan, inTo := to.Plans[planName]
+		if !inFrom || !inTo {
+			changes = append(changes, newChange(categoryPlan, planName, presence(inFrom, fromPlan.PreferredVersion), presence(inTo, toPlan.PreferredVersion)))
+			continue
+		}
+
+		if fromPlan.PreferredVersion != toPlan.PreferredVersion {
+			changes = append(changes, newChange(categoryPreferredVersion, planName, fromPlan.PreferredVersion, toPlan.PreferredVersion))
+		}
+		for _, feature := range unionKeys(fromPlan.Features, toPlan.Features) {
+			fromConfig, inFrom := fromPlan.Features[feature]
+			toConfig, inTo := toPlan.Features[feature]
+			if inFrom && inTo && fromConfig == toConfig {
+				continue
+			}
+			changes = append(changes, newChange(categoryFeature, planName+"/"+feature, featurePresence(inFrom, fromConfig), featurePresence(inTo, toConfig)))
+		}
+	}
+
+	fromSecrets := make(map[string]string)
+	for _, name := range from.Secrets {
+		fromSecrets[name] = "present"
+	}
+	toSecrets := make(map[string]string)
+	for _, name := range to.Secrets {
+		toSecrets[name] = "present"
+	}
+	for _, name := range unionKeys(fromSecrets, toSecrets) {
+		if fromSecrets[name] != toSecrets[name] {
+			changes = append(changes, newChange(categorySecret, name, fromSecrets[name], toSecrets[name]))
+		}
+	}
+
+	for _, name := range unionKeys(from.Domains, to.Domains) {
+		if from.Domains[name] != to.Domains[name] {
+			changes = append(changes, newChange(categoryDomain, name, from.Domains[name], to.Domains[name]))
+		}
+	}
+
+	return changes
+}
+
+// newChange returns the change of a subject, added if it only has a to value and removed if it only has a from value.
+func newChange(category, subject, from, to string) model.EnvironmentChange {
+	change := changeChanged
+	switch {
+	case from == "":
+		change = changeAdded
+	case to == "":
+		change = changeRemoved
+	}
+	return model.EnvironmentChange{Category: category, Subject: subject, Change: change, From: from, To: to}
+}
+
+// presence describes a plan present in an environment by its preferred version.
+func presence(present bool, preferredVersion string) string {
+	switch {
+	case !present:
+		return ""
+	case preferredVersion == "":
+		return "present, no preferred version"
+	default:
+		return "preferred version " + preferredVersion
+	}
+}
+
+func featurePresence(enabled bool, configuration string) string {
+	switch {
+	case !enabled:
+		return ""
+	case configuration == "":
+		return "enabled"
+	default:
+		return "enabled " + configuration
+	}
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,82 @@
+package environment
+
+import (
+	"testing"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientv1 "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlanSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	snapshot := newPlanSnapshot([]openapiclientv1.TierVersionSet{
+		{Version: "2.0", Status: "Active", EnabledFeatures: []openapiclientv1.ProductTierFeatureDetail{{Feature: utils.ToPtr("LOGS")}}},
+		{Version: "1.0", Status: "Preferred", EnabledFeatures: []openapiclientv1.ProductTierFeatureDetail{
+			{Feature: utils.ToPtr("METRICS")},
+			{Feature: utils.ToPtr("BACKUPS"), Configuration: map[string]interface{}{"retention": 7, "interval": "1h"}},
+		}},
+	})
+	require.Equal(planSnapshot{
+		PreferredVersion: "1.0",
+		Features: map[string]string{
+			"METRICS": "",
+			"BACKUPS": `{"interval":"1h","retention":7}`,
+		},
+	}, snapshot)
+
+	require.Equal(planSnapshot{Features: map[string]string{}}, newPlanSnapshot([]openapiclientv1.TierVersionSet{{Version: "1.0", Status: "Active"}}))
+}
+
+func TestDiffEnvironments(t *testing.T) {
+	require := require.New(t)
+
+	from := environmentSnapshot{
+		Name:             "Dev",
+		DeploymentConfig: "default",
+		RolloutPriority:  []string{"dev", "prod"},
+		Plans: map[string]planSnapshot{
+			"Free":    {PreferredVersion: "1.0", Features: map[string]string{"LOGS": ""}},
+			"Premium": {PreferredVersion: "2.0", Features: map[string]string{"LOGS": "", "BACKUPS": `{"retention":7}`}},
+			"Beta":    {Features: map[string]string{}},
+		},
+		Secrets: []string{"db-password", "api-key"},
+		Domains: map[string]string{"portal": "dev.example.com"},
+	}
+	to := environmentSnapshot{
+		Name:             "Prod",
+		DeploymentConfig: "default",
+		RolloutPriority:  []string{"dev", "prod"},
+		Plans: map[string]planSnapshot{
+			"Free":       {PreferredVersion: "1.0", Features: map[string]string{"LOGS": ""}},
+			"Premium":    {PreferredVersion: "1.5", Features: map[string]string{"BACKUPS": `{"retention":30}`, "METRICS": ""}},
+			"Enterprise": {PreferredVersion: "1.0", Features: map[string]string{}},
+		},
+		Secrets: []string{"db-password"},
+		Domains: map[string]string{"portal": "example.com", "api": "api.example.com"},
+	}
+
+	require.Equal([]model.EnvironmentChange{
+		{Category: categoryPlan, Subject: "Beta", Change: changeRemoved, From: "present, no preferred version"},
+		{Category: categoryPlan, Subject: "Enterprise", Change: changeAdded, To: "preferred version 1.0"},
+		{Category: categoryPreferredVersion, Subject: "Premium", Change: changeChanged, From: "2.0", To: "1.5"},
+		{Category: categoryFeature, Subject: "Premium/BACKUPS", Change: changeChanged, From: `enabled {"retention":7}`, To: `enabled {"retention":30}`},
+		{Category: categoryFeature, Subject: "Premium/LOGS", Change: changeRemoved, From: "enabled"},
+		{Category: categoryFeature, Subject: "Premium/METRICS", Change: changeAdded, To: "enabled"},
+		{Category: categorySecret, Subject: "api-key", Change: changeRemoved, From: "present"},
+		{Category: categoryDomain, Subject: "api", Change: changeAdded, To: "api.example.com"},
+		{Category: categoryDomain, Subject: "portal", Change: changeChanged, From: "dev.example.com", To: "example.com"},
+	}, diffEnvironments(from, to))
+
+	require.Empty(diffEnvironments(from, from))
+
+	to = from
+	to.DeploymentConfig = "canary"
+	to.RolloutPriority = []string{"prod", "dev"}
+	require.Equal([]model.EnvironmentChange{
+		{Category: categoryDeploymentConfig, Subject: "name", Change: changeChanged, From: "default", To: "canary"},
+		{Category: categoryDeploymentConfig, Subject: "rollout priority", Change: changeChanged, From: "dev, prod", To: "prod, dev"},
+	}, diffEnvironments(from, to))
+}
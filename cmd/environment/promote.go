@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
 	openapiclientv1 "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
@@ -22,13 +23,27 @@ const (
 omctl environment promote [service-name] [environment-name]
 
 # Promote environment by ID instead of name
-omctl environment promote --service-id=[service-id] --environment-id=[environment-id]`
+omctl environment promote --service-id=[service-id] --environment-id=[environment-id]
+
+# Promote environment once its instances are healthy, its changes released and the smoke tests pass, wait for the
+# promotion to complete and make the promoted versions the default ones in the target environment
+omctl environment promote [service-name] [environment-name] --gate=no-failed-instances --gate=changes-released --smoke-test="./smoke-test.sh" --wait --set-default`
 )
 
 var promoteCmd = &cobra.Command{
-	Use:          "promote [service-name] [environment-name] [flags]",
-	Short:        "Promote a environment",
-	Long:         `This command helps you promote a environment in your service.`,
+	Use:   "promote [service-name] [environment-name] [flags]",
+	Short: "Promote a environment",
+	Long: `This command helps you promote a environment in your service.
+
+Gates can be checked before promoting, and the promotion doesn't start if any of them fails:
+  no-failed-instances  no instance of the environment is in the FAILED status
+  changes-released     no service plan of the environment has pending changes to release
+A smoke test command given with --smoke-test is also a gate, passing if the command exits 0. It runs in a shell with
+the OMNISTRATE_SERVICE_ID and OMNISTRATE_ENVIRONMENT_ID environment variables set to the promoted environment.
+
+With --wait, the command polls until all the target environments are IN_SYNC with the promoted environment, or until
+--timeout. With --set-default, it then sets the latest version of each service plan in the target environments, which
+is the promoted one once in sync, as the default version.`,
 	Example:      promoteExample,
 	RunE:         runPromote,
 	SilenceUsage: true,
@@ -37,6 +52,12 @@ var promoteCmd = &cobra.Command{
 func init() {
 	promoteCmd.Flags().StringP("service-id", "", "", "Service ID. Required if service name is not provided")
 	promoteCmd.Flags().StringP("environment-id", "", "", "Environment ID. Required if environment name is not provided")
+	promoteCmd.Flags().StringSlice("gate", []string{}, "Gate to check before promoting: "+strings.Join(promotionGates, ", ")+". Can be repeated")
+	promoteCmd.Flags().String("smoke-test", "", "Command that must exit 0 before promoting")
+	promoteCmd.Flags().Bool("wait", false, "Wait until the target environments are in sync with the promoted environment")
+	promoteCmd.Flags().Duration("interval", 10*time.Second, "Polling interval with --wait")
+	promoteCmd.Flags().Duration("timeout", time.Hour, "Maximum time to wait with --wait")
+	promoteCmd.Flags().Bool("set-default", false, "Once the target environments are in sync, set the promoted versions as default in the target environments. Implies --wait")
 }
 
 func runPromote(cmd *cobra.Command, args []string) error {
@@ -46,12 +67,28 @@ func runPromote(cmd *cobra.Command, args []string) error {
 	output, _ := cmd.Flags().GetString("output")
 	serviceID, _ := cmd.Flags().GetString("service-id")
 	environmentID, _ := cmd.Flags().GetString("environment-id")
+	gates, _ := cmd.Flags().GetStringSlice("gate")
+	smokeTest, _ := cmd.Flags().GetString("smoke-test")
+	wait, _ := cmd.Flags().GetBool("wait")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	setDefault, _ := cmd.Flags().GetBool("set-default")
+	wait = wait || setDefault
 
 	// Validate input arguments
 	if err := validatePromoteArguments(args, serviceID, environmentID); err != nil {
 		utils.PrintError(err)
 		return err
 	}
+	if err := validatePromotionGates(gates); err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	if interval <= 0 {
+		err := errors.New("--interval must be positive")
+		utils.PrintError(err)
+		return err
+	}
 
 	// Set service and environment names if provided in args
 	var serviceName, environmentName string
@@ -82,6 +119,40 @@ func runPromote(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Check the gates before promoting
+	var gateResults []model.PromotionGate
+	if len(gates) > 0 || smokeTest != "" {
+		service, err := dataaccess.DescribeService(cmd.Context(), token, serviceID)
+		if err != nil {
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+		if spinner != nil {
+			spinner.UpdateMessage("Checking promotion gates...")
+		}
+		pc := promotionContext{
+			token:         token,
+			serviceID:     serviceID,
+			environmentID: environmentID,
+			plans:         findEnvironmentPlans(service, environmentID),
+		}
+		if gateResults, err = runPromotionGates(cmd.Context(), pc, gates, smokeTest); err != nil {
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+		if failed := failedGates(gateResults); len(failed) > 0 {
+			err = fmt.Errorf("environment not promoted, failed gates: %s", strings.Join(failed, ", "))
+			utils.HandleSpinnerError(spinner, sm, err)
+			if output != "json" {
+				_ = utils.PrintTextTableJsonArrayOutput(output, gateResults)
+			}
+			return err
+		}
+		if spinner != nil {
+			spinner.UpdateMessage("Promoting environment...")
+		}
+	}
+
 	// Promote the environment
 	if err = dataaccess.PromoteServiceEnvironment(cmd.Context(), token, serviceID, environmentID); err != nil {
 		utils.HandleSpinnerError(spinner, sm, err)
@@ -95,20 +166,75 @@ func runPromote(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Get promote status and format output
-	formattedPromotions, err := formatPromoteStatus(cmd.Context(), token, serviceID, environmentID, serviceName, environment)
-	if err != nil {
-		utils.HandleSpinnerError(spinner, sm, err)
-		return err
+	// Get promote status, polling until the promotion completes with --wait
+	ctx := cmd.Context()
+	if wait {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var formattedPromotions []model.Promotion
+	var pending []string
+	for {
+		formattedPromotions, err = formatPromoteStatus(ctx, token, serviceID, environmentID, serviceName, environment)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("timed out after %s waiting for the promotion of %s", timeout, environment.Name)
+			}
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+
+		var inSync bool
+		inSync, pending = promotionsInSync(formattedPromotions)
+		if !wait || inSync {
+			break
+		}
+
+		if spinner != nil {
+			spinner.UpdateMessage(fmt.Sprintf("Waiting for %s to be in sync with %s...", strings.Join(pending, ", "), environment.Name))
+		}
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("timed out after %s waiting for %s to be in sync with %s", timeout, strings.Join(pending, ", "), environment.Name)
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		case <-time.After(interval):
+		}
+	}
+
+	// Set the promoted versions as default in the target environments
+	var defaultVersions []model.PromotionDefaultVersion
+	if setDefault {
+		if spinner != nil {
+			spinner.UpdateMessage("Setting the promoted versions as default...")
+		}
+		if defaultVersions, err = setPromotedVersionsAsDefault(ctx, token, serviceID, environmentID, formattedPromotions); err != nil {
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
 	}
 
 	// Handle output based on format
 	utils.HandleSpinnerSuccess(spinner, sm, "Successfully promoted environment")
 
+	if output != "json" && len(gateResults) > 0 {
+		if err = utils.PrintTextTableJsonArrayOutput(output, gateResults); err != nil {
+			return err
+		}
+	}
+
 	if err = utils.PrintTextTableJsonArrayOutput(output, formattedPromotions); err != nil {
 		return err
 	}
 
+	if output != "json" && len(defaultVersions) > 0 {
+		if err = utils.PrintTextTableJsonArrayOutput(output, defaultVersions); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -197,3 +323,44 @@ func formatPromoteStatus(ctx context.Context, token, serviceID, environmentID, s
 
 	return formattedPromotions, nil
 }
+
+// setPromotedVersionsAsDefault sets the latest version of each service plan of the source environment, in each target
+// environment, as the default version. Plans are matched by name across environments.
+func setPromotedVersionsAsDefault(ctx context.Context, token, serviceID, sourceEnvironmentID string, promotions []model.Promotion) ([]model.PromotionDefaultVersion, error) {
+	// The service is described after the promotion, which can create the plans in the target environments
+	service, err := dataaccess.DescribeService(ctx, token, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultVersions := make([]model.PromotionDefaultVersion, 0)
+	for _, promotion := range promotions {
+		for _, sourcePlan := range findEnvironmentPlans(service, sourceEnvironmentID) {
+			targetPlanID := ""
+			for _, targetPlan := range findEnvironmentPlans(service, promotion.TargetEnvID) {
+				if strings.EqualFold(targetPlan.Name, sourcePlan.Name) {
+					targetPlanID = targetPlan.ProductTierID
+					break
+				}
+			}
+			if targetPlanID == "" {
+				continue
+			}
+
+			version, err := dataaccess.FindLatestVersion(ctx, token, service.Id, targetPlanID)
+			if err != nil {
+				return nil, err
+			}
+			if _, err = dataaccess.SetDefaultServicePlan(ctx, token, service.Id, targetPlanID, version); err != nil {
+				return nil, errors.Wrapf(err, "failed to set version %s of %s as default in %s", version, sourcePlan.Name, promotion.TargetEnvName)
+			}
+
+			defaultVersions = append(defaultVersions, model.PromotionDefaultVersion{
+				TargetEnvName: promotion.TargetEnvName,
+				PlanName:      sourcePlan.Name,
+				Version:       version,
+			})
+		}
+	}
+	return defaultVersions, nil
+}
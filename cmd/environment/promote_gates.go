@@ -0,0 +1,204 @@
+package environment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	openapiclientv1 "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+)
+
+const (
+	gateNoFailedInstances = "no-failed-instances"
+	gateChangesReleased   = "changes-released"
+	gateSmokeTest         = "smoke-test"
+)
+
+var promotionGates = []string{gateNoFailedInstances, gateChangesReleased}
+
+// promoteStatusInSync is the promotion status of a target environment once the promotion completed. The other
+// statuses, OUT_OF_SYNC and UNKNOWN, are those of a promotion still running.
+const promoteStatusInSync = "IN_SYNC"
+
+// promotionContext is what the gates of a promotion need to know about the source environment.
+type promotionContext struct {
+	token         string
+	serviceID     string
+	environmentID string
+	plans         []openapiclientv1.ServicePlan
+}
+
+func validatePromotionGates(gates []string) error {
+	for _, gate := range gates {
+		if !containsFold(promotionGates, gate) {
+			return fmt.Errorf("invalid gate %s, valid gates are: %s", gate, strings.Join(promotionGates, ", "))
+		}
+	}
+	return nil
+}
+
+// runPromotionGates checks the gates in order and returns the result of each of them. All the gates are checked even
+// if one fails, so that everything blocking the promotion is reported at once.
+func runPromotionGates(ctx context.Context, pc promotionContext, gates []string, smokeTest string) ([]model.PromotionGate, error) {
+	results := make([]model.PromotionGate, 0, len(gates)+1)
+	for _, gate := range gates {
+		var result model.PromotionGate
+		var err error
+		switch strings.ToLower(gate) {
+		case gateNoFailedInstances:
+			result, err = checkNoFailedInstances(ctx, pc)
+		case gateChangesReleased:
+			result, err = checkChangesReleased(ctx, pc)
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if smokeTest != "" {
+		results = append(results, runSmokeTest(ctx, pc, smokeTest))
+	}
+
+	return results, nil
+}
+
+func checkNoFailedInstances(ctx context.Context, pc promotionContext) (model.PromotionGate, error) {
+	instances := make([]openapiclientfleet.ResourceInstance, 0)
+	for _, plan := range pc.plans {
+		planInstances, err := dataaccess.ListResourceInstances(ctx, pc.token, pc.serviceID, pc.environmentID, plan.ProductTierID)
+		if err != nil {
+			return model.PromotionGate{}, err
+		}
+		instances = append(instances, planInstances...)
+	}
+	return failedInstancesGate(instances), nil
+}
+
+// failedInstancesGate passes if none of the instances is in the FAILED status.
+func failedInstancesGate(instances []openapiclientfleet.ResourceInstance) model.PromotionGate {
+	failed := make([]string, 0)
+	for _, instance := range instances {
+		if strings.EqualFold(instance.ConsumptionResourceInstanceResult.GetStatus(), "FAILED") {
+			failed = append(failed, instance.ConsumptionResourceInstanceResult.GetId())
+		}
+	}
+	sort.Strings(failed)
+
+	if len(failed) == 0 {
+		return model.PromotionGate{Gate: gateNoFailedInstances, Passed: true, Detail: fmt.Sprintf("none of the %d instances failed", len(instances))}
+	}
+	return model.PromotionGate{Gate: gateNoFailedInstances, Detail: "failed instances: " + strings.Join(failed, ", ")}
+}
+
+func checkChangesReleased(ctx context.Context, pc promotionContext) (model.PromotionGate, error) {
+	pendingChanges := make(map[string]int)
+	for _, plan := range pc.plans {
+		productTier, err := dataaccess.DescribeProductTier(ctx, pc.token, pc.serviceID, plan.ProductTierID)
+		if err != nil {
+			return model.PromotionGate{}, err
+		}
+		serviceModel, err := dataaccess.DescribeServiceModel(ctx, pc.token, pc.serviceID, productTier.ServiceModelId)
+		if err != nil {
+			return model.PromotionGate{}, err
+		}
+		changes, err := dataaccess.DescribePendingChanges(ctx, pc.token, pc.serviceID, serviceModel.ServiceApiId, plan.ProductTierID)
+		if err != nil {
+			return model.PromotionGate{}, err
+		}
+		pendingChanges[plan.Name] = len(changes.ResourceChangeSets)
+	}
+	return changesReleasedGate(pendingChanges), nil
+}
+
+// changesReleasedGate passes if none of the plans, by name, has resources with pending changes.
+func changesReleasedGate(pendingChanges map[string]int) model.PromotionGate {
+	unreleased := make([]string, 0)
+	for planName, resources := range pendingChanges {
+		if resources > 0 {
+			unreleased = append(unreleased, fmt.Sprintf("%s (%d resources)", planName, resources))
+		}
+	}
+	sort.Strings(unreleased)
+
+	if len(unreleased) == 0 {
+		return model.PromotionGate{Gate: gateChangesReleased, Passed: true, Detail: fmt.Sprintf("no pending changes in %d plans", len(pendingChanges))}
+	}
+	return model.PromotionGate{Gate: gateChangesReleased, Detail: "plans with unreleased changes: " + strings.Join(unreleased, ", ")}
+}
+
+// runSmokeTest runs the smoke test command in a shell, with the service and source environment IDs in the
+// OMNISTRATE_SERVICE_ID and OMNISTRATE_ENVIRONMENT_ID environment variables. The gate passes if the command exits 0.
+func runSmokeTest(ctx context.Context, pc promotionContext, command string) model.PromotionGate {
+	var out bytes.Buffer
+	smokeTest := exec.CommandContext(ctx, "sh", "-c", command)
+	smokeTest.Env = append(os.Environ(),
+		"OMNISTRATE_SERVICE_ID="+pc.serviceID,
+		"OMNISTRATE_ENVIRONMENT_ID="+pc.environmentID,
+	)
+	smokeTest.Stdout = &out
+	smokeTest.Stderr = &out
+
+	start := time.Now()
+	if err := smokeTest.Run(); err != nil {
+		detail := fmt.Sprintf("%s: %v", command, err)
+		if lastLine := lastOutputLine(out.String()); lastLine != "" {
+			detail += ": " + lastLine
+		}
+		return model.PromotionGate{Gate: gateSmokeTest, Detail: detail}
+	}
+	return model.PromotionGate{Gate: gateSmokeTest, Passed: true, Detail: fmt.Sprintf("%s passed in %s", command, time.Since(start).Round(time.Second))}
+}
+
+func lastOutputLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func failedGates(results []model.PromotionGate) []string {
+	failed := make([]string, 0)
+	for _, result := range results {
+		if !result.Passed {
+			failed = append(failed, result.Gate)
+		}
+	}
+	return failed
+}
+
+// promotionsInSync returns whether all the target environments are in sync with the source environment, and the
+// names of the ones that are not yet.
+func promotionsInSync(promotions []model.Promotion) (inSync bool, pending []string) {
+	for _, promotion := range promotions {
+		if !strings.EqualFold(promotion.PromoteStatus, promoteStatusInSync) {
+			pending = append(pending, promotion.TargetEnvName)
+		}
+	}
+	return len(pending) == 0, pending
+}
+
+// findEnvironmentPlans returns the service plans of an environment of the service.
+func findEnvironmentPlans(service *openapiclientv1.DescribeServiceResult, environmentID string) []openapiclientv1.ServicePlan {
+	for _, environment := range service.ServiceEnvironments {
+		if environment.Id == environmentID {
+			return environment.ServicePlans
+		}
+	}
+	return nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package environment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePromotionGates(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(validatePromotionGates(nil))
+	require.NoError(validatePromotionGates([]string{"no-failed-instances", "Changes-Released"}))
+	require.ErrorContains(validatePromotionGates([]string{"smoke-test"}), "invalid gate smoke-test")
+}
+
+func TestFailedInstancesGate(t *testing.T) {
+	require := require.New(t)
+
+	newInstance := func(id, status string) openapiclientfleet.ResourceInstance {
+		instance := openapiclientfleet.ResourceInstance{}
+		instance.ConsumptionResourceInstanceResult.Id = utils.ToPtr(id)
+		instance.ConsumptionResourceInstanceResult.Status = utils.ToPtr(status)
+		return instance
+	}
+
+	gate := failedInstancesGate([]openapiclientfleet.ResourceInstance{newInstance("instance-1", "RUNNING"), newInstance("instance-2", "STOPPED")})
+	require.Equal(model.PromotionGate{Gate: gateNoFailedInstances, Passed: true, Detail: "none of the 2 instances failed"}, gate)
+
+	gate = failedInstancesGate([]openapiclientfleet.ResourceInstance{newInstance("instance-2", "FAILED"), newInstance("instance-1", "failed"), newInstance("instance-3", "RUNNING")})
+	require.Equal(model.PromotionGate{Gate: gateNoFailedInstances, Detail: "failed instances: instance-1, instance-2"}, gate)
+}
+
+func TestChangesReleasedGate(t *testing.T) {
+	require := require.New(t)
+
+	gate := changesReleasedGate(map[string]int{"Free": 0, "Premium": 0})
+	require.True(gate.Passed)
+	require.Equal("no pending changes in 2 plans", gate.Detail)
+
+	gate = changesReleasedGate(map[string]int{"Free": 0, "Premium": 2, "Enterprise": 1})
+	require.False(gate.Passed)
+	require.Equal("plans with unreleased changes: Enterprise (1 resources), Premium (2 resources)", gate.Detail)
+}
+
+func TestRunSmokeTest(t *testing.T) {
+	require := require.New(t)
+
+	pc := promotionContext{serviceID: "s-1", environmentID: "se-1"}
+
+	gate := runSmokeTest(context.Background(), pc, `test "$OMNISTRATE_SERVICE_ID/$OMNISTRATE_ENVIRONMENT_ID" = s-1/se-1`)
+	require.True(gate.Passed, gate.Detail)
+
+	gate = runSmokeTest(context.Background(), pc, "echo checking; echo endpoint unreachable >&2; exit 3")
+	require.False(gate.Passed)
+	require.Equal("echo checking; echo endpoint unreachable >&2; exit 3: exit status 3: endpoint unreachable", gate.Detail)
+
+	require.Equal([]string{gateSmokeTest}, failedGates([]model.PromotionGate{{Gate: gateNoFailedInstances, Passed: true}, gate}))
+}
+
+func TestPromotionsInSync(t *testing.T) {
+	require := require.New(t)
+
+	inSync, pending := promotionsInSync([]model.Promotion{
+		{TargetEnvName: "Staging", PromoteStatus: "IN_SYNC"},
+		{TargetEnvName: "Prod", PromoteStatus: "OUT_OF_SYNC"},
+		{TargetEnvName: "QA", PromoteStatus: "UNKNOWN"},
+	})
+	require.False(inSync)
+	require.Equal([]string{"Prod", "QA"}, pending)
+
+	inSync, pending = promotionsInSync([]model.Promotion{
+		{TargetEnvName: "Staging", PromoteStatus: "IN_SYNC"},
+		{TargetEnvName: "Prod", PromoteStatus: "in_sync"},
+	})
+	require.True(inSync)
+	require.Empty(pending)
+}
@@ -1,6 +1,8 @@
 package instance
 
 import (
+	"context"
+	"slices"
 	"strings"
 
 	"github.com/chelnak/ysmrr"
@@ -9,12 +11,28 @@ import (
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
 	"github.com/spf13/cobra"
 )
 
 const (
 	listExample = `# List instance deployments of the service postgres in the prod and dev environments
-omctl instance list -f="service:postgres,environment:Production" -f="service:postgres,environment:Dev"`
+omctl instance list -f="service:postgres,environment:Production" -f="service:postgres,environment:Dev"
+
+# List running instance deployments in any us region, except those of the free plan
+omctl instance list -f="status = RUNNING and region like us-* and plan != free"
+
+# List instance deployments of the postgres or mysql services that are not healthy
+omctl instance list -f="service in (postgres, mysql) and not status in (RUNNING, STOPPED)"
+
+# List instance deployments whose version matches a regular expression
+omctl instance list -f='version =~ "^1\.[0-9]+$"'
+
+# List the ten most recently created failed instance deployments
+omctl instance list -f="status = FAILED" --sort-by created_at,desc --limit 10
+
+# Print the IDs of the running instance deployments, one per line, for piping into other tools
+omctl instance list -f="status = RUNNING" --columns instance_id --no-headers -o text`
 	defaultMaxNameLength = 30 // Maximum length of the name column in the table
 )
 
@@ -22,7 +40,10 @@ var listCmd = &cobra.Command{
 	Use:   "list [flags]",
 	Short: "List instance deployments for your service",
 	Long: `This command helps you list instance deployments for your service.
-You can filter for specific instances by using the filter flag.`,
+You can filter for specific instances by using the filter flag.
+
+The creation time of the instances, created_at, takes one more request per plan to retrieve, so it's only retrieved
+and printed when it's filtered on, sorted by or selected with --columns.`,
 	Example:      listExample,
 	RunE:         runList,
 	SilenceUsage: true,
@@ -30,7 +51,8 @@ You can filter for specific instances by using the filter flag.`,
 
 func init() {
 
-	listCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of instances. E.g.: key1:value1,key2:value2, which filters instances where key1 equals value1 and key2 equals value2. Allow use of multiple filters to form the logical OR operation. Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.Instance{}), ",")+". Check the examples for more details.")
+	listCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of instances, e.g. \"service:postgres and environment in (Production, Staging)\". "+utils.FilterExpressionSyntax+" Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.Instance{}, utils.ScalarFieldsOnly), ",")+". Check the examples for more details.")
+	common.AddListFlags(listCmd, utils.GetSupportedFilterKeys(model.Instance{}, utils.ScalarFieldsOnly))
 	listCmd.Flags().Bool("truncate", false, "Truncate long names in the output")
 }
 
@@ -54,8 +76,15 @@ func runList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Parse filters into a map
-	filterMaps, err := utils.ParseFilters(filters, utils.GetSupportedFilterKeys(model.Instance{}))
+	// Parse filters into expressions
+	filterExpressions, err := utils.ParseFilterExpressions(filters, utils.GetSupportedFilterKeys(model.Instance{}, utils.ScalarFieldsOnly))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Parse sorting, pagination and column options
+	listOpts, err := common.GetListOptions(cmd, utils.GetSupportedFilterKeys(model.Instance{}, utils.ScalarFieldsOnly))
 	if err != nil {
 		utils.PrintError(err)
 		return err
@@ -84,6 +113,17 @@ func runList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// The inventory search doesn't return when instances were created, read it from the instances of each plan when
+	// it's used
+	var createdAt map[string]string
+	if usesCreationTime(filterExpressions, listOpts) {
+		createdAt, err = instanceCreationTimes(cmd.Context(), token, searchRes.ResourceInstanceResults)
+		if err != nil {
+			utils.PrintError(err)
+			return err
+		}
+	}
+
 	formattedInstances := make([]model.Instance, 0)
 	for i := range searchRes.ResourceInstanceResults {
 		instance := searchRes.ResourceInstanceResults[i]
@@ -93,9 +133,10 @@ func runList(cmd *cobra.Command, args []string) error {
 
 		// Format instance
 		formattedInstance := formatInstance(&instance, truncateNames)
+		formattedInstance.CreatedAt = createdAt[instance.Id]
 
 		// Check if the instance matches the filters
-		ok, err := utils.MatchesFilters(formattedInstance, filterMaps)
+		ok, err := utils.MatchesFilterExpressions(formattedInstance, filterExpressions)
 		if err != nil {
 			utils.PrintError(err)
 			return err
@@ -109,11 +150,60 @@ func runList(cmd *cobra.Command, args []string) error {
 		utils.HandleSpinnerSuccess(spinner, sm, "No instances found.")
 	}
 
+	// Sort and paginate the results
+	formattedInstances, err = utils.SortAndPaginate(formattedInstances, listOpts)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
 	// Print output
-	err = utils.PrintTextTableJsonArrayOutput(output, formattedInstances)
+	err = utils.PrintListOutput(output, formattedInstances, listOpts)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// usesCreationTime reports whether the creation time of the instances is filtered on, sorted by or printed.
+func usesCreationTime(filterExpressions []*utils.FilterExpression, listOpts utils.ListOptions) bool {
+	const createdAtKey = "created_at"
+	if listOpts.SortBy == createdAtKey || slices.Contains(listOpts.Columns, createdAtKey) {
+		return true
+	}
+	return slices.ContainsFunc(filterExpressions, func(expression *utils.FilterExpression) bool {
+		return expression.UsesKey(createdAtKey)
+	})
+}
+
+// instanceCreationTimes returns the creation time of the instances by ID, listing the instances of each plan they
+// belong to once.
+func instanceCreationTimes(ctx context.Context, token string, instances []openapiclientfleet.ResourceInstanceSearchRecord) (map[string]string, error) {
+	type planKey struct {
+		serviceID, environmentID, planID string
+	}
+
+	plans := make(map[planKey]bool)
+	for _, instance := range instances {
+		if instance.Id != "" {
+			plans[planKey{instance.ServiceId, instance.ServiceEnvironmentId, instance.ProductTierId}] = true
+		}
+	}
+
+	createdAt := make(map[string]string)
+	for plan := range plans {
+		planInstances, err := dataaccess.ListResourceInstances(ctx, token, plan.serviceID, plan.environmentID, plan.planID)
+		if err != nil {
+			return nil, err
+		}
+		for _, planInstance := range planInstances {
+			instance := planInstance.ConsumptionResourceInstanceResult
+			if instance.Id != nil && instance.CreatedAt != nil {
+				createdAt[*instance.Id] = *instance.CreatedAt
+			}
+		}
+	}
+
+	return createdAt, nil
+}
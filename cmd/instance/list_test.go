@@ -0,0 +1,24 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsesCreationTime(t *testing.T) {
+	require := require.New(t)
+
+	expressions, err := utils.ParseFilterExpressions([]string{"status = FAILED"}, utils.GetSupportedFilterKeys(model.Instance{}, utils.ScalarFieldsOnly))
+	require.NoError(err)
+	require.False(usesCreationTime(expressions, utils.ListOptions{SortBy: "status", Columns: []string{"instance_id", "status"}}))
+
+	require.True(usesCreationTime(expressions, utils.ListOptions{SortBy: "created_at"}))
+	require.True(usesCreationTime(expressions, utils.ListOptions{Columns: []string{"instance_id", "created_at"}}))
+
+	expressions, err = utils.ParseFilterExpressions([]string{"status = FAILED", "created_at like 2025-*"}, utils.GetSupportedFilterKeys(model.Instance{}, utils.ScalarFieldsOnly))
+	require.NoError(err)
+	require.True(usesCreationTime(expressions, utils.ListOptions{}))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/auth/logout"
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/build"
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/customnetwork"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/dashboard"
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/deploymentcell"
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/domain"
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/environment"
@@ -126,6 +127,7 @@ func init() {
 	RootCmd.AddCommand(servicesorchestration.Cmd)
 	RootCmd.AddCommand(inspect.Cmd)
 	RootCmd.AddCommand(secret.Cmd)
+	RootCmd.AddCommand(dashboard.Cmd)
 
 	// Hide the default completion command
 	RootCmd.Root().CompletionOptions.DisableDefaultCmd = true
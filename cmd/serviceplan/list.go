@@ -16,7 +16,10 @@ import (
 
 const (
 	listExample = `# List service plans of the service postgres in the prod and dev environments
-omctl service-plan list -f="service_name:postgres,environment:prod" -f="service:postgres,environment:dev"`
+omctl service-plan list -f="service_name:postgres,environment:prod" -f="service_name:postgres,environment:dev"
+
+# List service plans of the services starting with postgres, except in the dev environment
+omctl service-plan list -f="service_name like postgres* and environment != dev"`
 	defaultMaxNameLength = 30 // Maximum length of the name column in the table
 )
 
@@ -32,7 +35,8 @@ You can filter for specific service plans by using the filter flag.`,
 
 func init() {
 
-	listCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of service plans. E.g.: key1:value1,key2:value2, which filters service plans where key1 equals value1 and key2 equals value2. Allow use of multiple filters to form the logical OR operation. Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.ServicePlanVersion{}), ",")+". Check the examples for more details.")
+	listCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of service plans, e.g. \"service_name:postgres and environment in (prod, dev)\". "+utils.FilterExpressionSyntax+" Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.ServicePlan{}, utils.ScalarFieldsOnly), ",")+". Check the examples for more details.")
+	common.AddListFlags(listCmd, utils.GetSupportedFilterKeys(model.ServicePlan{}, utils.ScalarFieldsOnly))
 	listCmd.Flags().Bool("truncate", false, "Truncate long names in the output")
 	listCmd.Args = cobra.NoArgs
 }
@@ -46,7 +50,14 @@ func runList(cmd *cobra.Command, args []string) error {
 	truncateNames, _ := cmd.Flags().GetBool("truncate")
 
 	// Parse and validate filters
-	filterMaps, err := utils.ParseFilters(filters, utils.GetSupportedFilterKeys(model.ServicePlanVersion{}))
+	filterExpressions, err := utils.ParseFilterExpressions(filters, utils.GetSupportedFilterKeys(model.ServicePlan{}, utils.ScalarFieldsOnly))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Parse sorting, pagination and column options
+	listOpts, err := common.GetListOptions(cmd, utils.GetSupportedFilterKeys(model.ServicePlan{}, utils.ScalarFieldsOnly))
 	if err != nil {
 		utils.PrintError(err)
 		return err
@@ -87,7 +98,7 @@ func runList(cmd *cobra.Command, args []string) error {
 					return err
 				}
 
-				match, err := utils.MatchesFilters(formattedServicePlan, filterMaps)
+				match, err := utils.MatchesFilterExpressions(formattedServicePlan, filterExpressions)
 				if err != nil {
 					utils.HandleSpinnerError(spinner, sm, err)
 					return err
@@ -107,8 +118,15 @@ func runList(cmd *cobra.Command, args []string) error {
 		utils.HandleSpinnerSuccess(spinner, sm, "Service plans retrieved successfully.")
 	}
 
-	// Format output as requested
-	err = utils.PrintTextTableJsonArrayOutput(output, formattedServicePlans)
+	// Sort and paginate the results
+	formattedServicePlans, err = utils.SortAndPaginate(formattedServicePlans, listOpts)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Print output
+	err = utils.PrintListOutput(output, formattedServicePlans, listOpts)
 	if err != nil {
 		return err
 	}
@@ -19,7 +19,10 @@ import (
 
 const (
 	listVersionsExample = `# List service plan versions of the service postgres in the prod and dev environments
-omctl service-plan list-versions postgres postgres -f="service_name:postgres,environment:prod" -f="service:postgres,environment:dev"`
+omctl service-plan list-versions postgres postgres -f="service_name:postgres,environment:prod" -f="service_name:postgres,environment:dev"
+
+# List the active and preferred versions of a service plan
+omctl service-plan list-versions postgres postgres -f="version_set_status in (Active, Preferred)"`
 )
 
 var listVersionsCmd = &cobra.Command{
@@ -39,7 +42,7 @@ func init() {
 	listVersionsCmd.Flags().IntP("latest-n", "", -1, "List only the latest N service plan versions")
 	listVersionsCmd.Flags().StringP("environment", "", "", "Environment name. Use this flag with service name and plan name to describe the version in a specific environment")
 
-	listVersionsCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of service plan versions. E.g.: key1:value1,key2:value2, which filters service plans where key1 equals value1 and key2 equals value2. Allow use of multiple filters to form the logical OR operation. Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.ServicePlanVersion{}), ",")+". Check the examples for more details.")
+	listVersionsCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of service plan versions, e.g. \"version_set_status in (Active, Preferred)\". "+utils.FilterExpressionSyntax+" Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.ServicePlanVersion{}, utils.ScalarFieldsOnly), ",")+". Check the examples for more details.")
 	listVersionsCmd.Flags().Bool("truncate", false, "Truncate long names in the output")
 	err := listVersionsCmd.Flags().MarkHidden("latest-n")
 	if err != nil {
@@ -72,7 +75,7 @@ func runListVersions(cmd *cobra.Command, args []string) error {
 	}
 
 	// Parse and validate filters
-	filterMaps, err := utils.ParseFilters(filters, utils.GetSupportedFilterKeys(model.ServicePlanVersion{}))
+	filterExpressions, err := utils.ParseFilterExpressions(filters, utils.GetSupportedFilterKeys(model.ServicePlanVersion{}, utils.ScalarFieldsOnly))
 	if err != nil {
 		utils.PrintError(err)
 		return err
@@ -127,7 +130,7 @@ func runListVersions(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		match, err := utils.MatchesFilters(formattedServicePlanVersion, filterMaps)
+		match, err := utils.MatchesFilterExpressions(formattedServicePlanVersion, filterExpressions)
 		if err != nil {
 			utils.HandleSpinnerError(spinner, sm, err)
 			return err
@@ -20,6 +20,7 @@ func init() {
 	Cmd.AddCommand(describeVersionCmd)
 	Cmd.AddCommand(listCmd)
 	Cmd.AddCommand(listVersionsCmd)
+	Cmd.AddCommand(versionReportCmd)
 	Cmd.AddCommand(enableCmd)
 	Cmd.AddCommand(disableCmd)
 	Cmd.AddCommand(updateCmd)
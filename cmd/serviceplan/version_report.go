@@ -0,0 +1,371 @@
+package serviceplan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	openapiclient "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	versionReportExample = `# Show how the instances of a service plan are spread across its versions
+omctl service-plan version-report postgres postgres
+
+# Show the version report of the prod environment only
+omctl service-plan version-report postgres postgres --environment prod
+
+# Save the report as JSON, e.g. to chart the version drift of the fleet over time
+omctl service-plan version-report --service-id=[service-id] --plan-id=[plan-id] -o json > report-$(date +%F).json`
+
+	unknownVersionStatus = "Unknown"
+)
+
+var versionReportCmd = &cobra.Command{
+	Use:   "version-report [service-name] [plan-name] [flags]",
+	Short: "Show the distribution of instances across the versions of a Service Plan",
+	Long: `This command shows, for each version of a service plan, its status (preferred, active or deprecated) and the
+number of instances running it by environment, cloud provider, region and status, along with the age of its oldest
+instance.
+
+Versions without instances are included, and instances on a version that is no longer listed are reported with an
+Unknown version status. The JSON output includes the time the report was generated.`,
+	Example:      versionReportExample,
+	RunE:         runVersionReport,
+	SilenceUsage: true,
+}
+
+func init() {
+	versionReportCmd.Flags().StringP("service-id", "", "", "Service ID. Required if service name is not provided")
+	versionReportCmd.Flags().StringP("plan-id", "", "", "Plan ID. Required if plan name is not provided")
+	versionReportCmd.Flags().StringP("environment", "", "", "Environment name. Only report the service plan in this environment")
+}
+
+// servicePlanRef is a service plan in one environment of a service.
+type servicePlanRef struct {
+	environmentID string
+	environment   string
+	planID        string
+	planName      string
+}
+
+func runVersionReport(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve command-line flags
+	serviceID, _ := cmd.Flags().GetString("service-id")
+	planID, _ := cmd.Flags().GetString("plan-id")
+	environment, _ := cmd.Flags().GetString("environment")
+	output, _ := cmd.Flags().GetString("output")
+
+	// Validate input arguments
+	if err := validateListVersionsArguments(args, serviceID, planID); err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Set service and service plan names if provided in args
+	var serviceName, planName string
+	if len(args) == 2 {
+		serviceName, planName = args[0], args[1]
+	}
+
+	// Ensure user is logged in
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not JSON
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		spinner = sm.AddSpinner("Building service plan version report...")
+		sm.Start()
+	}
+
+	report, err := buildVersionReport(cmd.Context(), token, serviceID, serviceName, planID, planName, environment)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("Found %d instances across %d versions", report.TotalInstances, len(report.Versions)))
+
+	if output == "json" {
+		return utils.PrintTextTableJsonOutput(output, report)
+	}
+
+	rows := make([]model.ServicePlanVersionUsageRow, 0, len(report.Versions))
+	for _, usage := range report.Versions {
+		rows = append(rows, formatVersionUsageRow(usage))
+	}
+	return utils.PrintTextTableJsonArrayOutput(output, rows)
+}
+
+func buildVersionReport(ctx context.Context, token, serviceIDArg, serviceNameArg, planIDArg, planNameArg, environmentArg string) (*model.ServicePlanVersionReport, error) {
+	serviceID, serviceName, plans, err := findServicePlans(ctx, token, serviceIDArg, serviceNameArg, planIDArg, planNameArg, environmentArg)
+	if err != nil {
+		return nil, err
+	}
+
+	searchRes, err := dataaccess.SearchInventory(ctx, token, "resourceinstance:i")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	report := &model.ServicePlanVersionReport{
+		GeneratedAt: now.Format(time.RFC3339),
+		ServiceID:   serviceID,
+		ServiceName: serviceName,
+		PlanName:    plans[0].planName,
+		Versions:    make([]model.ServicePlanVersionUsage, 0),
+	}
+
+	for _, plan := range plans {
+		versions, err := dataaccess.ListVersions(ctx, token, serviceID, plan.planID)
+		if err != nil {
+			return nil, err
+		}
+
+		var instances []openapiclientfleet.ResourceInstanceSearchRecord
+		for _, instance := range searchRes.ResourceInstanceResults {
+			if instance.ServiceId == serviceID && instance.ProductTierId == plan.planID && instance.ServiceEnvironmentId == plan.environmentID {
+				instances = append(instances, instance)
+			}
+		}
+
+		createdAt := make(map[string]time.Time)
+		if len(instances) > 0 {
+			details, err := dataaccess.ListResourceInstances(ctx, token, serviceID, plan.environmentID, plan.planID)
+			if err != nil {
+				return nil, err
+			}
+			for _, detail := range details {
+				instance := detail.ConsumptionResourceInstanceResult
+				if instance.Id == nil || instance.CreatedAt == nil {
+					continue
+				}
+				if t, err := time.Parse(time.RFC3339, *instance.CreatedAt); err == nil {
+					createdAt[*instance.Id] = t
+				}
+			}
+		}
+
+		usages := buildVersionUsages(plan, versions.TierVersionSets, instances, createdAt, now)
+		for _, usage := range usages {
+			report.TotalInstances += usage.Instances
+		}
+		report.Versions = append(report.Versions, usages...)
+	}
+
+	return report, nil
+}
+
+// findServicePlans returns the service plan in every environment of the service, or only in the given environment.
+func findServicePlans(ctx context.Context, token, serviceIDArg, serviceNameArg, planIDArg, planNameArg, environmentArg string) (serviceID, serviceName string, plans []servicePlanRef, err error) {
+	searchRes, err := dataaccess.SearchInventory(ctx, token, "service:s")
+	if err != nil {
+		return
+	}
+
+	serviceFound := 0
+	for _, service := range searchRes.ServiceResults {
+		if !strings.EqualFold(service.Name, serviceNameArg) && service.Id != serviceIDArg {
+			continue
+		}
+		serviceID, serviceName = service.Id, service.Name
+		serviceFound += 1
+	}
+
+	if serviceFound == 0 {
+		err = fmt.Errorf("service not found. Please check input values and try again")
+		return
+	}
+
+	if serviceFound > 1 {
+		err = fmt.Errorf("multiple services with the same name found. Please provide the service ID instead of the name")
+		return
+	}
+
+	describeServiceRes, err := dataaccess.DescribeService(ctx, token, serviceID)
+	if err != nil {
+		return
+	}
+
+	envFound := 0
+	for _, env := range describeServiceRes.ServiceEnvironments {
+		if environmentArg != "" && !strings.EqualFold(environmentArg, env.Name) {
+			continue
+		}
+		envFound += 1
+		for _, servicePlan := range env.ServicePlans {
+			if !strings.EqualFold(servicePlan.Name, planNameArg) && servicePlan.ProductTierID != planIDArg {
+				continue
+			}
+			plans = append(plans, servicePlanRef{
+				environmentID: env.Id,
+				environment:   env.Name,
+				planID:        servicePlan.ProductTierID,
+				planName:      servicePlan.Name,
+			})
+		}
+	}
+
+	if environmentArg != "" && envFound == 0 {
+		err = fmt.Errorf("environment not found. Please check input values and try again")
+		return
+	}
+
+	if len(plans) == 0 {
+		err = fmt.Errorf("service plan not found. Please check input values and try again")
+		return
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].environment < plans[j].environment
+	})
+
+	return
+}
+
+// buildVersionUsages aggregates the instances of a service plan by version. Every listed version is included, newest
+// release first, followed by the versions of instances that aren't listed anymore.
+func buildVersionUsages(
+	plan servicePlanRef,
+	versions []openapiclient.TierVersionSet,
+	instances []openapiclientfleet.ResourceInstanceSearchRecord,
+	createdAt map[string]time.Time,
+	now time.Time,
+) []model.ServicePlanVersionUsage {
+	newUsage := func(version string) *model.ServicePlanVersionUsage {
+		return &model.ServicePlanVersionUsage{
+			Environment:       plan.environment,
+			PlanID:            plan.planID,
+			Version:           version,
+			VersionStatus:     unknownVersionStatus,
+			InstancesByCloud:  make(map[string]int),
+			InstancesByRegion: make(map[string]int),
+			InstancesByStatus: make(map[string]int),
+		}
+	}
+
+	usages := make(map[string]*model.ServicePlanVersionUsage)
+	for _, version := range versions {
+		usage := newUsage(version.Version)
+		usage.VersionStatus = version.Status
+		usage.ReleasedAt = version.ReleasedAt
+		if version.Name != nil {
+			usage.VersionName = *version.Name
+		}
+		usages[version.Version] = usage
+	}
+
+	oldest := make(map[string]time.Time)
+	for _, instance := range instances {
+		version := ""
+		if instance.ProductTierVersion != nil {
+			version = *instance.ProductTierVersion
+		}
+
+		usage, ok := usages[version]
+		if !ok {
+			usage = newUsage(version)
+			usages[version] = usage
+		}
+
+		usage.Instances++
+		usage.InstancesByCloud[instance.CloudProvider]++
+		usage.InstancesByRegion[instance.RegionCode]++
+		usage.InstancesByStatus[instance.Status]++
+
+		if t, ok := createdAt[instance.Id]; ok {
+			if current, found := oldest[version]; !found || t.Before(current) {
+				oldest[version] = t
+			}
+		}
+	}
+
+	result := make([]model.ServicePlanVersionUsage, 0, len(usages))
+	for version, usage := range usages {
+		if t, ok := oldest[version]; ok {
+			usage.OldestInstanceCreatedAt = t.UTC().Format(time.RFC3339)
+			usage.OldestInstanceAgeDays = int(now.Sub(t).Hours() / 24)
+		}
+		result = append(result, *usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if (a.ReleasedAt == "") != (b.ReleasedAt == "") {
+			return a.ReleasedAt != ""
+		}
+		if a.ReleasedAt != b.ReleasedAt {
+			ta, _ := time.Parse(time.RFC3339, a.ReleasedAt)
+			tb, _ := time.Parse(time.RFC3339, b.ReleasedAt)
+			return ta.After(tb)
+		}
+		return a.Version > b.Version
+	})
+
+	return result
+}
+
+func formatVersionUsageRow(usage model.ServicePlanVersionUsage) model.ServicePlanVersionUsageRow {
+	version := usage.Version
+	if usage.VersionName != "" {
+		version = fmt.Sprintf("%s (%s)", usage.Version, usage.VersionName)
+	}
+
+	oldestInstanceAge := "-"
+	if usage.OldestInstanceCreatedAt != "" {
+		oldestInstanceAge = fmt.Sprintf("%dd", usage.OldestInstanceAgeDays)
+	}
+
+	return model.ServicePlanVersionUsageRow{
+		Environment:       usage.Environment,
+		Version:           version,
+		VersionStatus:     usage.VersionStatus,
+		Instances:         usage.Instances,
+		Clouds:            formatCounts(usage.InstancesByCloud),
+		Regions:           formatCounts(usage.InstancesByRegion),
+		Statuses:          formatCounts(usage.InstancesByStatus),
+		OldestInstanceAge: oldestInstanceAge,
+	}
+}
+
+// formatCounts formats counts as "key: count" pairs ordered by key, e.g. "aws: 3, gcp: 1".
+func formatCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		name := key
+		if name == "" {
+			name = "unknown"
+		}
+		parts = append(parts, fmt.Sprintf("%s: %d", name, counts[key]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,71 @@
+package serviceplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	openapiclient "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildVersionUsages(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	plan := servicePlanRef{environmentID: "se-1", environment: "prod", planID: "pt-1", planName: "postgres"}
+	versions := []openapiclient.TierVersionSet{
+		{Version: "1.0", Status: "Deprecated", ReleasedAt: "2024-01-01T00:00:00Z"},
+		{Version: "2.0", Status: "Preferred", ReleasedAt: "2024-03-01T00:00:00Z", Name: utils.ToPtr("spring")},
+		{Version: "3.0", Status: "Active", ReleasedAt: "2024-05-01T00:00:00Z"},
+	}
+	instances := []openapiclientfleet.ResourceInstanceSearchRecord{
+		{Id: "i-1", ProductTierVersion: utils.ToPtr("1.0"), CloudProvider: "aws", RegionCode: "us-east-1", Status: "RUNNING"},
+		{Id: "i-2", ProductTierVersion: utils.ToPtr("2.0"), CloudProvider: "aws", RegionCode: "us-east-1", Status: "RUNNING"},
+		{Id: "i-3", ProductTierVersion: utils.ToPtr("2.0"), CloudProvider: "gcp", RegionCode: "us-central1", Status: "STOPPED"},
+		{Id: "i-4", ProductTierVersion: utils.ToPtr("0.9"), CloudProvider: "aws", RegionCode: "eu-west-1", Status: "FAILED"},
+	}
+	createdAt := map[string]time.Time{
+		"i-1": now.AddDate(0, 0, -100),
+		"i-2": now.AddDate(0, 0, -10),
+		"i-3": now.AddDate(0, 0, -42),
+	}
+
+	usages := buildVersionUsages(plan, versions, instances, createdAt, now)
+	require.Len(usages, 4)
+
+	// Newest release first, unlisted versions last
+	require.Equal("3.0", usages[0].Version)
+	require.Equal("2.0", usages[1].Version)
+	require.Equal("1.0", usages[2].Version)
+	require.Equal("0.9", usages[3].Version)
+
+	require.Equal(0, usages[0].Instances)
+	require.Equal("Active", usages[0].VersionStatus)
+	require.Empty(usages[0].OldestInstanceCreatedAt)
+
+	require.Equal("spring", usages[1].VersionName)
+	require.Equal(2, usages[1].Instances)
+	require.Equal(map[string]int{"aws": 1, "gcp": 1}, usages[1].InstancesByCloud)
+	require.Equal(map[string]int{"RUNNING": 1, "STOPPED": 1}, usages[1].InstancesByStatus)
+	require.Equal(42, usages[1].OldestInstanceAgeDays)
+	require.Equal("prod", usages[1].Environment)
+	require.Equal("pt-1", usages[1].PlanID)
+
+	require.Equal(100, usages[2].OldestInstanceAgeDays)
+
+	require.Equal(unknownVersionStatus, usages[3].VersionStatus)
+	require.Equal(1, usages[3].Instances)
+	require.Empty(usages[3].OldestInstanceCreatedAt)
+
+	row := formatVersionUsageRow(usages[1])
+	require.Equal("2.0 (spring)", row.Version)
+	require.Equal("aws: 1, gcp: 1", row.Clouds)
+	require.Equal("us-central1: 1, us-east-1: 1", row.Regions)
+	require.Equal("42d", row.OldestInstanceAge)
+
+	row = formatVersionUsageRow(usages[0])
+	require.Equal("-", row.Clouds)
+	require.Equal("-", row.OldestInstanceAge)
+}
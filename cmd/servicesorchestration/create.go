@@ -12,13 +12,18 @@ import (
 
 const (
 	createExample = `# Create a services orchestration deployment from a DSL file
-omctl services-orchestration create --dsl-file /path/to/dsl.yaml`
+omctl services-orchestration create --dsl-file /path/to/dsl.yaml
+
+# Create a services orchestration deployment from a DSL template, with variables from a file and the command line
+omctl services-orchestration create --dsl-file /path/to/dsl.yaml --values /path/to/prod.yaml --set region=us-east-1`
 )
 
 var createCmd = &cobra.Command{
-	Use:          "create --dsl-file=[file-path]",
-	Short:        "Create a services orchestration deployment",
-	Long:         `This command helps you create a services orchestration deployment, coordinating the creation of multiple services.`,
+	Use:   "create --dsl-file=[file-path]",
+	Short: "Create a services orchestration deployment",
+	Long: `This command helps you create a services orchestration deployment, coordinating the creation of multiple services.
+
+` + dslTemplatingHelp,
 	Example:      createExample,
 	RunE:         runCreate,
 	SilenceUsage: true,
@@ -26,6 +31,7 @@ var createCmd = &cobra.Command{
 
 func init() {
 	createCmd.Flags().String("dsl-file", "", "Yaml file containing DSL for services orchestration deployment")
+	addDslFlags(createCmd)
 
 	if err := createCmd.MarkFlagRequired("dsl-file"); err != nil {
 		return
@@ -49,6 +55,13 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Render and validate the DSL file before anything is sent
+	dslFileContent, err := readDslFile(cmd, dslFilePath)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
 	// Validate user login
 	token, err := common.GetTokenWithLogin()
 	if err != nil {
@@ -66,13 +79,6 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		sm.Start()
 	}
 
-	// Read DSL file
-	dslFileContent, err := readDslFile(dslFilePath)
-	if err != nil {
-		utils.HandleSpinnerError(spinner, sm, err)
-		return err
-	}
-
 	orchestration, err := dataaccess.CreateServicesOrchestration(
 		cmd.Context(),
 		token,
@@ -0,0 +1,396 @@
+package servicesorchestration
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// DSL files are Go templates with ${{ }} delimiters, so that they don't clash with the {{ $var.name }} and
+// {{ $sys.name }} expressions that are resolved by Omnistrate at deployment time.
+const (
+	dslTemplateLeftDelim  = "${{"
+	dslTemplateRightDelim = "}}"
+
+	dslAliasKey     = "instanceDeploymentAlias"
+	dslDependsOnKey = "dependsOnDeployment"
+)
+
+// dslRequiredDeploymentKeys are the fields every deployment must set: the service, its plan and the subscription to
+// deploy with.
+var dslRequiredDeploymentKeys = []string{"serviceId", "productTierId", "subscriptionId"}
+
+const dslTemplatingHelp = `The DSL file can reference variables as ${{ .name }}, set with --values files and --set flags, e.g.
+${{ .region }} or ${{ .db.plan | default "basic" }}. The functions default, required, quote, upper and lower
+are available. Before upload, the DSL is validated locally: it must be well-formed YAML without duplicate keys, and
+every deployment (a mapping with an instanceDeploymentAlias) must have a unique alias, set serviceId, productTierId
+and subscriptionId, and only depend on deployments defined in the same file, without cycles.`
+
+// dslError is a problem found at a position of the rendered DSL.
+type dslError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// dslValidationError lists all problems found in a DSL file, ordered by position.
+type dslValidationError struct {
+	File   string
+	Errors []dslError
+}
+
+func (e *dslValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid services orchestration DSL %s: %d error(s)", e.File, len(e.Errors))
+	for _, dslErr := range e.Errors {
+		fmt.Fprintf(&b, "\n  %s:%d:%d: %s", filepath.Base(e.File), dslErr.Line, dslErr.Column, dslErr.Message)
+	}
+	return b.String()
+}
+
+func addDslFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("set", []string{}, "Set a DSL template variable, e.g. --set region=us-east-1 or --set db.plan=premium. Can be repeated and takes precedence over --values")
+	cmd.Flags().StringArray("values", []string{}, "YAML file with DSL template variables. Can be repeated, later files take precedence")
+	cmd.Flags().Bool("skip-validation", false, "Skip the local validation of the DSL before it is sent")
+
+	_ = cmd.MarkFlagFilename("dsl-file")
+	_ = cmd.MarkFlagFilename("values")
+}
+
+// getDslValues merges the template variables from the --values files and --set flags.
+func getDslValues(cmd *cobra.Command) (map[string]interface{}, error) {
+	valuesFiles, err := cmd.Flags().GetStringArray("values")
+	if err != nil {
+		return nil, err
+	}
+	sets, err := cmd.Flags().GetStringArray("set")
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	for _, valuesFile := range valuesFiles {
+		data, err := os.ReadFile(valuesFile)
+		if err != nil {
+			return nil, err
+		}
+		var fileValues map[string]interface{}
+		if err = yaml.Unmarshal(data, &fileValues); err != nil {
+			return nil, errors.Wrapf(err, "invalid values file %s", valuesFile)
+		}
+		mergeDslValues(values, fileValues)
+	}
+
+	for _, set := range sets {
+		key, value, found := strings.Cut(set, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid --set %q, expected key=value", set)
+		}
+		if err = setDslValue(values, key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// mergeDslValues merges src into dst, merging nested maps key by key.
+func mergeDslValues(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeDslValues(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// setDslValue sets a dotted key, creating the intermediate maps.
+func setDslValue(values map[string]interface{}, key, value string) error {
+	parts := strings.Split(key, ".")
+	current := values
+	for i, part := range parts[:len(parts)-1] {
+		if part == "" {
+			return fmt.Errorf("invalid --set key %q", key)
+		}
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			if _, exists := current[part]; exists {
+				return fmt.Errorf("invalid --set key %q: %s is not a map", key, strings.Join(parts[:i+1], "."))
+			}
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	if parts[len(parts)-1] == "" {
+		return fmt.Errorf("invalid --set key %q", key)
+	}
+	current[parts[len(parts)-1]] = value
+	return nil
+}
+
+// templateErrorPosition matches the "<name>:<line>[:<column>]: " prefix of text/template errors.
+var templateErrorPosition = regexp.MustCompile(`^template: [^:]*:(\d+)(?::(\d+))?: (?:executing "[^"]*" at <[^>]*>: )?`)
+
+// dslUndefinedValue is what text/template prints for a variable that isn't set.
+const dslUndefinedValue = "<no value>"
+
+// renderDsl executes the DSL template with the given variables. Printing an undefined variable is an error.
+func renderDsl(name string, content []byte, values map[string]interface{}) ([]byte, error) {
+	tmpl, err := template.New(filepath.Base(name)).
+		Delims(dslTemplateLeftDelim, dslTemplateRightDelim).
+		Funcs(dslTemplateFuncs).
+		Parse(string(content))
+	if err != nil {
+		return nil, dslTemplateError(name, err)
+	}
+
+	var rendered bytes.Buffer
+	if err = tmpl.Execute(&rendered, values); err != nil {
+		return nil, dslTemplateError(name, err)
+	}
+
+	// Undefined variables render as <no value>, which lets default handle them
+	for i, line := range strings.Split(rendered.String(), "\n") {
+		if column := strings.Index(line, dslUndefinedValue); column >= 0 {
+			return nil, fmt.Errorf("failed to render DSL %s:%d:%d: undefined variable, set it with --set or --values", filepath.Base(name), i+1, column+1)
+		}
+	}
+	return rendered.Bytes(), nil
+}
+
+func dslTemplateError(name string, err error) error {
+	match := templateErrorPosition.FindStringSubmatch(err.Error())
+	if match == nil {
+		return errors.Wrapf(err, "failed to render DSL %s", name)
+	}
+
+	position := match[1]
+	if match[2] != "" {
+		// text/template columns are 0-based
+		column, _ := strconv.Atoi(match[2])
+		position += ":" + strconv.Itoa(column+1)
+	}
+	return fmt.Errorf("failed to render DSL %s:%s: %s", filepath.Base(name), position, strings.TrimPrefix(err.Error(), match[0]))
+}
+
+var dslTemplateFuncs = template.FuncMap{
+	"default": func(defaultValue, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return defaultValue
+		}
+		return value
+	},
+	"required": func(message string, value interface{}) (interface{}, error) {
+		if value == nil || value == "" {
+			return nil, errors.New(message)
+		}
+		return value, nil
+	},
+	"quote": func(value interface{}) string {
+		return fmt.Sprintf("%q", fmt.Sprint(value))
+	},
+	"upper": func(value interface{}) string {
+		return strings.ToUpper(fmt.Sprint(value))
+	},
+	"lower": func(value interface{}) string {
+		return strings.ToLower(fmt.Sprint(value))
+	},
+}
+
+// yamlErrorLine matches the line number in yaml.v3 syntax errors.
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// validateDsl checks the rendered DSL and returns all the problems found, or nil.
+func validateDsl(name string, content []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		dslErr := dslError{Line: 1, Column: 1, Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+		if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+			dslErr.Line, _ = strconv.Atoi(match[1])
+			dslErr.Message = strings.TrimPrefix(err.Error(), match[0])
+		}
+		return &dslValidationError{File: name, Errors: []dslError{dslErr}}
+	}
+
+	var errs []dslError
+	if len(root.Content) == 0 || (root.Content[0].Kind != yaml.MappingNode && root.Content[0].Kind != yaml.SequenceNode) {
+		errs = append(errs, dslError{Line: max(root.Line, 1), Column: max(root.Column, 1), Message: "the DSL must be a YAML mapping or list"})
+		return &dslValidationError{File: name, Errors: errs}
+	}
+
+	var deployments []*yaml.Node
+	walkDslNodes(root.Content[0], func(node *yaml.Node) {
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if seen[key.Value] {
+				errs = append(errs, dslError{Line: key.Line, Column: key.Column, Message: fmt.Sprintf("duplicate key %s", key.Value)})
+			}
+			seen[key.Value] = true
+			if key.Value == dslAliasKey {
+				deployments = append(deployments, node)
+			}
+		}
+	})
+
+	if len(deployments) == 0 {
+		errs = append(errs, dslError{Line: root.Content[0].Line, Column: root.Content[0].Column, Message: fmt.Sprintf("no deployments found, each deployment must set %s", dslAliasKey)})
+	}
+
+	errs = append(errs, validateDslDeployments(deployments)...)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return &dslValidationError{File: name, Errors: errs}
+}
+
+func walkDslNodes(node *yaml.Node, visit func(*yaml.Node)) {
+	visit(node)
+	for _, child := range node.Content {
+		walkDslNodes(child, visit)
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// validateDslDeployments checks the aliases and required fields of the deployments and the dependencies between them.
+func validateDslDeployments(deployments []*yaml.Node) (errs []dslError) {
+	aliases := make(map[string]*yaml.Node)
+	for _, deployment := range deployments {
+		alias := mappingValue(deployment, dslAliasKey)
+		name := "deployment"
+		if alias.Kind == yaml.ScalarNode && strings.TrimSpace(alias.Value) != "" {
+			name += " " + alias.Value
+		}
+		for _, key := range dslRequiredDeploymentKeys {
+			value := mappingValue(deployment, key)
+			switch {
+			case value == nil:
+				errs = append(errs, dslError{Line: deployment.Line, Column: deployment.Column, Message: fmt.Sprintf("%s must set %s", name, key)})
+			case value.Kind != yaml.ScalarNode || strings.TrimSpace(value.Value) == "":
+				errs = append(errs, dslError{Line: value.Line, Column: value.Column, Message: fmt.Sprintf("%s must be a non-empty string", key)})
+			}
+		}
+		if alias.Kind != yaml.ScalarNode || strings.TrimSpace(alias.Value) == "" {
+			errs = append(errs, dslError{Line: alias.Line, Column: alias.Column, Message: fmt.Sprintf("%s must be a non-empty string", dslAliasKey)})
+			continue
+		}
+		if previous, ok := aliases[alias.Value]; ok {
+			errs = append(errs, dslError{Line: alias.Line, Column: alias.Column, Message: fmt.Sprintf("duplicate %s %s, already defined on line %d", dslAliasKey, alias.Value, previous.Line)})
+			continue
+		}
+		aliases[alias.Value] = alias
+	}
+
+	dependencies := make(map[string][]string)
+	for _, deployment := range deployments {
+		alias := mappingValue(deployment, dslAliasKey)
+		dependsOn := mappingValue(deployment, dslDependsOnKey)
+		if dependsOn == nil || alias.Kind != yaml.ScalarNode {
+			continue
+		}
+		if dependsOn.Kind != yaml.SequenceNode {
+			errs = append(errs, dslError{Line: dependsOn.Line, Column: dependsOn.Column, Message: fmt.Sprintf("%s must be a list of %s values", dslDependsOnKey, dslAliasKey)})
+			continue
+		}
+		for _, dependency := range dependsOn.Content {
+			switch {
+			case dependency.Kind != yaml.ScalarNode:
+				errs = append(errs, dslError{Line: dependency.Line, Column: dependency.Column, Message: fmt.Sprintf("%s must be a list of %s values", dslDependsOnKey, dslAliasKey)})
+			case dependency.Value == alias.Value:
+				errs = append(errs, dslError{Line: dependency.Line, Column: dependency.Column, Message: fmt.Sprintf("deployment %s depends on itself", alias.Value)})
+			case aliases[dependency.Value] == nil:
+				errs = append(errs, dslError{Line: dependency.Line, Column: dependency.Column, Message: fmt.Sprintf("deployment %s depends on unknown deployment %s", alias.Value, dependency.Value)})
+			default:
+				dependencies[alias.Value] = append(dependencies[alias.Value], dependency.Value)
+			}
+		}
+	}
+
+	if cycle := findDependencyCycle(dependencies); cycle != nil {
+		start := aliases[cycle[0]]
+		errs = append(errs, dslError{Line: start.Line, Column: start.Column, Message: fmt.Sprintf("dependency cycle: %s", strings.Join(cycle, " -> "))})
+	}
+
+	return errs
+}
+
+// findDependencyCycle returns the aliases of a dependency cycle, starting and ending with the same alias, or nil.
+func findDependencyCycle(dependencies map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var stack []string
+
+	var visit func(alias string) []string
+	visit = func(alias string) []string {
+		state[alias] = visiting
+		stack = append(stack, alias)
+		for _, dependency := range dependencies[alias] {
+			switch state[dependency] {
+			case visiting:
+				for i, a := range stack {
+					if a == dependency {
+						return append(append([]string{}, stack[i:]...), dependency)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[alias] = visited
+		return nil
+	}
+
+	aliases := make([]string, 0, len(dependencies))
+	for alias := range dependencies {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		if state[alias] == unvisited {
+			if cycle := visit(alias); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package servicesorchestration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+const testDsl = `deployments:
+  - instanceDeploymentAlias: db
+    serviceId: ${{ .db.service }}
+    productTierId: pt-db
+    subscriptionId: sub-db
+    params:
+      region: ${{ .region | default "us-east-1" }}
+      password: "{{ $var.password }}"
+  - instanceDeploymentAlias: app
+    serviceId: s-456
+    productTierId: pt-app
+    subscriptionId: sub-app
+    dependsOnDeployment: [db]
+    params:
+      plan: ${{ .plan | upper }}
+`
+
+func TestRenderDsl(t *testing.T) {
+	require := require.New(t)
+
+	rendered, err := renderDsl("dsl.yaml", []byte(testDsl), map[string]interface{}{
+		"db":   map[string]interface{}{"service": "s-123"},
+		"plan": "premium",
+	})
+	require.NoError(err)
+	require.Equal(`deployments:
+  - instanceDeploymentAlias: db
+    serviceId: s-123
+    productTierId: pt-db
+    subscriptionId: sub-db
+    params:
+      region: us-east-1
+      password: "{{ $var.password }}"
+  - instanceDeploymentAlias: app
+    serviceId: s-456
+    productTierId: pt-app
+    subscriptionId: sub-app
+    dependsOnDeployment: [db]
+    params:
+      plan: PREMIUM
+`, string(rendered))
+	require.NoError(validateDsl("dsl.yaml", rendered))
+
+	_, err = renderDsl("dsl.yaml", []byte(testDsl), map[string]interface{}{"db": map[string]interface{}{}})
+	require.EqualError(err, "failed to render DSL dsl.yaml:3:16: undefined variable, set it with --set or --values")
+
+	_, err = renderDsl("/tmp/dsl.yaml", []byte("a: b\nc: ${{ .c | nofunc }}\n"), nil)
+	require.EqualError(err, `failed to render DSL dsl.yaml:2: function "nofunc" not defined`)
+
+	_, err = renderDsl("dsl.yaml", []byte("a: b\nc: ${{ required \"c is required\" .c }}\n"), nil)
+	require.EqualError(err, "failed to render DSL dsl.yaml:2:8: error calling required: c is required")
+}
+
+func TestValidateDsl(t *testing.T) {
+	tests := []struct {
+		name           string
+		dsl            string
+		expectedErrors []dslError
+	}{
+		{
+			name:           "Syntax error",
+			dsl:            "deployments:\n\t- instanceDeploymentAlias: db\n",
+			expectedErrors: []dslError{{Line: 2, Column: 1, Message: "found character that cannot start any token"}},
+		},
+		{
+			name:           "Scalar",
+			dsl:            "just a string",
+			expectedErrors: []dslError{{Line: 1, Column: 1, Message: "the DSL must be a YAML mapping or list"}},
+		},
+		{
+			name:           "No deployments",
+			dsl:            "deployments: []",
+			expectedErrors: []dslError{{Line: 1, Column: 1, Message: "no deployments found, each deployment must set instanceDeploymentAlias"}},
+		},
+		{
+			name: "All errors are reported",
+			dsl: `- instanceDeploymentAlias: db
+  dependsOnDeployment: [app]
+  params: {a: 1, a: 2}
+  serviceId: s-123
+  productTierId: pt-123
+  subscriptionId: sub-123
+- instanceDeploymentAlias: app
+  dependsOnDeployment: [db, cache, app]
+  serviceId: s-123
+  productTierId: pt-123
+  subscriptionId: sub-123
+- instanceDeploymentAlias: db
+  serviceId: s-123
+  productTierId: pt-123
+  subscriptionId: sub-123
+- instanceDeploymentAlias: ""
+  serviceId: s-123
+  productTierId: pt-123
+  subscriptionId: sub-123
+- instanceDeploymentAlias: web
+  dependsOnDeployment: app
+  serviceId: s-123
+  productTierId: pt-123
+  subscriptionId: sub-123
+`,
+			expectedErrors: []dslError{
+				{Line: 3, Column: 18, Message: "duplicate key a"},
+				{Line: 7, Column: 28, Message: "dependency cycle: app -> db -> app"},
+				{Line: 8, Column: 29, Message: "deployment app depends on unknown deployment cache"},
+				{Line: 8, Column: 36, Message: "deployment app depends on itself"},
+				{Line: 12, Column: 28, Message: "duplicate instanceDeploymentAlias db, already defined on line 1"},
+				{Line: 16, Column: 28, Message: "instanceDeploymentAlias must be a non-empty string"},
+				{Line: 21, Column: 24, Message: "dependsOnDeployment must be a list of instanceDeploymentAlias values"},
+			},
+		},
+		{
+			name: "Missing required fields",
+			dsl: `deployments:
+  - instanceDeploymentAlias: db
+    serviceId: s-123
+    subscriptionId: ""
+  - instanceDeploymentAlias: app
+    serviceId: [s-456]
+    productTierId: pt-456
+    subscriptionId: sub-456
+`,
+			expectedErrors: []dslError{
+				{Line: 2, Column: 5, Message: "deployment db must set productTierId"},
+				{Line: 4, Column: 21, Message: "subscriptionId must be a non-empty string"},
+				{Line: 6, Column: 16, Message: "serviceId must be a non-empty string"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateDsl("dsl.yaml", []byte(test.dsl))
+			require.Error(t, err)
+
+			var validationErr *dslValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Equal(t, test.expectedErrors, validationErr.Errors)
+		})
+	}
+}
+
+func TestGetDslValues(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	prod := filepath.Join(dir, "prod.yaml")
+	require.NoError(os.WriteFile(base, []byte("region: us-east-1\ndb:\n  plan: basic\n  size: small\n"), 0600))
+	require.NoError(os.WriteFile(prod, []byte("db:\n  plan: premium\n"), 0600))
+
+	cmd := &cobra.Command{}
+	addDslFlags(cmd)
+	require.NoError(cmd.ParseFlags([]string{"--values", base, "--values", prod, "--set", "db.size=large", "--set", "owner=team=ops"}))
+
+	values, err := getDslValues(cmd)
+	require.NoError(err)
+	require.Equal(map[string]interface{}{
+		"region": "us-east-1",
+		"owner":  "team=ops",
+		"db":     map[string]interface{}{"plan": "premium", "size": "large"},
+	}, values)
+
+	require.NoError(cmd.ParseFlags([]string{"--set", "region.name=x"}))
+	_, err = getDslValues(cmd)
+	require.EqualError(err, `invalid --set key "region.name": region is not a map`)
+}
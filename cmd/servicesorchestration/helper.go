@@ -5,11 +5,24 @@ import (
 	"os"
 
 	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
 )
 
 // Helper functions
 
-func readDslFile(filePath string) (base64FileContent string, err error) {
+func readDslFile(cmd *cobra.Command, filePath string) (base64FileContent string, err error) {
+	var fileContent []byte
+	fileContent, err = renderDslFile(cmd, filePath)
+	if err != nil {
+		return
+	}
+	// return base64 encoded file content
+	base64FileContent = base64.StdEncoding.EncodeToString(fileContent)
+	return
+}
+
+// renderDslFile renders the DSL file with the template variables of the command flags and validates the result.
+func renderDslFile(cmd *cobra.Command, filePath string) (rendered []byte, err error) {
 	// Read parameters from file if provided
 	if filePath == "" {
 		err = errors.New("dsl file path is empty")
@@ -20,7 +33,21 @@ func readDslFile(filePath string) (base64FileContent string, err error) {
 	if err != nil {
 		return
 	}
-	// return base64 encoded file content
-	base64FileContent = base64.StdEncoding.EncodeToString(fileContent)
+
+	values, err := getDslValues(cmd)
+	if err != nil {
+		return
+	}
+	if rendered, err = renderDsl(filePath, fileContent, values); err != nil {
+		return
+	}
+
+	skipValidation, err := cmd.Flags().GetBool("skip-validation")
+	if err != nil {
+		return
+	}
+	if !skipValidation {
+		err = validateDsl(filePath, rendered)
+	}
 	return
 }
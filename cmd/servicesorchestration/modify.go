@@ -13,13 +13,18 @@ import (
 
 const (
 	modifyExample = `# Modify a services orchestration deployment from a DSL file
-omctl services-orchestration modify so-abcd1234 --dsl-file /path/to/dsl.yaml`
+omctl services-orchestration modify so-abcd1234 --dsl-file /path/to/dsl.yaml
+
+# Modify a services orchestration deployment from a DSL template
+omctl services-orchestration modify so-abcd1234 --dsl-file /path/to/dsl.yaml --values /path/to/prod.yaml`
 )
 
 var modifyCmd = &cobra.Command{
-	Use:          "modify [so-id] -dsl-file=[file-path]",
-	Short:        "Modify a services orchestration deployment",
-	Long:         `This command helps you modify a services orchestration deployment, coordinating the modification of multiple services.`,
+	Use:   "modify [so-id] -dsl-file=[file-path]",
+	Short: "Modify a services orchestration deployment",
+	Long: `This command helps you modify a services orchestration deployment, coordinating the modification of multiple services.
+
+` + dslTemplatingHelp,
 	Example:      modifyExample,
 	RunE:         runModify,
 	SilenceUsage: true,
@@ -29,6 +34,7 @@ func init() {
 	describeCmd.Args = cobra.ExactArgs(1) // Require exactly one argument
 
 	modifyCmd.Flags().String("dsl-file", "", "Yaml file containing DSL for services orchestration deployment")
+	addDslFlags(modifyCmd)
 
 	if err := modifyCmd.MarkFlagRequired("dsl-file"); err != nil {
 		return
@@ -59,6 +65,13 @@ func runModify(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Render and validate the DSL file before anything is sent
+	dslFileContent, err := readDslFile(cmd, dslFilePath)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
 	// Validate user login
 	token, err := common.GetTokenWithLogin()
 	if err != nil {
@@ -76,13 +89,6 @@ func runModify(cmd *cobra.Command, args []string) error {
 		sm.Start()
 	}
 
-	// Read DSL file
-	dslFileContent, err := readDslFile(dslFilePath)
-	if err != nil {
-		utils.HandleSpinnerError(spinner, sm, err)
-		return err
-	}
-
 	err = dataaccess.ModifyServicesOrchestration(
 		cmd.Context(),
 		token,
@@ -0,0 +1,59 @@
+package servicesorchestration
+
+import (
+	"fmt"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	renderExample = `# Print the DSL that would be sent for production
+omctl services-orchestration render --dsl-file /path/to/dsl.yaml --values /path/to/prod.yaml
+
+# Check a DSL template with a variable set on the command line
+omctl services-orchestration render --dsl-file /path/to/dsl.yaml --set region=us-east-1 > /dev/null`
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render --dsl-file=[file-path]",
+	Short: "Render and validate a services orchestration DSL file",
+	Long: `This command renders a services orchestration DSL file with its template variables and prints the DSL that create and modify would send, after validating it locally.
+
+` + dslTemplatingHelp,
+	Example:      renderExample,
+	RunE:         runRender,
+	SilenceUsage: true,
+}
+
+func init() {
+	renderCmd.Flags().String("dsl-file", "", "Yaml file containing DSL for services orchestration deployment")
+	addDslFlags(renderCmd)
+
+	if err := renderCmd.MarkFlagRequired("dsl-file"); err != nil {
+		return
+	}
+
+	renderCmd.Args = cobra.NoArgs // Require no arguments
+}
+
+func runRender(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve flags
+	dslFilePath, err := cmd.Flags().GetString("dsl-file")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	rendered, err := renderDslFile(cmd, dslFilePath)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	fmt.Print(string(rendered))
+	return nil
+}
@@ -18,6 +18,8 @@ func init() {
 	Cmd.AddCommand(deleteCmd)
 	Cmd.AddCommand(listCmd)
 	Cmd.AddCommand(modifyCmd)
+	Cmd.AddCommand(renderCmd)
+	Cmd.AddCommand(statusCmd)
 }
 
 func run(cmd *cobra.Command, args []string) {
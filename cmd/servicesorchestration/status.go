@@ -0,0 +1,380 @@
+package servicesorchestration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	statusExample = `# Show the status of a services orchestration deployment and its instances
+omctl services-orchestration status so-abcd1234
+
+# Wait until all instances are deployed, printing the tree whenever it changes
+omctl services-orchestration status so-abcd1234 --wait --timeout 1h`
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status [so-id] [--wait]",
+	Short: "Show the deployment status of a services orchestration and its instances",
+	Long: `This command shows a services orchestration deployment as a tree of its instance deployments and their statuses,
+with the 'omctl instance debug' command to troubleshoot each instance.
+
+With --wait, the status is polled until no instance deployment is pending, and the tree is printed again whenever it
+changes. The command exits with an error if the orchestration or any of its instance deployments failed.`,
+	Example:      statusExample,
+	RunE:         runStatus,
+	SilenceUsage: true,
+}
+
+// Deployment states derived from the orchestration and instance statuses.
+const (
+	deploymentStatePending = "pending"
+	deploymentStateReady   = "ready"
+	deploymentStateFailed  = "failed"
+)
+
+var (
+	readyDeploymentStatuses  = []string{"RUNNING", "READY", "DEPLOYED", "COMPLETE", "COMPLETED", "SUCCESS", "SUCCEEDED"}
+	failedDeploymentStatuses = []string{"FAILED", "ERROR", "CANCELLED"}
+)
+
+// orchestrationStatus is a snapshot of a services orchestration and its instance deployments.
+type orchestrationStatus struct {
+	ID           string           `json:"id"`
+	Status       string           `json:"status"`
+	State        string           `json:"state"`
+	FailedReason string           `json:"failedReason,omitempty"`
+	UpdatedAt    string           `json:"updatedAt"`
+	Instances    []instanceStatus `json:"instances"`
+}
+
+// instanceStatus is the status of one instance deployment of a services orchestration.
+type instanceStatus struct {
+	Alias          string   `json:"instanceDeploymentAlias"`
+	InstanceID     string   `json:"instanceId,omitempty"`
+	ServiceID      string   `json:"serviceId"`
+	SubscriptionID string   `json:"subscriptionId"`
+	Status         string   `json:"status"`
+	State          string   `json:"state"`
+	Message        string   `json:"statusMessage,omitempty"`
+	FailedReason   string   `json:"failedReason,omitempty"`
+	DependsOn      []string `json:"dependsOnDeployment,omitempty"`
+	DebugCommand   string   `json:"debugCommand,omitempty"`
+}
+
+func init() {
+	statusCmd.Args = cobra.ExactArgs(1) // Require exactly one argument
+	statusCmd.Flags().Bool("wait", false, "Wait until no instance deployment is pending")
+	statusCmd.Flags().Duration("interval", 10*time.Second, "Polling interval with --wait")
+	statusCmd.Flags().Duration("timeout", time.Hour, "Maximum time to wait with --wait")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve args
+	soID := args[0]
+
+	// Retrieve flags
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	wait, err := cmd.Flags().GetBool("wait")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	interval, err := cmd.Flags().GetDuration("interval")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	if interval <= 0 {
+		err = errors.New("--interval must be positive")
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	describe := func(ctx context.Context) (*orchestrationStatus, error) {
+		res, err := dataaccess.DescribeServicesOrchestration(ctx, token, soID)
+		if err != nil {
+			return nil, err
+		}
+		return newOrchestrationStatus(res), nil
+	}
+
+	var out io.Writer = os.Stdout
+	if output == "json" {
+		// Only the final snapshot is printed as JSON
+		out = io.Discard
+	}
+
+	ctx := cmd.Context()
+	if wait {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	status, err := watchOrchestrationStatus(ctx, describe, wait, interval, out)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s waiting for services orchestration %s", timeout, soID)
+		}
+		utils.PrintError(err)
+		return err
+	}
+
+	if output == "json" {
+		if err = utils.PrintTextTableJsonOutput(output, status); err != nil {
+			utils.PrintError(err)
+			return err
+		}
+	}
+
+	if failed := status.failedDeployments(); len(failed) > 0 {
+		err = fmt.Errorf("services orchestration %s has failed deployments: %s", soID, strings.Join(failed, ", "))
+		utils.PrintError(err)
+		return err
+	}
+
+	return nil
+}
+
+// watchOrchestrationStatus prints the status tree, and with wait, polls until nothing is pending, printing the tree
+// again whenever it changes.
+func watchOrchestrationStatus(
+	ctx context.Context,
+	describe func(context.Context) (*orchestrationStatus, error),
+	wait bool,
+	interval time.Duration,
+	out io.Writer,
+) (*orchestrationStatus, error) {
+	var previous string
+	for {
+		status, err := describe(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tree := renderOrchestrationTree(status)
+		if tree != previous {
+			if wait {
+				_, _ = fmt.Fprintf(out, "[%s]\n", time.Now().Format(time.TimeOnly))
+			}
+			_, _ = fmt.Fprintln(out, tree)
+			previous = tree
+		}
+
+		if !wait || !status.isPending() {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func newOrchestrationStatus(res *openapiclientfleet.FleetDescribeServicesOrchestrationResult) *orchestrationStatus {
+	orchestration := res.GetAccessServicesOrchestration()
+	status := &orchestrationStatus{
+		ID:           orchestration.GetId(),
+		Status:       orchestration.GetStatus(),
+		FailedReason: orchestration.GetOrchestrationFailedReason(),
+		UpdatedAt:    orchestration.GetUpdatedAt(),
+	}
+	status.State = deploymentState(status.Status, status.FailedReason)
+
+	for _, deployment := range orchestration.GetServicesTopology() {
+		instance := instanceStatus{
+			Alias:          deployment.GetInstanceDeploymentAlias(),
+			InstanceID:     deployment.GetInstanceId(),
+			ServiceID:      deployment.GetServiceId(),
+			SubscriptionID: deployment.GetSubscriptionId(),
+			Status:         deployment.GetStatus(),
+			Message:        deployment.GetStatusMessage(),
+			FailedReason:   deployment.GetFailedReason(),
+			DependsOn:      deployment.GetDependsOnDeployment(),
+		}
+		instance.State = deploymentState(instance.Status, instance.FailedReason)
+		if instance.InstanceID != "" {
+			instance.DebugCommand = "omctl instance debug " + instance.InstanceID
+		}
+		status.Instances = append(status.Instances, instance)
+	}
+	status.Instances = sortInstancesByDependencies(status.Instances)
+
+	return status
+}
+
+func deploymentState(status, failedReason string) string {
+	status = strings.ToUpper(status)
+	switch {
+	case slices.Contains(failedDeploymentStatuses, status):
+		return deploymentStateFailed
+	case slices.Contains(readyDeploymentStatuses, status):
+		return deploymentStateReady
+	case failedReason != "":
+		return deploymentStateFailed
+	default:
+		return deploymentStatePending
+	}
+}
+
+// isPending reports whether the orchestration is still deploying. It stops once every instance deployment is ready
+// or failed, or the orchestration itself failed.
+func (s *orchestrationStatus) isPending() bool {
+	if s.State == deploymentStateFailed {
+		return false
+	}
+	if len(s.Instances) == 0 {
+		return s.State == deploymentStatePending
+	}
+	for _, instance := range s.Instances {
+		if instance.State == deploymentStatePending {
+			return true
+		}
+	}
+	return false
+}
+
+// failedDeployments returns the aliases of the failed instance deployments, and the orchestration ID if it failed.
+func (s *orchestrationStatus) failedDeployments() (failed []string) {
+	for _, instance := range s.Instances {
+		if instance.State == deploymentStateFailed {
+			failed = append(failed, instance.Alias)
+		}
+	}
+	if len(failed) == 0 && s.State == deploymentStateFailed {
+		failed = append(failed, s.ID)
+	}
+	return
+}
+
+// sortInstancesByDependencies orders the instances so that each one comes after its dependencies, and by alias
+// otherwise. Instances in a dependency cycle keep alias order at the end.
+func sortInstancesByDependencies(instances []instanceStatus) []instanceStatus {
+	sort.SliceStable(instances, func(i, j int) bool {
+		return instances[i].Alias < instances[j].Alias
+	})
+
+	placed := make(map[string]bool)
+	known := make(map[string]bool)
+	for _, instance := range instances {
+		known[instance.Alias] = true
+	}
+
+	sorted := make([]instanceStatus, 0, len(instances))
+	for len(sorted) < len(instances) {
+		progress := false
+		for _, instance := range instances {
+			if placed[instance.Alias] {
+				continue
+			}
+			ready := true
+			for _, dependency := range instance.DependsOn {
+				if known[dependency] && !placed[dependency] && dependency != instance.Alias {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				sorted = append(sorted, instance)
+				placed[instance.Alias] = true
+				progress = true
+			}
+		}
+		if !progress {
+			for _, instance := range instances {
+				if !placed[instance.Alias] {
+					sorted = append(sorted, instance)
+					placed[instance.Alias] = true
+				}
+			}
+		}
+	}
+	return sorted
+}
+
+var deploymentStateSymbols = map[string]string{
+	deploymentStatePending: "…",
+	deploymentStateReady:   "✔",
+	deploymentStateFailed:  "✖",
+}
+
+// renderOrchestrationTree renders the orchestration and its instance deployments as a tree.
+func renderOrchestrationTree(s *orchestrationStatus) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s", deploymentStateSymbols[s.State], s.ID, s.Status)
+	if s.FailedReason != "" {
+		fmt.Fprintf(&b, ": %s", s.FailedReason)
+	}
+
+	aliasWidth, statusWidth := 0, 0
+	for _, instance := range s.Instances {
+		aliasWidth = max(aliasWidth, len(instance.Alias))
+		statusWidth = max(statusWidth, len(instance.Status))
+	}
+
+	for i, instance := range s.Instances {
+		branch, indent := "├──", "│   "
+		if i == len(s.Instances)-1 {
+			branch, indent = "└──", "    "
+		}
+
+		instanceID := instance.InstanceID
+		if instanceID == "" {
+			instanceID = "-"
+		}
+		fmt.Fprintf(&b, "\n%s %s %-*s  %-*s  %s", branch, deploymentStateSymbols[instance.State], aliasWidth, instance.Alias, statusWidth, instance.Status, instanceID)
+
+		var details []string
+		if instance.FailedReason != "" {
+			details = append(details, instance.FailedReason)
+		} else if instance.Message != "" {
+			details = append(details, instance.Message)
+		}
+		if len(instance.DependsOn) > 0 {
+			details = append(details, "depends on "+strings.Join(instance.DependsOn, ", "))
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(&b, "  (%s)", strings.Join(details, "; "))
+		}
+		if instance.DebugCommand != "" {
+			fmt.Fprintf(&b, "\n%s  debug: %s", indent, instance.DebugCommand)
+		}
+	}
+
+	return b.String()
+}
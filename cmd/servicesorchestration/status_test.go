@@ -0,0 +1,117 @@
+package servicesorchestration
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDeployment(alias, instanceID, status string, dependsOn ...string) openapiclientfleet.ServiceDeploymentDetails {
+	deployment := openapiclientfleet.NewServiceDeploymentDetails(alias, "s-123", "sub-123")
+	if instanceID != "" {
+		deployment.SetInstanceId(instanceID)
+	}
+	deployment.SetStatus(status)
+	if len(dependsOn) > 0 {
+		deployment.SetDependsOnDeployment(dependsOn)
+	}
+	return *deployment
+}
+
+func newTestOrchestration(status string, deployments ...openapiclientfleet.ServiceDeploymentDetails) *openapiclientfleet.FleetDescribeServicesOrchestrationResult {
+	orchestration := openapiclientfleet.NewDescribeServicesOrchestrationResult("2026-01-01T00:00:00Z", "so-123", status, "2026-01-01T00:10:00Z")
+	orchestration.SetServicesTopology(deployments)
+	res := openapiclientfleet.NewFleetDescribeServicesOrchestrationResult()
+	res.SetAccessServicesOrchestration(*orchestration)
+	return res
+}
+
+func TestOrchestrationStatusTree(t *testing.T) {
+	require := require.New(t)
+
+	failed := newTestDeployment("cache", "instance-2", "FAILED")
+	failed.SetFailedReason("Invalid inputs")
+	status := newOrchestrationStatus(newTestOrchestration("DEPLOYING",
+		newTestDeployment("app", "", "PENDING", "db", "cache"),
+		failed,
+		newTestDeployment("db", "instance-1", "RUNNING"),
+	))
+
+	require.True(status.isPending())
+	require.Equal([]string{"cache"}, status.failedDeployments())
+	require.Equal(`… so-123 DEPLOYING
+├── ✖ cache  FAILED   instance-2  (Invalid inputs)
+│     debug: omctl instance debug instance-2
+├── ✔ db     RUNNING  instance-1
+│     debug: omctl instance debug instance-1
+└── … app    PENDING  -  (depends on db, cache)`, renderOrchestrationTree(status))
+}
+
+func TestSortInstancesByDependencies(t *testing.T) {
+	sorted := sortInstancesByDependencies([]instanceStatus{
+		{Alias: "web", DependsOn: []string{"api"}},
+		{Alias: "api", DependsOn: []string{"db", "queue"}},
+		{Alias: "queue"},
+		{Alias: "db", DependsOn: []string{"unknown"}},
+		{Alias: "x", DependsOn: []string{"y"}},
+		{Alias: "y", DependsOn: []string{"x"}},
+	})
+
+	var aliases []string
+	for _, instance := range sorted {
+		aliases = append(aliases, instance.Alias)
+	}
+	require.Equal(t, []string{"db", "queue", "api", "web", "x", "y"}, aliases)
+}
+
+func TestWatchOrchestrationStatus(t *testing.T) {
+	require := require.New(t)
+
+	snapshots := []*openapiclientfleet.FleetDescribeServicesOrchestrationResult{
+		newTestOrchestration("DEPLOYING", newTestDeployment("db", "", "PENDING")),
+		newTestOrchestration("DEPLOYING", newTestDeployment("db", "", "PENDING")),
+		newTestOrchestration("DEPLOYING", newTestDeployment("db", "instance-1", "DEPLOYING")),
+		newTestOrchestration("COMPLETED", newTestDeployment("db", "instance-1", "RUNNING")),
+	}
+	calls := 0
+	describe := func(context.Context) (*orchestrationStatus, error) {
+		status := newOrchestrationStatus(snapshots[calls])
+		calls++
+		return status, nil
+	}
+
+	var out bytes.Buffer
+	status, err := watchOrchestrationStatus(context.Background(), describe, true, time.Millisecond, &out)
+	require.NoError(err)
+	require.Equal(4, calls)
+	require.False(status.isPending())
+	require.Empty(status.failedDeployments())
+	require.Equal(3, strings.Count(out.String(), "so-123"), "unchanged snapshots are not printed again")
+
+	calls = 0
+	out.Reset()
+	status, err = watchOrchestrationStatus(context.Background(), describe, false, time.Millisecond, &out)
+	require.NoError(err)
+	require.Equal(1, calls)
+	require.True(status.isPending())
+	require.Equal("… so-123 DEPLOYING\n└── … db  PENDING  -\n", out.String())
+}
+
+func TestOrchestrationFailure(t *testing.T) {
+	require := require.New(t)
+
+	res := newTestOrchestration("FAILED")
+	orchestration := res.GetAccessServicesOrchestration()
+	orchestration.SetOrchestrationFailedReason("Invalid DSL")
+	res.SetAccessServicesOrchestration(orchestration)
+
+	status := newOrchestrationStatus(res)
+	require.False(status.isPending())
+	require.Equal([]string{"so-123"}, status.failedDeployments())
+	require.Equal("✖ so-123 FAILED: Invalid DSL", renderOrchestrationTree(status))
+}
@@ -0,0 +1,205 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	createExample = `# Create a subscription to the premium plan on behalf of a customer
+omctl subscription create --customer-email user@example.com --plan premium
+
+# Select the plan by service and environment when several plans share the name
+omctl subscription create --customer-email user@example.com --service postgres --environment prod --plan premium`
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create --customer-email=[email] --plan=[plan] [flags]",
+	Short: "Create a Customer Subscription on behalf of a customer",
+	Long: `This command helps you create a Customer Subscription to a service plan on behalf of a customer.
+The customer must already have signed up to your service. The plan can be given by name or ID, and the service
+and environment flags select the plan when several plans have the same name.`,
+	Example:      createExample,
+	RunE:         runCreate,
+	SilenceUsage: true,
+}
+
+func init() {
+	createCmd.Flags().String("customer-email", "", "Email of the customer to create the subscription for")
+	createCmd.Flags().String("plan", "", "Service plan name or ID")
+	createCmd.Flags().String("service", "", "Service name or ID")
+	createCmd.Flags().String("environment", "", "Environment name or ID")
+
+	if err := createCmd.MarkFlagRequired("customer-email"); err != nil {
+		return
+	}
+	if err := createCmd.MarkFlagRequired("plan"); err != nil {
+		return
+	}
+}
+
+func runCreate(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve flags
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	customerEmail, err := cmd.Flags().GetString("customer-email")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	plan, err := cmd.Flags().GetString("plan")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	service, err := cmd.Flags().GetString("service")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	environment, err := cmd.Flags().GetString("environment")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not JSON
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		msg := "Creating subscription..."
+		spinner = sm.AddSpinner(msg)
+		sm.Start()
+	}
+
+	servicePlan, err := findServicePlan(cmd.Context(), token, service, environment, plan)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	// Check the customer doesn't already have a subscription to the plan
+	searchRes, err := dataaccess.SearchInventory(cmd.Context(), token, "subscription:s")
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+	for _, subscription := range searchRes.SubscriptionResults {
+		if subscription.ProductTierID == servicePlan.Id &&
+			subscription.ServiceEnvironmentID == servicePlan.ServiceEnvironmentId &&
+			strings.EqualFold(subscription.RootUserEmail, customerEmail) {
+			err = fmt.Errorf("%s already has subscription %s to plan %s", customerEmail, subscription.Id, servicePlan.Name)
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+	}
+
+	userID, err := dataaccess.GetUserIDByEmail(cmd.Context(), token, servicePlan.ServiceId, customerEmail)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	subscriptionID, err := dataaccess.CreateSubscriptionOnBehalfOfCustomer(cmd.Context(), token, servicePlan.ServiceId, servicePlan.ServiceEnvironmentId, servicePlan.Id, userID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	// The inventory search may not return the new subscription yet, describe it from the plan it was created for then
+	subscription, err := getSubscription(cmd.Context(), token, subscriptionID)
+	if err != nil {
+		subscription = &openapiclientfleet.SubscriptionSearchRecord{
+			Id:                     subscriptionID,
+			ServiceID:              servicePlan.ServiceId,
+			ServiceName:            servicePlan.ServiceName,
+			ProductTierID:          servicePlan.Id,
+			ServicePlanName:        servicePlan.Name,
+			ServiceEnvironmentID:   servicePlan.ServiceEnvironmentId,
+			ServiceEnvironmentName: servicePlan.ServiceEnvironmentName,
+			RootUserID:             userID,
+			RootUserEmail:          customerEmail,
+		}
+	}
+
+	utils.HandleSpinnerSuccess(spinner, sm, "Successfully created subscription")
+
+	// Print output
+	err = utils.PrintTextTableJsonOutput(output, formatSubscription(subscription, false))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	return nil
+}
+
+// findServicePlan returns the service plan with the name or ID, in the service and environment if they are set.
+func findServicePlan(ctx context.Context, token, service, environment, plan string) (*openapiclientfleet.ServicePlanSearchRecord, error) {
+	searchRes, err := dataaccess.SearchInventory(ctx, token, "serviceplan:p")
+	if err != nil {
+		return nil, err
+	}
+
+	matches := func(value string, candidates ...string) bool {
+		if value == "" {
+			return true
+		}
+		for _, candidate := range candidates {
+			if strings.EqualFold(value, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var found []openapiclientfleet.ServicePlanSearchRecord
+	seen := make(map[string]bool)
+	for _, servicePlan := range searchRes.ServicePlanResults {
+		key := servicePlan.Id + "/" + servicePlan.ServiceEnvironmentId
+		if seen[key] ||
+			!matches(plan, servicePlan.Id, servicePlan.Name) ||
+			!matches(service, servicePlan.ServiceId, servicePlan.ServiceName) ||
+			!matches(environment, servicePlan.ServiceEnvironmentId, servicePlan.ServiceEnvironmentName) {
+			continue
+		}
+		seen[key] = true
+		found = append(found, servicePlan)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("service plan %s not found. Please check the plan, service and environment and try again", plan)
+	case 1:
+		return &found[0], nil
+	default:
+		candidates := make([]string, 0, len(found))
+		for _, servicePlan := range found {
+			candidates = append(candidates, fmt.Sprintf("%s (%s) in %s/%s", servicePlan.Name, servicePlan.Id, servicePlan.ServiceName, servicePlan.ServiceEnvironmentName))
+		}
+		return nil, errors.Errorf("multiple service plans match %s, use --service and --environment to select one: %s", plan, strings.Join(candidates, ", "))
+	}
+}
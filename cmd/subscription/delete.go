@@ -0,0 +1,114 @@
+package subscription
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/cqroot/prompt"
+	"github.com/cqroot/prompt/input"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	deleteExample = `# Delete a subscription
+omctl subscription delete [subscription-id]`
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete [subscription-id] [flags]",
+	Short: "Delete a Customer Subscription",
+	Long: `This command helps you delete a Customer Subscription.
+The confirmation prompt shows how many instances belong to the subscription, which can be listed with
+'omctl subscription instances'.`,
+	Example:      deleteExample,
+	RunE:         runDelete,
+	SilenceUsage: true,
+}
+
+func init() {
+	deleteCmd.Flags().BoolP("yes", "y", false, "Pre-approve the deletion of the subscription without prompting for confirmation")
+	deleteCmd.Args = cobra.ExactArgs(1) // Require exactly one argument
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve args
+	subscriptionID := args[0]
+
+	// Retrieve flags
+	output, _ := cmd.Flags().GetString("output")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Check if the subscription exists
+	subscription, err := getSubscription(cmd.Context(), token, subscriptionID)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Confirm deletion
+	if !yes {
+		instances, err := listSubscriptionInstances(cmd.Context(), token, map[string]string{subscriptionID: subscription.RootUserEmail})
+		if err != nil {
+			utils.PrintError(err)
+			return err
+		}
+
+		question := fmt.Sprintf("Are you sure you want to delete subscription %s of %s to %s?", subscriptionID, subscription.RootUserEmail, subscription.ServicePlanName)
+		if len(instances) > 0 {
+			question += fmt.Sprintf(" It has %d instances.", len(instances))
+		}
+		ok, err := prompt.New().Ask(question+" (y/n)").
+			Input("", input.WithValidateFunc(
+				func(input string) error {
+					if slices.Contains([]string{"y", "yes", "n", "no"}, strings.ToLower(input)) {
+						return nil
+					} else {
+						return errors.New("invalid input")
+					}
+				}))
+		if err != nil {
+			utils.PrintError(err)
+			return err
+		}
+
+		if !slices.Contains([]string{"y", "yes"}, strings.ToLower(ok)) {
+			return nil
+		}
+	}
+
+	// Initialize spinner if output is not JSON
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		msg := "Deleting subscription..."
+		spinner = sm.AddSpinner(msg)
+		sm.Start()
+	}
+
+	err = dataaccess.TerminateSubscription(cmd.Context(), token, subscription.ServiceID, subscription.ServiceEnvironmentID, subscriptionID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	utils.HandleSpinnerSuccess(spinner, sm, "Successfully deleted subscription")
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package subscription
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	instancesExample = `# List the instances of a subscription
+omctl subscription instances [subscription-id]
+
+# List the instances of all subscriptions of a customer
+omctl subscription instances --customer-email user@example.com
+
+# List the instances of all subscriptions of an organization
+omctl subscription instances --organization-id org-abcd1234`
+)
+
+var instancesCmd = &cobra.Command{
+	Use:   "instances [subscription-id] [flags]",
+	Short: "List the instance deployments of Customer Subscriptions",
+	Long: `This command helps you list the instance deployments that belong to a Customer Subscription.
+Instead of a subscription ID, the customer email and organization flags select the subscriptions whose instances are
+listed. When combined, a subscription must match all of them.`,
+	Example:      instancesExample,
+	RunE:         runInstances,
+	SilenceUsage: true,
+}
+
+func init() {
+	instancesCmd.Args = cobra.MaximumNArgs(1)
+
+	instancesCmd.Flags().String("customer-email", "", "List the instances of the subscriptions owned by this customer email")
+	instancesCmd.Flags().String("organization-id", "", "List the instances of the subscriptions of this organization")
+	common.AddListFlags(instancesCmd, utils.GetSupportedFilterKeys(model.SubscriptionInstance{}, utils.ScalarFieldsOnly))
+}
+
+func runInstances(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve args
+	var subscriptionID string
+	if len(args) > 0 {
+		subscriptionID = args[0]
+	}
+
+	// Retrieve flags
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	customerEmail, err := cmd.Flags().GetString("customer-email")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	organizationID, err := cmd.Flags().GetString("organization-id")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	if subscriptionID == "" && customerEmail == "" && organizationID == "" {
+		err = errors.New("a subscription ID, --customer-email or --organization-id is required")
+		utils.PrintError(err)
+		return err
+	}
+
+	// Parse sorting, pagination and column options
+	listOpts, err := common.GetListOptions(cmd, utils.GetSupportedFilterKeys(model.SubscriptionInstance{}, utils.ScalarFieldsOnly))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not JSON
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		msg := "Retrieving subscription instances..."
+		spinner = sm.AddSpinner(msg)
+		sm.Start()
+	}
+
+	searchRes, err := dataaccess.SearchInventory(cmd.Context(), token, "subscription:s")
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	subscriptions := matchSubscriptions(searchRes.SubscriptionResults, subscriptionID, customerEmail, organizationID)
+	if len(subscriptions) == 0 {
+		err = errors.New("no subscriptions found. Please check the subscription ID, customer email and organization and try again")
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	instances, err := listSubscriptionInstances(cmd.Context(), token, subscriptions)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	if len(instances) == 0 {
+		utils.HandleSpinnerSuccess(spinner, sm, "No instances found")
+	} else {
+		utils.HandleSpinnerSuccess(spinner, sm, "Successfully retrieved subscription instances")
+	}
+
+	// Sort and paginate the results
+	instances, err = utils.SortAndPaginate(instances, listOpts)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Print output
+	err = utils.PrintListOutput(output, instances, listOpts)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	return nil
+}
+
+// matchSubscriptions returns the owner emails of the subscriptions matching all the set criteria, by subscription ID.
+func matchSubscriptions(subscriptions []openapiclientfleet.SubscriptionSearchRecord, subscriptionID, customerEmail, organizationID string) map[string]string {
+	matched := make(map[string]string)
+	for _, subscription := range subscriptions {
+		if subscription.Id == "" ||
+			(subscriptionID != "" && subscription.Id != subscriptionID) ||
+			(customerEmail != "" && !strings.EqualFold(subscription.RootUserEmail, customerEmail)) ||
+			(organizationID != "" && subscription.OrgID != organizationID) {
+			continue
+		}
+		matched[subscription.Id] = subscription.RootUserEmail
+	}
+	return matched
+}
+
+// listSubscriptionInstances returns the instances of the subscriptions, given as owner emails by subscription ID.
+func listSubscriptionInstances(ctx context.Context, token string, subscriptions map[string]string) ([]model.SubscriptionInstance, error) {
+	searchRes, err := dataaccess.SearchInventory(ctx, token, "resourceinstance:i")
+	if err != nil {
+		return nil, err
+	}
+	return formatSubscriptionInstances(searchRes.ResourceInstanceResults, subscriptions), nil
+}
+
+func formatSubscriptionInstances(instances []openapiclientfleet.ResourceInstanceSearchRecord, subscriptions map[string]string) []model.SubscriptionInstance {
+	formattedInstances := make([]model.SubscriptionInstance, 0)
+	for _, instance := range instances {
+		ownerEmail, ok := subscriptions[instance.GetSubscriptionId()]
+		if instance.Id == "" || !ok {
+			continue
+		}
+
+		formattedInstances = append(formattedInstances, model.SubscriptionInstance{
+			InstanceID:             instance.Id,
+			SubscriptionID:         instance.GetSubscriptionId(),
+			SubscriptionOwnerEmail: ownerEmail,
+			Service:                instance.ServiceName,
+			Environment:            instance.ServiceEnvironmentName,
+			Plan:                   instance.GetProductTierName(),
+			Version:                instance.GetProductTierVersion(),
+			Resource:               instance.ResourceName,
+			CloudProvider:          instance.CloudProvider,
+			Region:                 instance.RegionCode,
+			Status:                 instance.Status,
+		})
+	}
+	return formattedInstances
+}
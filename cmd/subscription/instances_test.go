@@ -0,0 +1,64 @@
+package subscription
+
+import (
+	"testing"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchSubscriptions(t *testing.T) {
+	subscriptions := []openapiclientfleet.SubscriptionSearchRecord{
+		{Id: "sub-1", OrgID: "org-a", RootUserEmail: "alice@a.com"},
+		{Id: "sub-2", OrgID: "org-a", RootUserEmail: "bob@a.com"},
+		{Id: "sub-3", OrgID: "org-b", RootUserEmail: "Alice@A.com"},
+		{Id: "", OrgID: "org-a", RootUserEmail: "alice@a.com"},
+	}
+
+	tests := []struct {
+		name           string
+		subscriptionID string
+		customerEmail  string
+		organizationID string
+		expected       map[string]string
+	}{
+		{name: "by ID", subscriptionID: "sub-2", expected: map[string]string{"sub-2": "bob@a.com"}},
+		{name: "by email", customerEmail: "alice@a.com", expected: map[string]string{"sub-1": "alice@a.com", "sub-3": "Alice@A.com"}},
+		{name: "by organization", organizationID: "org-a", expected: map[string]string{"sub-1": "alice@a.com", "sub-2": "bob@a.com"}},
+		{name: "by email and organization", customerEmail: "alice@a.com", organizationID: "org-b", expected: map[string]string{"sub-3": "Alice@A.com"}},
+		{name: "no match", subscriptionID: "sub-1", organizationID: "org-b", expected: map[string]string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, matchSubscriptions(subscriptions, test.subscriptionID, test.customerEmail, test.organizationID))
+		})
+	}
+}
+
+func TestFormatSubscriptionInstances(t *testing.T) {
+	planName, version := "premium", "1.2"
+	subscriptionID, otherSubscriptionID := "sub-1", "sub-2"
+	instances := []openapiclientfleet.ResourceInstanceSearchRecord{
+		{Id: "instance-1", SubscriptionId: &subscriptionID, ServiceName: "postgres", ServiceEnvironmentName: "prod", ProductTierName: &planName, ProductTierVersion: &version, ResourceName: "db", CloudProvider: "aws", RegionCode: "us-east-2", Status: "RUNNING"},
+		{Id: "instance-2", SubscriptionId: &otherSubscriptionID},
+		{Id: "instance-3"},
+	}
+
+	require.Equal(t, []model.SubscriptionInstance{
+		{
+			InstanceID:             "instance-1",
+			SubscriptionID:         "sub-1",
+			SubscriptionOwnerEmail: "alice@a.com",
+			Service:                "postgres",
+			Environment:            "prod",
+			Plan:                   "premium",
+			Version:                "1.2",
+			Resource:               "db",
+			CloudProvider:          "aws",
+			Region:                 "us-east-2",
+			Status:                 "RUNNING",
+		},
+	}, formatSubscriptionInstances(instances, map[string]string{"sub-1": "alice@a.com"}))
+}
@@ -14,7 +14,13 @@ import (
 
 const (
 	listExample = `# List subscriptions of the service postgres and mysql in the prod environment
-omctl subscription list -f="service_name:postgres,environment:prod" -f="service:mysql,environment:prod"`
+omctl subscription list -f="service_name:postgres,environment:prod" -f="service_name:mysql,environment:prod"
+
+# List subscriptions of the example.com organization that are not suspended
+omctl subscription list -f="subscription_owner_email ilike *@example.com and status != SUSPENDED"
+
+# List the second page of 20 subscriptions, sorted by owner name, showing only the ID, owner and status
+omctl subscription list --sort-by subscription_owner_name --limit 20 --offset 20 --columns subscription_id,subscription_owner_name,status`
 	defaultMaxNameLength = 30 // Maximum length of the name column in the table
 )
 
@@ -29,7 +35,8 @@ You can filter for specific subscriptions by using the filter flag.`,
 }
 
 func init() {
-	listCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of subscriptions. E.g.: key1:value1,key2:value2, which filters subscriptions where key1 equals value1 and key2 equals value2. Allow use of multiple filters to form the logical OR operation. Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.Subscription{}), ",")+". Check the examples for more details.")
+	listCmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of subscriptions, e.g. \"service_name ilike postgres* and status != SUSPENDED\". "+utils.FilterExpressionSyntax+" Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.Subscription{}, utils.ScalarFieldsOnly), ",")+". Check the examples for more details.")
+	common.AddListFlags(listCmd, utils.GetSupportedFilterKeys(model.Subscription{}, utils.ScalarFieldsOnly))
 	listCmd.Flags().Bool("truncate", false, "Truncate long names in the output")
 }
 
@@ -51,8 +58,15 @@ func runList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Parse filters into a map
-	filterMaps, err := utils.ParseFilters(filters, utils.GetSupportedFilterKeys(model.Subscription{}))
+	// Parse filters into expressions
+	filterExpressions, err := utils.ParseFilterExpressions(filters, utils.GetSupportedFilterKeys(model.Subscription{}, utils.ScalarFieldsOnly))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Parse sorting, pagination and column options
+	listOpts, err := common.GetListOptions(cmd, utils.GetSupportedFilterKeys(model.Subscription{}, utils.ScalarFieldsOnly))
 	if err != nil {
 		utils.PrintError(err)
 		return err
@@ -92,7 +106,7 @@ func runList(cmd *cobra.Command, args []string) error {
 		formattedSubscription := formatSubscription(&subscription, truncateNames)
 
 		// Check if the subscription matches the filters
-		ok, err := utils.MatchesFilters(formattedSubscription, filterMaps)
+		ok, err := utils.MatchesFilterExpressions(formattedSubscription, filterExpressions)
 		if err != nil {
 			utils.HandleSpinnerError(spinner, sm, err)
 			return err
@@ -109,8 +123,15 @@ func runList(cmd *cobra.Command, args []string) error {
 		utils.HandleSpinnerSuccess(spinner, sm, "Successfully retrieved subscriptions")
 	}
 
+	// Sort and paginate the results
+	formattedSubscriptions, err = utils.SortAndPaginate(formattedSubscriptions, listOpts)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
 	// Print output
-	err = utils.PrintTextTableJsonOutput(output, formattedSubscriptions)
+	err = utils.PrintListOutput(output, formattedSubscriptions, listOpts)
 	if err != nil {
 		utils.PrintError(err)
 		return err
@@ -0,0 +1,90 @@
+package subscription
+
+import (
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	resumeExample = `# Resume a suspended subscription
+omctl subscription resume [subscription-id]`
+)
+
+var resumeCmd = &cobra.Command{
+	Use:          "resume [subscription-id]",
+	Short:        "Resume a suspended Customer Subscription",
+	Long:         `This command helps you resume a suspended Customer Subscription.`,
+	Example:      resumeExample,
+	RunE:         runResume,
+	SilenceUsage: true,
+}
+
+func init() {
+	resumeCmd.Args = cobra.ExactArgs(1) // Require exactly one argument
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve args
+	subscriptionID := args[0]
+
+	// Retrieve flags
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not JSON
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		msg := "Resuming subscription..."
+		spinner = sm.AddSpinner(msg)
+		sm.Start()
+	}
+
+	// Check if the subscription exists
+	subscription, err := getSubscription(cmd.Context(), token, subscriptionID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	err = dataaccess.ResumeSubscription(cmd.Context(), token, subscription.ServiceID, subscription.ServiceEnvironmentID, subscriptionID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	// Retrieve the updated status
+	subscription, err = getSubscription(cmd.Context(), token, subscriptionID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	utils.HandleSpinnerSuccess(spinner, sm, "Successfully resumed subscription")
+
+	// Print output
+	err = utils.PrintTextTableJsonOutput(output, formatSubscription(subscription, false))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	return nil
+}
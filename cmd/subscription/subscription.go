@@ -15,7 +15,11 @@ var Cmd = &cobra.Command{
 func init() {
 	Cmd.AddCommand(listCmd)
 	Cmd.AddCommand(describeCmd)
-
+	Cmd.AddCommand(createCmd)
+	Cmd.AddCommand(suspendCmd)
+	Cmd.AddCommand(resumeCmd)
+	Cmd.AddCommand(deleteCmd)
+	Cmd.AddCommand(instancesCmd)
 }
 
 func run(cmd *cobra.Command, args []string) {
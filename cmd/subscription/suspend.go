@@ -0,0 +1,91 @@
+package subscription
+
+import (
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	suspendExample = `# Suspend a subscription
+omctl subscription suspend [subscription-id]`
+)
+
+var suspendCmd = &cobra.Command{
+	Use:   "suspend [subscription-id]",
+	Short: "Suspend a Customer Subscription",
+	Long: `This command helps you suspend a Customer Subscription.
+A suspended subscription can be resumed with 'omctl subscription resume'.`,
+	Example:      suspendExample,
+	RunE:         runSuspend,
+	SilenceUsage: true,
+}
+
+func init() {
+	suspendCmd.Args = cobra.ExactArgs(1) // Require exactly one argument
+}
+
+func runSuspend(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve args
+	subscriptionID := args[0]
+
+	// Retrieve flags
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not JSON
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		msg := "Suspending subscription..."
+		spinner = sm.AddSpinner(msg)
+		sm.Start()
+	}
+
+	// Check if the subscription exists
+	subscription, err := getSubscription(cmd.Context(), token, subscriptionID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	err = dataaccess.SuspendSubscription(cmd.Context(), token, subscription.ServiceID, subscription.ServiceEnvironmentID, subscriptionID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	// Retrieve the updated status
+	subscription, err = getSubscription(cmd.Context(), token, subscriptionID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	utils.HandleSpinnerSuccess(spinner, sm, "Successfully suspended subscription")
+
+	// Print output
+	err = utils.PrintTextTableJsonOutput(output, formatSubscription(subscription, false))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	return nil
+}
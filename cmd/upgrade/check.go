@@ -0,0 +1,124 @@
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	checkExample = `# Check if instances can be upgraded to a specific version
+omctl upgrade check [instance1] [instance2] --version=2.0
+
+# Check if all instances of a plan in an environment can be upgraded to the preferred version
+omctl upgrade check --service=postgres --plan=Premium --environment=Prod --version=preferred`
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [instance-id...] --version=[version]",
+	Short: "Check if Instance Deployments are compatible with a version before upgrading them",
+	Long: `This command checks, for each instance, if it can be upgraded to the target version without creating any
+upgrade. The instances are given or selected as with 'omctl upgrade'.
+
+The parameters of each instance are compared with the parameter definitions of the target version, and the resources
+of the source version with the ones of the target version. Blocking issues, such as a parameter set on the instance
+being removed or changing type, or a resource being removed or changing type, make the upgrade fail. Warnings, such as
+a deprecated target version or a new parameter, are worth reviewing. The command exits with an error if any issue is
+blocking.`,
+	Example:      checkExample,
+	RunE:         runCheck,
+	SilenceUsage: true,
+}
+
+func init() {
+	checkCmd.Args = cobra.ArbitraryArgs
+
+	checkCmd.Flags().StringP("version", "", "", "Specify the version number to check the upgrade to. Use 'latest' for the latest version and 'preferred' for the preferred version.")
+	checkCmd.Flags().StringP("version-name", "", "", "Specify the version name to check the upgrade to. Use either this flag or the --version flag.")
+	checkCmd.Flags().String("service", "", "Check the instances of this service, by name or ID, instead of the given instance IDs")
+	checkCmd.Flags().String("plan", "", "Check the instances of this service plan, by name or ID")
+	checkCmd.Flags().String("environment", "", "Only check the instances in this environment, by name or ID")
+	checkCmd.Flags().String("from-version", "", "Only check the instances currently at this version")
+	checkCmd.Flags().StringSlice("exclude", []string{}, "Instance IDs to leave out of the check")
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve flags
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	req, err := getUpgradeRequest(cmd, args)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not json
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		spinner = sm.AddSpinner("Checking upgrade compatibility")
+		sm.Start()
+	}
+
+	// Find the instances to check with their source and target versions
+	instances, _, err := resolveUpgradeInstances(cmd, token, req)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	targets := make([]preflightTarget, 0, len(instances))
+	for _, instance := range instances {
+		targets = append(targets, instance.preflightTarget())
+	}
+
+	issues, err := newPreflightChecker(cmd.Context(), token).check(targets)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	return printCheckIssues(spinner, sm, output, len(targets), issues)
+}
+
+// printCheckIssues prints the issues found checking the upgrade of the instances, and returns an error if any of them
+// is blocking.
+func printCheckIssues(spinner *ysmrr.Spinner, sm ysmrr.SpinnerManager, output string, instances int, issues []model.UpgradeCheckIssue) error {
+	if len(issues) == 0 {
+		utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("No compatibility issues found upgrading %s", plural(instances, "instance")))
+		if output == "json" {
+			return utils.PrintTextTableJsonArrayOutput(output, issues)
+		}
+		return nil
+	}
+
+	utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("Found %s", summarizeIssues(issues)))
+	if err := utils.PrintTextTableJsonArrayOutput(output, issues); err != nil {
+		return err
+	}
+
+	if hasBlockingIssues(issues) {
+		err := fmt.Errorf("upgrade check found %s", summarizeIssues(issues))
+		utils.PrintError(err)
+		return err
+	}
+	return nil
+}
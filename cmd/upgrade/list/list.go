@@ -0,0 +1,389 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	listExample = `# List all upgrades
+omctl upgrade list
+
+# List the upgrades still in progress or failed in the last week
+omctl upgrade list --status IN_PROGRESS,FAILED --since 168h
+
+# List the upgrades of a plan to a target version, newest first
+omctl upgrade list --service postgres --plan premium --target-version 2.0 --sort-by created_at,desc
+
+# List upgrades created in a date range, filtering on the counts
+omctl upgrade list --start-time 2025-01-01T00:00:00Z --end-time 2025-02-01T00:00:00Z -f "failed != 0"`
+
+	// describeParallelism is the number of upgrade paths described at a time
+	describeParallelism = 8
+)
+
+var Cmd = &cobra.Command{
+	Use:   "list [flags]",
+	Short: "List upgrades",
+	Long: `This command helps you list upgrades with their instance counts, to find upgrade IDs for the other upgrade
+commands. Upgrades can be filtered by service, plan, status, source and target version, and creation date.
+
+The service, plan and status filters, and filter expressions only on upgrade_id, service, plan, environment and
+status, are applied to the search results. Every remaining upgrade is then described to get its versions, creation
+date and instance counts, so source and target version, date and other filters don't make listing faster: combine
+them with --service, --plan or --status to describe fewer upgrades.`,
+	Example:      listExample,
+	RunE:         run,
+	SilenceUsage: true,
+}
+
+// listFilters are the upgrade path filters set by flags.
+type listFilters struct {
+	Service       string
+	Plan          string
+	Statuses      []string
+	SourceVersion string
+	TargetVersion string
+	StartTime     time.Time
+	EndTime       time.Time
+}
+
+func init() {
+	Cmd.Args = cobra.NoArgs
+
+	addListFilterFlags(Cmd)
+	Cmd.Flags().StringArrayP("filter", "f", []string{}, "Filter to apply to the list of upgrades, e.g. \"failed != 0\". "+utils.FilterExpressionSyntax+" Supported keys: "+strings.Join(utils.GetSupportedFilterKeys(model.UpgradePathSummary{}, utils.ScalarFieldsOnly), ","))
+	common.AddListFlags(Cmd, utils.GetSupportedFilterKeys(model.UpgradePathSummary{}, utils.ScalarFieldsOnly))
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve flags
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	filterExpressions, err := cmd.Flags().GetStringArray("filter")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	filters, err := getListFilters(cmd, time.Now())
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Parse filters into expressions
+	expressions, err := utils.ParseFilterExpressions(filterExpressions, utils.GetSupportedFilterKeys(model.UpgradePathSummary{}, utils.ScalarFieldsOnly))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Parse sorting, pagination and column options
+	listOpts, err := common.GetListOptions(cmd, utils.GetSupportedFilterKeys(model.UpgradePathSummary{}, utils.ScalarFieldsOnly))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not json
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		msg := "Retrieving upgrades..."
+		spinner = sm.AddSpinner(msg)
+		sm.Start()
+	}
+
+	searchRes, err := dataaccess.SearchInventory(cmd.Context(), token, "upgradepath:u")
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	// Filter on the search records first, so that only the matching upgrade paths are described
+	records := make([]openapiclientfleet.UpgradePathSearchRecord, 0)
+	for _, record := range searchRes.UpgradePathResults {
+		if record.Id == "" || !filters.matchesRecord(record) {
+			continue
+		}
+		ok, err := matchesRecordExpressions(record, expressions)
+		if err != nil {
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+		if ok {
+			records = append(records, record)
+		}
+	}
+
+	upgradePaths, err := describeUpgradePaths(cmd.Context(), records, func(ctx context.Context, record openapiclientfleet.UpgradePathSearchRecord) (*openapiclientfleet.UpgradePath, error) {
+		return dataaccess.DescribeUpgradePath(ctx, token, record.ServiceId, record.ProductTierID, record.Id)
+	})
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	formattedUpgrades := make([]model.UpgradePathSummary, 0)
+	for i, upgradePath := range upgradePaths {
+		if !filters.matchesUpgradePath(upgradePath) {
+			continue
+		}
+
+		formattedUpgrade := formatUpgradePathSummary(records[i], upgradePath)
+		ok, err := utils.MatchesFilterExpressions(formattedUpgrade, expressions)
+		if err != nil {
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+		if ok {
+			formattedUpgrades = append(formattedUpgrades, formattedUpgrade)
+		}
+	}
+
+	if len(formattedUpgrades) == 0 {
+		utils.HandleSpinnerSuccess(spinner, sm, "No upgrades found")
+	} else {
+		utils.HandleSpinnerSuccess(spinner, sm, "Successfully retrieved upgrades")
+	}
+
+	// Sort and paginate the results
+	formattedUpgrades, err = utils.SortAndPaginate(formattedUpgrades, listOpts)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Print output
+	err = utils.PrintListOutput(output, formattedUpgrades, listOpts)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	return nil
+}
+
+func addListFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("service", "", "Service name or ID")
+	cmd.Flags().String("plan", "", "Service plan name or ID")
+	cmd.Flags().StringSlice("status", []string{}, "Upgrade statuses to list, e.g. IN_PROGRESS,FAILED. Valid statuses: "+strings.Join(upgradePathStatuses(), ","))
+	cmd.Flags().String("source-version", "", "Source version of the upgrades")
+	cmd.Flags().String("target-version", "", "Target version of the upgrades")
+	cmd.Flags().Duration("since", 0, "Only list upgrades created within this duration, e.g. 168h for the last week")
+	cmd.Flags().String("start-time", "", "Only list upgrades created at or after this time (RFC3339 format)")
+	cmd.Flags().String("end-time", "", "Only list upgrades created before this time (RFC3339 format)")
+}
+
+func getListFilters(cmd *cobra.Command, now time.Time) (filters listFilters, err error) {
+	if filters.Service, err = cmd.Flags().GetString("service"); err != nil {
+		return
+	}
+	if filters.Plan, err = cmd.Flags().GetString("plan"); err != nil {
+		return
+	}
+	if filters.SourceVersion, err = cmd.Flags().GetString("source-version"); err != nil {
+		return
+	}
+	if filters.TargetVersion, err = cmd.Flags().GetString("target-version"); err != nil {
+		return
+	}
+
+	statuses, err := cmd.Flags().GetStringSlice("status")
+	if err != nil {
+		return
+	}
+	for _, status := range statuses {
+		status = strings.ToUpper(strings.TrimSpace(status))
+		if !slices.Contains(upgradePathStatuses(), status) {
+			err = fmt.Errorf("invalid status %s, valid statuses are: %s", status, strings.Join(upgradePathStatuses(), ", "))
+			return
+		}
+		filters.Statuses = append(filters.Statuses, status)
+	}
+
+	since, err := cmd.Flags().GetDuration("since")
+	if err != nil {
+		return
+	}
+	startTime, err := cmd.Flags().GetString("start-time")
+	if err != nil {
+		return
+	}
+	endTime, err := cmd.Flags().GetString("end-time")
+	if err != nil {
+		return
+	}
+
+	if since < 0 {
+		err = errors.New("--since must not be negative")
+		return
+	}
+	if since > 0 && startTime != "" {
+		err = errors.New("--since can't be combined with --start-time")
+		return
+	}
+	if since > 0 {
+		filters.StartTime = now.Add(-since)
+	}
+	if startTime != "" {
+		if filters.StartTime, err = time.Parse(time.RFC3339, startTime); err != nil {
+			err = errors.Wrap(err, "invalid --start-time, expected RFC3339 format")
+			return
+		}
+	}
+	if endTime != "" {
+		if filters.EndTime, err = time.Parse(time.RFC3339, endTime); err != nil {
+			err = errors.Wrap(err, "invalid --end-time, expected RFC3339 format")
+			return
+		}
+	}
+	if !filters.StartTime.IsZero() && !filters.EndTime.IsZero() && !filters.EndTime.After(filters.StartTime) {
+		err = errors.New("the end time must be after the start time")
+		return
+	}
+
+	return
+}
+
+func upgradePathStatuses() []string {
+	statuses := make([]string, 0, len(model.UpgradePathStatuses))
+	for _, status := range model.UpgradePathStatuses {
+		statuses = append(statuses, status.String())
+	}
+	return statuses
+}
+
+// matchesRecord checks the filters available in the search record.
+func (f listFilters) matchesRecord(record openapiclientfleet.UpgradePathSearchRecord) bool {
+	if f.Service != "" && f.Service != record.ServiceId && !strings.EqualFold(f.Service, record.ServiceName) {
+		return false
+	}
+	if f.Plan != "" && f.Plan != record.ProductTierID && !strings.EqualFold(f.Plan, record.ProductTierName) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, strings.ToUpper(record.Status)) {
+		return false
+	}
+	return true
+}
+
+// recordSummaryKeys are the keys of an upgrade summary known from its search record, before describing the upgrade path.
+var recordSummaryKeys = []string{"upgrade_id", "service", "plan", "environment", "status"}
+
+// matchesRecordExpressions checks the filter expressions against the search record when they only use keys known from
+// it. Otherwise, they are checked once the upgrade path is described.
+func matchesRecordExpressions(record openapiclientfleet.UpgradePathSearchRecord, expressions []*utils.FilterExpression) (bool, error) {
+	for _, key := range utils.GetSupportedFilterKeys(model.UpgradePathSummary{}, utils.ScalarFieldsOnly) {
+		if slices.Contains(recordSummaryKeys, key) {
+			continue
+		}
+		for _, expression := range expressions {
+			if expression.UsesKey(key) {
+				return true, nil
+			}
+		}
+	}
+
+	return utils.MatchesFilterExpressions(formatUpgradePathSummary(record, &openapiclientfleet.UpgradePath{Status: record.Status}), expressions)
+}
+
+// matchesUpgradePath checks the filters that need the described upgrade path.
+func (f listFilters) matchesUpgradePath(upgradePath *openapiclientfleet.UpgradePath) bool {
+	if f.SourceVersion != "" && f.SourceVersion != upgradePath.SourceVersion {
+		return false
+	}
+	if f.TargetVersion != "" && f.TargetVersion != upgradePath.TargetVersion {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, strings.ToUpper(upgradePath.Status)) {
+		return false
+	}
+
+	if !f.StartTime.IsZero() || !f.EndTime.IsZero() {
+		createdAt, err := time.Parse(time.RFC3339, upgradePath.CreatedAt)
+		if err != nil {
+			return false
+		}
+		if !f.StartTime.IsZero() && createdAt.Before(f.StartTime) {
+			return false
+		}
+		if !f.EndTime.IsZero() && !createdAt.Before(f.EndTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeUpgradePaths describes the upgrade paths of the search records, a few at a time, in the order of the records.
+func describeUpgradePaths(
+	ctx context.Context,
+	records []openapiclientfleet.UpgradePathSearchRecord,
+	describe func(context.Context, openapiclientfleet.UpgradePathSearchRecord) (*openapiclientfleet.UpgradePath, error),
+) ([]*openapiclientfleet.UpgradePath, error) {
+	upgradePaths := make([]*openapiclientfleet.UpgradePath, len(records))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(describeParallelism)
+	for i, record := range records {
+		g.Go(func() error {
+			upgradePath, err := describe(ctx, record)
+			if err != nil {
+				return errors.Wrapf(err, "failed to describe upgrade %s", record.Id)
+			}
+			upgradePaths[i] = upgradePath
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return upgradePaths, nil
+}
+
+func formatUpgradePathSummary(record openapiclientfleet.UpgradePathSearchRecord, upgradePath *openapiclientfleet.UpgradePath) model.UpgradePathSummary {
+	return model.UpgradePathSummary{
+		UpgradeID:     record.Id,
+		Service:       record.ServiceName,
+		Plan:          record.ProductTierName,
+		Environment:   record.ServiceEnvironmentName,
+		SourceVersion: upgradePath.SourceVersion,
+		TargetVersion: upgradePath.TargetVersion,
+		Status:        upgradePath.Status,
+		Total:         upgradePath.TotalCount,
+		Pending:       upgradePath.PendingCount,
+		InProgress:    upgradePath.InProgressCount,
+		Completed:     upgradePath.CompletedCount,
+		Failed:        upgradePath.FailedCount,
+		Skipped:       upgradePath.SkippedCount,
+		CreatedAt:     upgradePath.CreatedAt,
+		CompletedAt:   utils.FromPtr(upgradePath.CompletedAt),
+	}
+}
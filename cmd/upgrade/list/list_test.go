@@ -0,0 +1,162 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCmd(t *testing.T, args ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	addListFilterFlags(cmd)
+	require.NoError(t, cmd.ParseFlags(args))
+	return cmd
+}
+
+func TestGetListFilters(t *testing.T) {
+	now := time.Date(2025, 3, 8, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		args             []string
+		expected         listFilters
+		expectedErrorMsg string
+	}{
+		{
+			name: "status and versions",
+			args: []string{"--status", "in_progress,FAILED", "--source-version", "1.0", "--target-version", "2.0"},
+			expected: listFilters{
+				Statuses:      []string{"IN_PROGRESS", "FAILED"},
+				SourceVersion: "1.0",
+				TargetVersion: "2.0",
+			},
+		},
+		{
+			name:     "since",
+			args:     []string{"--since", "168h"},
+			expected: listFilters{StartTime: now.Add(-168 * time.Hour)},
+		},
+		{
+			name: "time range",
+			args: []string{"--start-time", "2025-01-01T00:00:00Z", "--end-time", "2025-02-01T00:00:00Z"},
+			expected: listFilters{
+				StartTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{name: "invalid status", args: []string{"--status", "RUNNING"}, expectedErrorMsg: "invalid status RUNNING"},
+		{name: "since and start time", args: []string{"--since", "1h", "--start-time", "2025-01-01T00:00:00Z"}, expectedErrorMsg: "--since can't be combined with --start-time"},
+		{name: "invalid start time", args: []string{"--start-time", "2025-01-01"}, expectedErrorMsg: "invalid --start-time"},
+		{name: "empty range", args: []string{"--start-time", "2025-02-01T00:00:00Z", "--end-time", "2025-01-01T00:00:00Z"}, expectedErrorMsg: "the end time must be after the start time"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filters, err := getListFilters(newTestCmd(t, test.args...), now)
+			if test.expectedErrorMsg != "" {
+				require.ErrorContains(t, err, test.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, filters)
+		})
+	}
+}
+
+func TestListFiltersMatch(t *testing.T) {
+	require := require.New(t)
+
+	record := openapiclientfleet.UpgradePathSearchRecord{
+		Id:              "up-1",
+		ServiceId:       "s-1",
+		ServiceName:     "Postgres",
+		ProductTierID:   "pt-1",
+		ProductTierName: "Premium",
+		Status:          "IN_PROGRESS",
+	}
+	require.True(listFilters{}.matchesRecord(record))
+	require.True(listFilters{Service: "postgres", Plan: "pt-1", Statuses: []string{"FAILED", "IN_PROGRESS"}}.matchesRecord(record))
+	require.False(listFilters{Service: "mysql"}.matchesRecord(record))
+	require.False(listFilters{Plan: "basic"}.matchesRecord(record))
+	require.False(listFilters{Statuses: []string{"COMPLETE"}}.matchesRecord(record))
+
+	upgradePath := &openapiclientfleet.UpgradePath{
+		SourceVersion: "1.0",
+		TargetVersion: "2.0",
+		Status:        "IN_PROGRESS",
+		CreatedAt:     "2025-03-05T10:00:00Z",
+	}
+	require.True(listFilters{SourceVersion: "1.0", TargetVersion: "2.0"}.matchesUpgradePath(upgradePath))
+	require.False(listFilters{TargetVersion: "3.0"}.matchesUpgradePath(upgradePath))
+	require.True(listFilters{StartTime: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)}.matchesUpgradePath(upgradePath))
+	require.False(listFilters{StartTime: time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)}.matchesUpgradePath(upgradePath))
+	require.False(listFilters{EndTime: time.Date(2025, 3, 5, 10, 0, 0, 0, time.UTC)}.matchesUpgradePath(upgradePath))
+}
+
+func TestCountFilterExpression(t *testing.T) {
+	require := require.New(t)
+
+	expressions, err := utils.ParseFilterExpressions([]string{"failed != 0"}, utils.GetSupportedFilterKeys(model.UpgradePathSummary{}, utils.ScalarFieldsOnly))
+	require.NoError(err)
+
+	ok, err := utils.MatchesFilterExpressions(model.UpgradePathSummary{Failed: 2}, expressions)
+	require.NoError(err)
+	require.True(ok)
+
+	ok, err = utils.MatchesFilterExpressions(model.UpgradePathSummary{Completed: 3}, expressions)
+	require.NoError(err)
+	require.False(ok)
+}
+
+func TestMatchesRecordExpressions(t *testing.T) {
+	require := require.New(t)
+
+	record := openapiclientfleet.UpgradePathSearchRecord{Id: "up-1", ServiceName: "Postgres", ProductTierName: "Premium", Status: "FAILED"}
+	for filters, expected := range map[string]bool{
+		"status = FAILED and service = Postgres": true,
+		"status = COMPLETE":                      false,
+		"plan = Basic":                           false,
+		// Filters needing the described upgrade path are checked after describing it
+		"plan = Basic or failed != 0":      true,
+		"status = COMPLETE and total != 0": true,
+	} {
+		expressions, err := utils.ParseFilterExpressions([]string{filters}, utils.GetSupportedFilterKeys(model.UpgradePathSummary{}, utils.ScalarFieldsOnly))
+		require.NoError(err)
+
+		ok, err := matchesRecordExpressions(record, expressions)
+		require.NoError(err)
+		require.Equal(expected, ok, filters)
+	}
+}
+
+func TestDescribeUpgradePaths(t *testing.T) {
+	require := require.New(t)
+
+	records := make([]openapiclientfleet.UpgradePathSearchRecord, 20)
+	for i := range records {
+		records[i].Id = string(rune('a' + i))
+	}
+
+	upgradePaths, err := describeUpgradePaths(context.Background(), records, func(_ context.Context, record openapiclientfleet.UpgradePathSearchRecord) (*openapiclientfleet.UpgradePath, error) {
+		return &openapiclientfleet.UpgradePath{UpgradePathId: record.Id}, nil
+	})
+	require.NoError(err)
+	for i, upgradePath := range upgradePaths {
+		require.Equal(records[i].Id, upgradePath.UpgradePathId)
+	}
+
+	_, err = describeUpgradePaths(context.Background(), records, func(_ context.Context, record openapiclientfleet.UpgradePathSearchRecord) (*openapiclientfleet.UpgradePath, error) {
+		if record.Id == "c" {
+			return nil, errors.New("not found")
+		}
+		return &openapiclientfleet.UpgradePath{}, nil
+	})
+	require.EqualError(err, "failed to describe upgrade c: not found")
+}
@@ -0,0 +1,229 @@
+package maintenancewindow
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	scopeCustomer     = "customer"
+	scopeSubscription = "subscription"
+
+	maintenanceWindowExample = `# Set the maintenance window of a customer
+omctl upgrade maintenance-window set "Sun 02:00-04:00 Europe/Berlin" --customer-email=admin@example.com
+
+# Set the maintenance window of a subscription, overriding the one of its customer
+omctl upgrade maintenance-window set "Mon-Fri 22:00-01:00 America/New_York" --subscription-id=sub-abcd1234
+
+# List the maintenance windows with their next start
+omctl upgrade maintenance-window list
+
+# Delete the maintenance window of a customer
+omctl upgrade maintenance-window delete --customer-email=admin@example.com
+
+# Upgrade instances in their maintenance windows
+omctl upgrade --service=postgres --plan=Premium --version=latest --respect-maintenance-windows --notify-customer`
+)
+
+var Cmd = &cobra.Command{
+	Use:   "maintenance-window [operation] [flags]",
+	Short: "Manage the maintenance windows used to schedule upgrades",
+	Long: `This command helps you manage maintenance windows, defined locally per customer email or subscription, and
+used by 'omctl upgrade --respect-maintenance-windows' to schedule the upgrade of each instance at the start of its
+next maintenance window.
+
+A maintenance window is written as ` + WindowSyntax + `.
+
+An instance can also set its maintenance window with a "maintenance-window" tag, also read as "maintenance_window",
+"maintenanceWindow" or "omnistrate.com/maintenance-window". The window of the instance tag takes precedence over the
+window of its subscription, which takes precedence over the window of its customer.`,
+	Example:      maintenanceWindowExample,
+	Run:          run,
+	SilenceUsage: true,
+}
+
+var setCmd = &cobra.Command{
+	Use:          "set [window] [flags]",
+	Short:        "Set the maintenance window of a customer or subscription",
+	Args:         cobra.ExactArgs(1),
+	RunE:         runSet,
+	SilenceUsage: true,
+}
+
+var listCmd = &cobra.Command{
+	Use:          "list [flags]",
+	Short:        "List the maintenance windows",
+	Args:         cobra.NoArgs,
+	RunE:         runList,
+	SilenceUsage: true,
+}
+
+var deleteCmd = &cobra.Command{
+	Use:          "delete [flags]",
+	Short:        "Delete the maintenance window of a customer or subscription",
+	Args:         cobra.NoArgs,
+	RunE:         runDelete,
+	SilenceUsage: true,
+}
+
+func init() {
+	Cmd.AddCommand(setCmd)
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(deleteCmd)
+
+	Cmd.PersistentFlags().String("file", "", "Maintenance windows file. Defaults to "+DefaultFile+" in the omnistrate-ctl config directory")
+
+	for _, cmd := range []*cobra.Command{setCmd, deleteCmd} {
+		cmd.Flags().String("customer-email", "", "Email of the customer")
+		cmd.Flags().String("subscription-id", "", "ID of the subscription")
+		cmd.MarkFlagsOneRequired("customer-email", "subscription-id")
+		cmd.MarkFlagsMutuallyExclusive("customer-email", "subscription-id")
+	}
+}
+
+func run(cmd *cobra.Command, args []string) {
+	err := cmd.Help()
+	if err != nil {
+		return
+	}
+}
+
+func runSet(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	customerEmail, _ := cmd.Flags().GetString("customer-email")
+	subscriptionID, _ := cmd.Flags().GetString("subscription-id")
+
+	window, err := Parse(args[0])
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	path := filePath(cmd)
+	store, err := Load(path)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	target := customerEmail
+	if subscriptionID != "" {
+		store.SetSubscription(subscriptionID, window.Spec)
+		target = subscriptionID
+	} else {
+		store.SetCustomer(customerEmail, window.Spec)
+	}
+
+	if err = store.Save(path); err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("Maintenance window of %s set to %q, next starting at %s", target, window.Spec, window.Next(time.Now()).Format(time.RFC3339)))
+	return nil
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	output, _ := cmd.Flags().GetString("output")
+
+	store, err := Load(filePath(cmd))
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	windows := formatMaintenanceWindows(store, time.Now())
+	if len(windows) == 0 && output != "json" {
+		utils.PrintInfo("No maintenance windows defined")
+		return nil
+	}
+
+	return utils.PrintTextTableJsonArrayOutput(output, windows)
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	customerEmail, _ := cmd.Flags().GetString("customer-email")
+	subscriptionID, _ := cmd.Flags().GetString("subscription-id")
+
+	path := filePath(cmd)
+	store, err := Load(path)
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	var deleted bool
+	target := customerEmail
+	if subscriptionID != "" {
+		deleted = store.DeleteSubscription(subscriptionID)
+		target = subscriptionID
+	} else {
+		deleted = store.DeleteCustomer(customerEmail)
+	}
+	if !deleted {
+		err = errors.Errorf("no maintenance window defined for %s", target)
+		utils.PrintError(err)
+		return err
+	}
+
+	if err = store.Save(path); err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	utils.PrintSuccess(fmt.Sprintf("Maintenance window of %s deleted", target))
+	return nil
+}
+
+func filePath(cmd *cobra.Command) string {
+	path, _ := cmd.Flags().GetString("file")
+	if path == "" {
+		return DefaultPath()
+	}
+	return path
+}
+
+// formatMaintenanceWindows lists the maintenance windows of the store, subscriptions first, with their next start.
+func formatMaintenanceWindows(store *Store, now time.Time) []model.MaintenanceWindow {
+	windows := make([]model.MaintenanceWindow, 0)
+	for _, scope := range []struct {
+		name    string
+		windows map[string]string
+	}{
+		{scopeSubscription, store.Subscriptions},
+		{scopeCustomer, store.Customers},
+	} {
+		targets := make([]string, 0, len(scope.windows))
+		for target := range scope.windows {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+
+		for _, target := range targets {
+			formatted := model.MaintenanceWindow{
+				Scope:  scope.name,
+				Target: target,
+				Window: scope.windows[target],
+			}
+			if window, err := Parse(scope.windows[target]); err == nil {
+				formatted.NextWindowStart = window.Next(now).Format(time.RFC3339)
+			} else {
+				formatted.NextWindowStart = "invalid window"
+			}
+			windows = append(windows, formatted)
+		}
+	}
+	return windows
+}
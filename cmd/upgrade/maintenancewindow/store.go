@@ -0,0 +1,152 @@
+package maintenancewindow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultFile is the file maintenance windows are stored in, in the omnistrate-ctl config directory
+	DefaultFile = "maintenance-windows.yml"
+)
+
+// Keys of the instance tag holding the maintenance window of an instance.
+var tagKeys = []string{"maintenance-window", "maintenance_window", "maintenanceWindow", "omnistrate.com/maintenance-window"}
+
+// Properties of the instance that may hold its tags.
+var tagProperties = []string{"tags", "customTags", "instanceTags"}
+
+// Store holds the maintenance windows defined locally, by customer email and by subscription ID.
+type Store struct {
+	Customers     map[string]string `yaml:"customers,omitempty"`
+	Subscriptions map[string]string `yaml:"subscriptions,omitempty"`
+}
+
+// DefaultPath returns the path of the default maintenance windows file.
+func DefaultPath() string {
+	return filepath.Join(config.ConfigDir(), DefaultFile)
+}
+
+// Load reads the maintenance windows from a file. A missing file has no maintenance windows.
+func Load(path string) (*Store, error) {
+	store := &Store{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse maintenance windows file %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Save writes the maintenance windows to a file, creating its directory if needed.
+func (s *Store) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), config.DefaultPermissions); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// SetCustomer sets the maintenance window of a customer, by email.
+func (s *Store) SetCustomer(email, window string) {
+	if s.Customers == nil {
+		s.Customers = make(map[string]string)
+	}
+	s.Customers[strings.ToLower(email)] = window
+}
+
+// SetSubscription sets the maintenance window of a subscription.
+func (s *Store) SetSubscription(subscriptionID, window string) {
+	if s.Subscriptions == nil {
+		s.Subscriptions = make(map[string]string)
+	}
+	s.Subscriptions[subscriptionID] = window
+}
+
+// DeleteCustomer removes the maintenance window of a customer, and reports whether it existed.
+func (s *Store) DeleteCustomer(email string) bool {
+	_, ok := s.Customers[strings.ToLower(email)]
+	delete(s.Customers, strings.ToLower(email))
+	return ok
+}
+
+// DeleteSubscription removes the maintenance window of a subscription, and reports whether it existed.
+func (s *Store) DeleteSubscription(subscriptionID string) bool {
+	_, ok := s.Subscriptions[subscriptionID]
+	delete(s.Subscriptions, subscriptionID)
+	return ok
+}
+
+// Lookup returns the maintenance window of an instance and where it was defined. The instance tag takes precedence
+// over the subscription, which takes precedence over the customer email.
+func (s *Store) Lookup(tagWindow, subscriptionID, customerEmail string) (window, source string) {
+	if tagWindow != "" {
+		return tagWindow, "instance tag"
+	}
+	if window = s.Subscriptions[subscriptionID]; subscriptionID != "" && window != "" {
+		return window, "subscription " + subscriptionID
+	}
+	if window = s.Customers[strings.ToLower(customerEmail)]; customerEmail != "" && window != "" {
+		return window, "customer " + customerEmail
+	}
+	return "", ""
+}
+
+// InstanceTagWindow returns the maintenance window set in the tags of an instance, if any. Tags are read from the
+// instance properties as either a map or a list of key/value pairs.
+func InstanceTagWindow(instance *openapiclientfleet.ResourceInstance) string {
+	if instance == nil {
+		return ""
+	}
+	for _, properties := range []map[string]interface{}{
+		instance.ConsumptionResourceInstanceResult.AdditionalProperties,
+		instance.AdditionalProperties,
+	} {
+		for _, property := range tagProperties {
+			if window := tagValue(properties[property]); window != "" {
+				return window
+			}
+		}
+	}
+	return ""
+}
+
+func tagValue(tags interface{}) string {
+	switch tags := tags.(type) {
+	case map[string]interface{}:
+		for _, key := range tagKeys {
+			if value, ok := tags[key].(string); ok && value != "" {
+				return value
+			}
+		}
+	case []interface{}:
+		for _, tag := range tags {
+			pair, ok := tag.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key, _ := pair["key"].(string)
+			value, _ := pair["value"].(string)
+			for _, tagKey := range tagKeys {
+				if key == tagKey && value != "" {
+					return value
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package maintenancewindow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+)
+
+// WindowSyntax describes the maintenance window format, for help texts.
+const WindowSyntax = `"<days> <start>-<end> [time zone]", where days are weekdays (e.g. Sun, Sat,Sun or Mon-Fri) or "daily", start and end are HH:MM, and the time zone is an IANA name defaulting to UTC, e.g. "Sun 02:00-04:00 Europe/Berlin"`
+
+// Window is a recurring maintenance window, such as Sundays from 02:00 to 04:00 in Europe/Berlin. A window may end
+// after midnight, on the next day.
+type Window struct {
+	Spec        string
+	Days        [7]bool
+	StartHour   int
+	StartMinute int
+	Duration    time.Duration
+	Location    *time.Location
+}
+
+// Parse parses a maintenance window in the WindowSyntax format.
+func Parse(spec string) (*Window, error) {
+	// Accept en dashes as pasted from contracts, e.g. 02:00–04:00
+	fields := strings.Fields(strings.ReplaceAll(spec, "–", "-"))
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, fmt.Errorf("invalid maintenance window %q, expected %s", spec, WindowSyntax)
+	}
+
+	window := &Window{Spec: strings.TrimSpace(spec), Location: time.UTC}
+	if err := window.parseDays(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid maintenance window %q: %w", spec, err)
+	}
+
+	startPart, endPart, found := strings.Cut(fields[1], "-")
+	if !found {
+		return nil, fmt.Errorf("invalid maintenance window %q, expected a time range such as 02:00-04:00", spec)
+	}
+	startHour, startMinute, err := utils.ParseClock(startPart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window %q: %w", spec, err)
+	}
+	endHour, endMinute, err := utils.ParseClock(endPart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window %q: %w", spec, err)
+	}
+	window.StartHour, window.StartMinute = startHour, startMinute
+
+	start := time.Duration(startHour)*time.Hour + time.Duration(startMinute)*time.Minute
+	end := time.Duration(endHour)*time.Hour + time.Duration(endMinute)*time.Minute
+	if end <= start {
+		end += 24 * time.Hour
+	}
+	window.Duration = end - start
+
+	if len(fields) == 3 {
+		location, err := time.LoadLocation(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window %q: unknown time zone %s", spec, fields[2])
+		}
+		window.Location = location
+	}
+
+	return window, nil
+}
+
+// parseDays parses a comma-separated list of weekdays or weekday ranges, or "daily". Plurals such as "Sundays" are
+// accepted.
+func (w *Window) parseDays(s string) error {
+	if strings.EqualFold(s, "daily") {
+		for i := range w.Days {
+			w.Days[i] = true
+		}
+		return nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		fromPart, toPart, isRange := strings.Cut(part, "-")
+		from, err := parsePluralWeekday(fromPart)
+		if err != nil {
+			return err
+		}
+		to := from
+		if isRange {
+			if to, err = parsePluralWeekday(toPart); err != nil {
+				return err
+			}
+		}
+		for day := from; ; day = (day + 1) % 7 {
+			w.Days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func parsePluralWeekday(s string) (time.Weekday, error) {
+	if weekday, err := utils.ParseWeekday(s); err == nil {
+		return weekday, nil
+	}
+	return utils.ParseWeekday(strings.TrimSuffix(strings.ToLower(s), "s"))
+}
+
+// Next returns the start of the first window that starts at or after t.
+func (w *Window) Next(t time.Time) time.Time {
+	local := t.In(w.Location)
+	for days := 0; days <= 7; days++ {
+		day := local.AddDate(0, 0, days)
+		start := time.Date(day.Year(), day.Month(), day.Day(), w.StartHour, w.StartMinute, 0, 0, w.Location)
+		if w.Days[start.Weekday()] && !start.Before(t) {
+			return start
+		}
+	}
+	// Unreachable as long as the window has at least one day
+	return t
+}
+
+func (w *Window) String() string {
+	return w.Spec
+}
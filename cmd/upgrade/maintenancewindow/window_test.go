@@ -0,0 +1,139 @@
+package maintenancewindow
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		days     []time.Weekday
+		duration time.Duration
+		location string
+		wantErr  bool
+	}{
+		{name: "single day", spec: "Sun 02:00-04:00 Europe/Berlin", days: []time.Weekday{time.Sunday}, duration: 2 * time.Hour, location: "Europe/Berlin"},
+		{name: "plural day and en dash", spec: "Sundays 02:00–04:00 Europe/Berlin", days: []time.Weekday{time.Sunday}, duration: 2 * time.Hour, location: "Europe/Berlin"},
+		{name: "list of days in UTC", spec: "Sat,Sun 23:00-01:30", days: []time.Weekday{time.Saturday, time.Sunday}, duration: 150 * time.Minute, location: "UTC"},
+		{name: "range of days", spec: "mon-fri 22:00-23:00", days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, duration: time.Hour, location: "UTC"},
+		{name: "range across the week", spec: "Fri-Mon 22:00-23:00", days: []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}, duration: time.Hour, location: "UTC"},
+		{name: "daily", spec: "daily 03:00-04:00", days: []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}, duration: time.Hour, location: "UTC"},
+		{name: "missing range", spec: "Sun", wantErr: true},
+		{name: "invalid day", spec: "Someday 02:00-04:00", wantErr: true},
+		{name: "invalid time", spec: "Sun 2am-4am", wantErr: true},
+		{name: "unknown time zone", spec: "Sun 02:00-04:00 Mars/Olympus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			window, err := Parse(tt.spec)
+			if tt.wantErr {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			var days []time.Weekday
+			for day, set := range window.Days {
+				if set {
+					days = append(days, time.Weekday(day))
+				}
+			}
+			require.ElementsMatch(tt.days, days)
+			require.Equal(tt.duration, window.Duration)
+			require.Equal(tt.location, window.Location.String())
+		})
+	}
+}
+
+func TestWindowNext(t *testing.T) {
+	require := require.New(t)
+
+	window, err := Parse("Sun 02:00-04:00 Europe/Berlin")
+	require.NoError(err)
+	berlin := window.Location
+
+	// Wednesday
+	next := window.Next(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+	require.True(time.Date(2025, 1, 19, 2, 0, 0, 0, berlin).Equal(next), next)
+
+	// At the start of the window
+	next = window.Next(time.Date(2025, 1, 19, 2, 0, 0, 0, berlin))
+	require.True(time.Date(2025, 1, 19, 2, 0, 0, 0, berlin).Equal(next), next)
+
+	// During the window, the next one starts a week later
+	next = window.Next(time.Date(2025, 1, 19, 3, 0, 0, 0, berlin))
+	require.True(time.Date(2025, 1, 26, 2, 0, 0, 0, berlin).Equal(next), next)
+}
+
+func TestStore(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "config", DefaultFile)
+
+	store, err := Load(path)
+	require.NoError(err)
+	require.Empty(store.Customers)
+
+	store.SetCustomer("Admin@Example.com", "Sun 02:00-04:00 Europe/Berlin")
+	store.SetSubscription("sub-1", "Sat 22:00-23:00")
+	require.NoError(store.Save(path))
+
+	store, err = Load(path)
+	require.NoError(err)
+
+	window, source := store.Lookup("", "sub-2", "admin@example.com")
+	require.Equal("Sun 02:00-04:00 Europe/Berlin", window)
+	require.Equal("customer admin@example.com", source)
+
+	window, source = store.Lookup("", "sub-1", "admin@example.com")
+	require.Equal("Sat 22:00-23:00", window)
+	require.Equal("subscription sub-1", source)
+
+	window, source = store.Lookup("daily 01:00-02:00", "sub-1", "admin@example.com")
+	require.Equal("daily 01:00-02:00", window)
+	require.Equal("instance tag", source)
+
+	window, _ = store.Lookup("", "", "")
+	require.Empty(window)
+
+	windows := formatMaintenanceWindows(store, time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+	require.Len(windows, 2)
+	require.Equal(scopeSubscription, windows[0].Scope)
+	require.Equal("2025-01-18T22:00:00Z", windows[0].NextWindowStart)
+	require.Equal(scopeCustomer, windows[1].Scope)
+	require.Equal("admin@example.com", windows[1].Target)
+
+	require.True(store.DeleteCustomer("ADMIN@example.com"))
+	require.False(store.DeleteCustomer("admin@example.com"))
+	require.True(store.DeleteSubscription("sub-1"))
+}
+
+func TestInstanceTagWindow(t *testing.T) {
+	require := require.New(t)
+
+	require.Empty(InstanceTagWindow(nil))
+	require.Empty(InstanceTagWindow(&openapiclientfleet.ResourceInstance{}))
+
+	instance := &openapiclientfleet.ResourceInstance{}
+	instance.ConsumptionResourceInstanceResult.AdditionalProperties = map[string]interface{}{
+		"tags": map[string]interface{}{"maintenance-window": "Sun 02:00-04:00"},
+	}
+	require.Equal("Sun 02:00-04:00", InstanceTagWindow(instance))
+
+	instance = &openapiclientfleet.ResourceInstance{AdditionalProperties: map[string]interface{}{
+		"customTags": []interface{}{
+			map[string]interface{}{"key": "team", "value": "data"},
+			map[string]interface{}{"key": "maintenance_window", "value": "daily 01:00-02:00"},
+		},
+	}}
+	require.Equal("daily 01:00-02:00", InstanceTagWindow(instance))
+}
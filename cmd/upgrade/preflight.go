@@ -0,0 +1,360 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	openapiclient "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+)
+
+// Severities of preflight issues
+const (
+	severityBlocking = "blocking"
+	severityWarning  = "warning"
+)
+
+// Categories of preflight issues
+const (
+	categoryVersion   = "version"
+	categoryResource  = "resource"
+	categoryParameter = "parameter"
+)
+
+// preflightTarget is an instance to check before upgrading it.
+type preflightTarget struct {
+	InstanceID    string
+	ServiceID     string
+	ProductTierID string
+	ResourceID    string
+	SourceVersion string
+	TargetVersion string
+	Params        map[string]interface{}
+}
+
+// versionDefinitions are the definitions of the source and target versions an instance is checked against.
+type versionDefinitions struct {
+	SourceResources     []openapiclient.DescribeResourceResult
+	TargetResources     []openapiclient.DescribeResourceResult
+	SourceParams        []openapiclientfleet.InputParameterEntity
+	TargetParams        []openapiclientfleet.InputParameterEntity
+	TargetVersionStatus string
+}
+
+// preflightChecker fetches the version definitions of the instances to check, once per plan, resource and version.
+type preflightChecker struct {
+	ctx   context.Context
+	token string
+
+	resources map[string][]openapiclient.DescribeResourceResult
+	params    map[string][]openapiclientfleet.InputParameterEntity
+	statuses  map[string]string
+}
+
+func newPreflightChecker(ctx context.Context, token string) *preflightChecker {
+	return &preflightChecker{
+		ctx:       ctx,
+		token:     token,
+		resources: make(map[string][]openapiclient.DescribeResourceResult),
+		params:    make(map[string][]openapiclientfleet.InputParameterEntity),
+		statuses:  make(map[string]string),
+	}
+}
+
+// check returns the issues found upgrading the instances, ordered by instance with blocking issues first.
+func (c *preflightChecker) check(targets []preflightTarget) ([]model.UpgradeCheckIssue, error) {
+	issues := make([]model.UpgradeCheckIssue, 0)
+	for _, target := range targets {
+		definitions, err := c.definitions(target)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, checkInstanceUpgrade(target, definitions)...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].InstanceID != issues[j].InstanceID {
+			return issues[i].InstanceID < issues[j].InstanceID
+		}
+		return issues[i].Severity == severityBlocking && issues[j].Severity != severityBlocking
+	})
+	return issues, nil
+}
+
+func (c *preflightChecker) definitions(target preflightTarget) (definitions versionDefinitions, err error) {
+	planKey := target.ServiceID + "/" + target.ProductTierID
+
+	status, ok := c.statuses[planKey+"/"+target.TargetVersion]
+	if !ok {
+		versionSet, err := dataaccess.DescribeVersionSet(c.ctx, c.token, target.ServiceID, target.ProductTierID, target.TargetVersion)
+		if err != nil {
+			return definitions, err
+		}
+		status = versionSet.Status
+		c.statuses[planKey+"/"+target.TargetVersion] = status
+	}
+	definitions.TargetVersionStatus = status
+
+	if definitions.SourceResources, err = c.versionResources(target.ServiceID, target.ProductTierID, target.SourceVersion); err != nil {
+		return
+	}
+	if definitions.TargetResources, err = c.versionResources(target.ServiceID, target.ProductTierID, target.TargetVersion); err != nil {
+		return
+	}
+
+	if target.ResourceID == "" {
+		return
+	}
+	if definitions.SourceParams, err = c.createParams(target, target.SourceVersion); err != nil {
+		return
+	}
+	definitions.TargetParams, err = c.createParams(target, target.TargetVersion)
+	return
+}
+
+func (c *preflightChecker) versionResources(serviceID, productTierID, version string) ([]openapiclient.DescribeResourceResult, error) {
+	key := serviceID + "/" + productTierID + "/" + version
+	if resources, ok := c.resources[key]; ok {
+		return resources, nil
+	}
+
+	res, err := dataaccess.ListResources(c.ctx, c.token, serviceID, productTierID, &version)
+	if err != nil {
+		return nil, err
+	}
+	c.resources[key] = res.Resources
+	return res.Resources, nil
+}
+
+// createParams returns the input parameters of the create API of the resource of the instance in a version.
+func (c *preflightChecker) createParams(target preflightTarget, version string) ([]openapiclientfleet.InputParameterEntity, error) {
+	key := target.ServiceID + "/" + target.ProductTierID + "/" + target.ResourceID + "/" + version
+	if params, ok := c.params[key]; ok {
+		return params, nil
+	}
+
+	res, err := dataaccess.DescribeServiceOfferingResource(c.ctx, c.token, target.ServiceID, target.ResourceID, target.InstanceID, target.ProductTierID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]openapiclientfleet.InputParameterEntity, 0)
+	if res.ConsumptionDescribeServiceOfferingResourceResult != nil {
+		for _, api := range res.ConsumptionDescribeServiceOfferingResourceResult.Apis {
+			if strings.EqualFold(api.Verb, "CREATE") {
+				params = api.InputParameters
+				break
+			}
+		}
+	}
+	c.params[key] = params
+	return params, nil
+}
+
+// checkInstanceUpgrade compares the parameters and resources of an instance between the source and target versions.
+// Changes the upgrade can't go through, such as a parameter the instance uses being removed or changing type, are
+// blocking. Other changes worth reviewing are warnings.
+func checkInstanceUpgrade(target preflightTarget, definitions versionDefinitions) []model.UpgradeCheckIssue {
+	issues := make([]model.UpgradeCheckIssue, 0)
+	addIssue := func(severity, category, subject, message string) {
+		issues = append(issues, model.UpgradeCheckIssue{
+			InstanceID:    target.InstanceID,
+			SourceVersion: target.SourceVersion,
+			TargetVersion: target.TargetVersion,
+			Severity:      severity,
+			Category:      category,
+			Subject:       subject,
+			Message:       message,
+		})
+	}
+
+	if strings.EqualFold(definitions.TargetVersionStatus, "Deprecated") {
+		addIssue(severityWarning, categoryVersion, target.TargetVersion, "target version is deprecated")
+	}
+
+	// Resources
+	sourceResources := resourcesByKey(definitions.SourceResources)
+	targetResources := resourcesByKey(definitions.TargetResources)
+	for _, key := range sortedKeys(sourceResources) {
+		source := sourceResources[key]
+		targetResource, found := targetResources[key]
+		switch {
+		case !found:
+			addIssue(severityBlocking, categoryResource, source.Name, "resource is removed in the target version")
+		case !strings.EqualFold(source.ResourceType, targetResource.ResourceType):
+			addIssue(severityBlocking, categoryResource, source.Name, fmt.Sprintf("resource type changes from %s to %s", source.ResourceType, targetResource.ResourceType))
+		case targetResource.IsDeprecated && !source.IsDeprecated:
+			addIssue(severityWarning, categoryResource, source.Name, "resource is deprecated in the target version")
+		}
+	}
+	for _, key := range sortedKeys(targetResources) {
+		if _, found := sourceResources[key]; !found {
+			addIssue(severityWarning, categoryResource, targetResources[key].Name, "resource is added in the target version")
+		}
+	}
+
+	// Parameters, only when the definitions of the create API of the instance resource are known
+	if len(definitions.TargetParams) == 0 && len(definitions.SourceParams) == 0 {
+		return issues
+	}
+	sourceParams := paramsByKey(definitions.SourceParams)
+	targetParams := paramsByKey(definitions.TargetParams)
+
+	for _, key := range sortedKeys(target.Params) {
+		value := target.Params[key]
+		targetParam, found := targetParams[key]
+		source, inSource := sourceParams[key]
+		switch {
+		case !found && !inSource:
+			// Not an input of the create API in either version, such as system or derived parameters
+		case !found && source.Required:
+			addIssue(severityBlocking, categoryParameter, key, "required parameter is removed in the target version")
+		case !found:
+			addIssue(severityBlocking, categoryParameter, key, "parameter set on the instance is removed in the target version")
+		case inSource && !strings.EqualFold(source.Type, targetParam.Type):
+			addIssue(severityBlocking, categoryParameter, key, fmt.Sprintf("parameter type changes from %s to %s", source.Type, targetParam.Type))
+		case !valueMatchesType(value, targetParam):
+			addIssue(severityBlocking, categoryParameter, key, fmt.Sprintf("value %s isn't a valid %s", formatParamValue(value), targetParam.Type))
+		case len(targetParam.Options) > 0 && !targetParam.IsList && !slices.Contains(targetParam.Options, formatParamValue(value)):
+			addIssue(severityBlocking, categoryParameter, key, fmt.Sprintf("value %s isn't one of the allowed options %s", formatParamValue(value), strings.Join(targetParam.Options, ", ")))
+		case inSource && source.Modifiable && !targetParam.Modifiable:
+			addIssue(severityWarning, categoryParameter, key, "parameter can't be modified anymore in the target version")
+		}
+	}
+
+	for _, key := range sortedKeys(targetParams) {
+		param := targetParams[key]
+		if _, set := target.Params[key]; set {
+			continue
+		}
+		source, inSource := sourceParams[key]
+		switch {
+		case param.Required && param.DefaultValue == nil:
+			addIssue(severityBlocking, categoryParameter, key, "required parameter without default value is missing on the instance")
+		case param.Required && (!inSource || !source.Required):
+			addIssue(severityWarning, categoryParameter, key, fmt.Sprintf("parameter becomes required and takes the default value %s", *param.DefaultValue))
+		case !inSource:
+			addIssue(severityWarning, categoryParameter, key, "parameter is added in the target version")
+		}
+	}
+
+	return issues
+}
+
+// hasBlockingIssues reports whether any of the issues blocks the upgrade.
+func hasBlockingIssues(issues []model.UpgradeCheckIssue) bool {
+	return slices.ContainsFunc(issues, func(issue model.UpgradeCheckIssue) bool {
+		return issue.Severity == severityBlocking
+	})
+}
+
+// summarizeIssues counts the blocking issues and warnings, e.g. "2 blocking issues and 1 warning in 2 instances".
+func summarizeIssues(issues []model.UpgradeCheckIssue) string {
+	var blocking, warnings int
+	instances := make(map[string]bool)
+	for _, issue := range issues {
+		instances[issue.InstanceID] = true
+		if issue.Severity == severityBlocking {
+			blocking++
+		} else {
+			warnings++
+		}
+	}
+	return fmt.Sprintf("%s and %s in %s", plural(blocking, "blocking issue"), plural(warnings, "warning"), plural(len(instances), "instance"))
+}
+
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+func resourcesByKey(resources []openapiclient.DescribeResourceResult) map[string]openapiclient.DescribeResourceResult {
+	byKey := make(map[string]openapiclient.DescribeResourceResult, len(resources))
+	for _, resource := range resources {
+		key := resource.Key
+		if key == "" {
+			key = resource.Name
+		}
+		byKey[key] = resource
+	}
+	return byKey
+}
+
+func paramsByKey(params []openapiclientfleet.InputParameterEntity) map[string]openapiclientfleet.InputParameterEntity {
+	byKey := make(map[string]openapiclientfleet.InputParameterEntity, len(params))
+	for _, param := range params {
+		byKey[param.Key] = param
+	}
+	return byKey
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// valueMatchesType reports whether an instance parameter value is valid for a parameter type. Values are often sent
+// as strings, so numbers and booleans are also accepted in their string form.
+func valueMatchesType(value interface{}, param openapiclientfleet.InputParameterEntity) bool {
+	if value == nil {
+		return true
+	}
+	if param.IsList {
+		if values, ok := value.([]interface{}); ok {
+			for _, v := range values {
+				if !valueMatchesType(v, openapiclientfleet.InputParameterEntity{Type: param.Type}) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	s := formatParamValue(value)
+	switch strings.ToLower(param.Type) {
+	case "float64", "float", "number":
+		_, err := strconv.ParseFloat(s, 64)
+		return err == nil
+	case "int64", "int", "integer":
+		_, err := strconv.ParseInt(s, 10, 64)
+		return err == nil
+	case "uint64", "uint":
+		_, err := strconv.ParseUint(s, 10, 64)
+		return err == nil
+	case "boolean", "bool":
+		_, err := strconv.ParseBool(s)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+func formatParamValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,127 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	openapiclient "github.com/omnistrate-oss/omnistrate-sdk-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInstanceUpgrade(t *testing.T) {
+	require := require.New(t)
+
+	target := preflightTarget{
+		InstanceID:    "instance-1",
+		SourceVersion: "1.0",
+		TargetVersion: "2.0",
+		Params: map[string]interface{}{
+			"name":     "db",
+			"replicas": "3",
+			"engine":   "postgres",
+			"legacy":   "on",
+			"size":     "large",
+			"mode":     "fast",
+			"timeout":  float64(30),
+			"port":     "5432",
+			"cluster":  "internal-1", // not an input of the create API in either version
+		},
+	}
+	definitions := versionDefinitions{
+		TargetVersionStatus: "Deprecated",
+		SourceResources: []openapiclient.DescribeResourceResult{
+			{Key: "db", Name: "Database", ResourceType: "OmnistrateManaged"},
+			{Key: "cache", Name: "Cache", ResourceType: "OmnistrateManaged"},
+			{Key: "proxy", Name: "Proxy", ResourceType: "OmnistrateManaged"},
+		},
+		TargetResources: []openapiclient.DescribeResourceResult{
+			{Key: "db", Name: "Database", ResourceType: "OmnistrateManaged"},
+			{Key: "cache", Name: "Cache", ResourceType: "Helm"},
+			{Key: "metrics", Name: "Metrics", ResourceType: "OmnistrateManaged"},
+		},
+		SourceParams: []openapiclientfleet.InputParameterEntity{
+			{Key: "name", Type: "String", Required: true},
+			{Key: "replicas", Type: "String"},
+			{Key: "engine", Type: "String"},
+			{Key: "legacy", Type: "String", Required: true},
+			{Key: "size", Type: "String", Modifiable: true},
+			{Key: "mode", Type: "String"},
+			{Key: "timeout", Type: "Float64"},
+			{Key: "region", Type: "String", DefaultValue: utils.ToPtr("us-east-1")},
+			{Key: "port", Type: "String"},
+		},
+		TargetParams: []openapiclientfleet.InputParameterEntity{
+			{Key: "name", Type: "String", Required: true},
+			{Key: "replicas", Type: "Float64"},
+			{Key: "engine", Type: "String", Options: []string{"postgres", "mysql"}},
+			{Key: "size", Type: "String"},
+			{Key: "mode", Type: "String", Options: []string{"safe"}},
+			{Key: "timeout", Type: "Float64"},
+			{Key: "region", Type: "String", Required: true, DefaultValue: utils.ToPtr("us-east-1")},
+			{Key: "password", Type: "Password", Required: true},
+			{Key: "tier", Type: "String"},
+		},
+	}
+
+	issues := checkInstanceUpgrade(target, definitions)
+
+	type issueKey struct{ severity, category, subject string }
+	found := make(map[issueKey]string)
+	for _, issue := range issues {
+		require.Equal("instance-1", issue.InstanceID)
+		require.Equal("1.0", issue.SourceVersion)
+		require.Equal("2.0", issue.TargetVersion)
+		found[issueKey{issue.Severity, issue.Category, issue.Subject}] = issue.Message
+	}
+
+	require.Equal(map[issueKey]string{
+		{severityWarning, categoryVersion, "2.0"}:         "target version is deprecated",
+		{severityBlocking, categoryResource, "Cache"}:     "resource type changes from OmnistrateManaged to Helm",
+		{severityBlocking, categoryResource, "Proxy"}:     "resource is removed in the target version",
+		{severityWarning, categoryResource, "Metrics"}:    "resource is added in the target version",
+		{severityBlocking, categoryParameter, "legacy"}:   "required parameter is removed in the target version",
+		{severityBlocking, categoryParameter, "port"}:     "parameter set on the instance is removed in the target version",
+		{severityBlocking, categoryParameter, "mode"}:     "value fast isn't one of the allowed options safe",
+		{severityBlocking, categoryParameter, "replicas"}: "parameter type changes from String to Float64",
+		{severityWarning, categoryParameter, "size"}:      "parameter can't be modified anymore in the target version",
+		{severityBlocking, categoryParameter, "password"}: "required parameter without default value is missing on the instance",
+		{severityWarning, categoryParameter, "region"}:    "parameter becomes required and takes the default value us-east-1",
+		{severityWarning, categoryParameter, "tier"}:      "parameter is added in the target version",
+	}, found)
+
+	require.True(hasBlockingIssues(issues))
+	require.Equal("7 blocking issues and 5 warnings in 1 instance", summarizeIssues(issues))
+}
+
+func TestCheckInstanceUpgradeWithoutParameterDefinitions(t *testing.T) {
+	require := require.New(t)
+
+	target := preflightTarget{InstanceID: "instance-1", Params: map[string]interface{}{"name": "db"}}
+	definitions := versionDefinitions{
+		TargetVersionStatus: "Active",
+		SourceResources:     []openapiclient.DescribeResourceResult{{Key: "db", Name: "Database", ResourceType: "Helm"}},
+		TargetResources:     []openapiclient.DescribeResourceResult{{Key: "db", Name: "Database", ResourceType: "Helm"}},
+	}
+
+	issues := checkInstanceUpgrade(target, definitions)
+	require.Empty(issues)
+	require.False(hasBlockingIssues(issues))
+	require.False(hasBlockingIssues([]model.UpgradeCheckIssue{{Severity: severityWarning}}))
+}
+
+func TestValueMatchesType(t *testing.T) {
+	require := require.New(t)
+
+	require.True(valueMatchesType("3", openapiclientfleet.InputParameterEntity{Type: "Float64"}))
+	require.True(valueMatchesType(float64(3), openapiclientfleet.InputParameterEntity{Type: "Float64"}))
+	require.False(valueMatchesType("three", openapiclientfleet.InputParameterEntity{Type: "Float64"}))
+	require.True(valueMatchesType("true", openapiclientfleet.InputParameterEntity{Type: "Boolean"}))
+	require.False(valueMatchesType("yes", openapiclientfleet.InputParameterEntity{Type: "Boolean"}))
+	require.False(valueMatchesType("-1", openapiclientfleet.InputParameterEntity{Type: "Uint64"}))
+	require.True(valueMatchesType([]interface{}{"1", "2"}, openapiclientfleet.InputParameterEntity{Type: "Int64", IsList: true}))
+	require.False(valueMatchesType([]interface{}{"1", "x"}, openapiclientfleet.InputParameterEntity{Type: "Int64", IsList: true}))
+	require.True(valueMatchesType(map[string]interface{}{"a": 1}, openapiclientfleet.InputParameterEntity{Type: "JSON"}))
+	require.True(valueMatchesType(nil, openapiclientfleet.InputParameterEntity{Type: "Float64"}))
+}
@@ -0,0 +1,330 @@
+package rollback
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chelnak/ysmrr"
+	"github.com/cqroot/prompt"
+	"github.com/cqroot/prompt/input"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	rollbackExample = `# Roll back the instances upgraded or failed by an upgrade to their original version
+omctl upgrade rollback [upgrade-id]
+
+# Roll back only the instances that failed the upgrade, without confirmation
+omctl upgrade rollback [upgrade-id] --failed-only --yes
+
+# Roll back the instances of an upgrade next Sunday at 02:00 Berlin time
+omctl upgrade rollback [upgrade-id] --scheduled-date="next sunday 02:00" --timezone=Europe/Berlin`
+)
+
+var Cmd = &cobra.Command{
+	Use:   "rollback [upgrade-id] [flags]",
+	Short: "Roll back the instances of an upgrade to their original version",
+	Long: `This command creates a reverse upgrade for the instances of an upgrade, back to the source version of the
+upgrade. By default, the instances that completed or failed the upgrade are rolled back. With --failed-only, only the
+failed instances are rolled back.
+
+Instances are grouped by their current version, so one rollback upgrade is created per version. Instances already at
+the source version, and instances that were skipped or didn't start the upgrade, are left out. The output links each
+instance to the upgrade it is rolled back from and the rollback upgrade.`,
+	Example:      rollbackExample,
+	RunE:         run,
+	SilenceUsage: true,
+}
+
+// Instance upgrade statuses that can be rolled back.
+var (
+	rollbackStatuses   = []string{model.Complete.String(), model.Failed.String()}
+	failedOnlyStatuses = []string{model.Failed.String()}
+
+	// Upgrade path statuses that can't be rolled back yet
+	activeUpgradeStatuses = []string{model.InProgress.String(), model.Scheduled.String(), model.Verifying.String()}
+)
+
+func init() {
+	Cmd.Args = cobra.ExactArgs(1)
+
+	Cmd.Flags().Bool("failed-only", false, "Only roll back the instances that failed the upgrade")
+	Cmd.Flags().StringP("scheduled-date", "", "", "Specify the scheduled date for the rollback, as "+utils.ScheduledDateSyntax+".")
+	Cmd.Flags().String("timezone", "", "Time zone of --scheduled-date when it has none, e.g. Europe/Berlin. Defaults to the local time zone")
+	Cmd.Flags().Bool("notify-customer", false, "Enable customer notifications for the rollback")
+	Cmd.Flags().BoolP("yes", "y", false, "Pre-approve the rollback without prompting for confirmation")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	defer config.CleanupArgsAndFlags(cmd, &args)
+
+	// Retrieve args
+	upgradePathID := args[0]
+
+	// Retrieve flags
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	failedOnly, _ := cmd.Flags().GetBool("failed-only")
+	notifyCustomer, _ := cmd.Flags().GetBool("notify-customer")
+	yes, _ := cmd.Flags().GetBool("yes")
+	scheduledDateParam, _ := cmd.Flags().GetString("scheduled-date")
+	timezone, _ := cmd.Flags().GetString("timezone")
+
+	// Validate input arguments
+	scheduledDate, _, err := utils.ParseScheduledDateFlag(scheduledDateParam, timezone, time.Now())
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	if output == "json" && !yes {
+		err = errors.New("--yes is required to roll back an upgrade with json output")
+		utils.PrintError(err)
+		return err
+	}
+
+	// Validate user login
+	token, err := common.GetTokenWithLogin()
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+
+	// Initialize spinner if output is not json
+	var sm ysmrr.SpinnerManager
+	var spinner *ysmrr.Spinner
+	if output != "json" {
+		sm = ysmrr.NewSpinnerManager()
+		spinner = sm.AddSpinner(fmt.Sprintf("Finding the instances of %s to roll back", upgradePathID))
+		sm.Start()
+	}
+
+	// Find the upgrade path
+	searchRes, err := dataaccess.SearchInventory(cmd.Context(), token, fmt.Sprintf("upgradepath:%s", upgradePathID))
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	var serviceID, productTierID string
+	for _, upgradePath := range searchRes.UpgradePathResults {
+		if upgradePath.Id == upgradePathID {
+			serviceID = upgradePath.ServiceId
+			productTierID = upgradePath.ProductTierID
+			break
+		}
+	}
+	if serviceID == "" {
+		err = fmt.Errorf("%s not found", upgradePathID)
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	upgradePath, err := dataaccess.DescribeUpgradePath(cmd.Context(), token, serviceID, productTierID, upgradePathID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+	if slices.Contains(activeUpgradeStatuses, upgradePath.Status) {
+		err = fmt.Errorf("upgrade %s is %s. Wait for it to finish or cancel it with 'omctl upgrade cancel %s' before rolling it back", upgradePathID, upgradePath.Status, upgradePathID)
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	instanceUpgrades, err := dataaccess.ListEligibleInstancesPerUpgrade(cmd.Context(), token, serviceID, productTierID, upgradePathID)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	// Find the current version of the instances
+	instancesRes, err := dataaccess.SearchInventory(cmd.Context(), token, "resourceinstance:i")
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+	currentVersions := currentInstanceVersions(instancesRes.ResourceInstanceResults)
+
+	rows, groups := planRollback(upgradePath, instanceUpgrades, currentVersions, failedOnly)
+	if len(groups) == 0 {
+		err = fmt.Errorf("no instances of %s to roll back", upgradePathID)
+		utils.HandleSpinnerError(spinner, sm, err)
+		if output != "json" {
+			_ = utils.PrintTextTableJsonArrayOutput(output, rows)
+		}
+		return err
+	}
+
+	// Preview the rollback and confirm
+	if !yes {
+		utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("Found %d instances to roll back to version %s", countInstances(groups), upgradePath.SourceVersion))
+
+		err = utils.PrintTextTableJsonArrayOutput(output, rows)
+		if err != nil {
+			return err
+		}
+
+		ok, err := prompt.New().Ask(fmt.Sprintf("Are you sure you want to roll back these %d instances to version %s? (y/n)", countInstances(groups), upgradePath.SourceVersion)).
+			Input("", input.WithValidateFunc(
+				func(input string) error {
+					if slices.Contains([]string{"y", "yes", "n", "no"}, strings.ToLower(input)) {
+						return nil
+					} else {
+						return errors.New("invalid input")
+					}
+				}))
+		if err != nil {
+			utils.PrintError(err)
+			return err
+		}
+
+		if !slices.Contains([]string{"y", "yes"}, strings.ToLower(ok)) {
+			return nil
+		}
+
+		if output != "json" {
+			sm = ysmrr.NewSpinnerManager()
+			spinner = sm.AddSpinner(fmt.Sprintf("Scheduling rollback for %d instances", countInstances(groups)))
+			sm.Start()
+		}
+	}
+
+	// Create one reverse upgrade path per current version
+	rollbackUpgradeIDs := make(map[string]string)
+	for _, currentVersion := range sortedKeys(groups) {
+		rollbackUpgradeID, err := dataaccess.CreateUpgradePath(
+			cmd.Context(),
+			token,
+			serviceID,
+			productTierID,
+			currentVersion,
+			upgradePath.SourceVersion,
+			scheduledDate,
+			groups[currentVersion],
+			notifyCustomer,
+		)
+		if err != nil {
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+		rollbackUpgradeIDs[currentVersion] = rollbackUpgradeID
+	}
+
+	for i := range rows {
+		if rows[i].Note == "" {
+			rows[i].RollbackUpgradeID = rollbackUpgradeIDs[rows[i].CurrentVersion]
+		}
+	}
+
+	utils.HandleSpinnerSuccess(spinner, sm, "Rollback scheduled successfully")
+
+	if output != "json" {
+		fmt.Printf("\nThe following instances of upgrade %s are being rolled back:\n", upgradePathID)
+	}
+
+	err = utils.PrintTextTableJsonArrayOutput(output, rows)
+	if err != nil {
+		return err
+	}
+
+	if output != "json" {
+		println("\nCheck the rollback status using the following command(s):")
+		for _, currentVersion := range sortedKeys(groups) {
+			fmt.Printf("  omctl upgrade status %s\n", rollbackUpgradeIDs[currentVersion])
+		}
+	}
+
+	return nil
+}
+
+// currentInstanceVersions returns the current version of each instance by instance ID.
+func currentInstanceVersions(instances []openapiclientfleet.ResourceInstanceSearchRecord) map[string]string {
+	versions := make(map[string]string, len(instances))
+	for _, instance := range instances {
+		if instance.ProductTierVersion != nil {
+			versions[instance.Id] = *instance.ProductTierVersion
+		}
+	}
+	return versions
+}
+
+// planRollback returns a row for each instance of the upgrade, and the IDs of the instances to roll back to the source
+// version of the upgrade, grouped by their current version. Instances that aren't rolled back have a note saying why.
+func planRollback(
+	upgradePath *openapiclientfleet.UpgradePath,
+	instanceUpgrades []openapiclientfleet.InstanceUpgrade,
+	currentVersions map[string]string,
+	failedOnly bool,
+) ([]model.UpgradeRollbackInstance, map[string][]string) {
+	statuses := rollbackStatuses
+	if failedOnly {
+		statuses = failedOnlyStatuses
+	}
+
+	rows := make([]model.UpgradeRollbackInstance, 0, len(instanceUpgrades))
+	groups := make(map[string][]string)
+	for _, instanceUpgrade := range instanceUpgrades {
+		row := model.UpgradeRollbackInstance{
+			UpgradeID:       upgradePath.UpgradePathId,
+			InstanceID:      instanceUpgrade.InstanceId,
+			UpgradeStatus:   instanceUpgrade.Status,
+			RollbackVersion: upgradePath.SourceVersion,
+		}
+
+		currentVersion, found := currentVersions[instanceUpgrade.InstanceId]
+		row.CurrentVersion = currentVersion
+		switch {
+		case !slices.Contains(statuses, strings.ToUpper(instanceUpgrade.Status)):
+			row.Note = fmt.Sprintf("skipped: upgrade status is %s", instanceUpgrade.Status)
+		case !found:
+			row.Note = "skipped: instance not found"
+		case currentVersion == upgradePath.SourceVersion:
+			row.Note = "skipped: already at version " + currentVersion
+		default:
+			groups[currentVersion] = append(groups[currentVersion], instanceUpgrade.InstanceId)
+		}
+
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if (rows[i].Note == "") != (rows[j].Note == "") {
+			return rows[i].Note == ""
+		}
+		return rows[i].InstanceID < rows[j].InstanceID
+	})
+	for _, instanceIDs := range groups {
+		slices.Sort(instanceIDs)
+	}
+
+	return rows, groups
+}
+
+func countInstances(groups map[string][]string) (count int) {
+	for _, instanceIDs := range groups {
+		count += len(instanceIDs)
+	}
+	return
+}
+
+func sortedKeys(groups map[string][]string) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,90 @@
+package rollback
+
+import (
+	"testing"
+
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRollback(t *testing.T) {
+	upgradePath := &openapiclientfleet.UpgradePath{
+		UpgradePathId: "upgrade-1",
+		SourceVersion: "1.0",
+		TargetVersion: "2.0",
+	}
+	instanceUpgrades := []openapiclientfleet.InstanceUpgrade{
+		{InstanceId: "instance-d", Status: "FAILED"},
+		{InstanceId: "instance-a", Status: "COMPLETE"},
+		{InstanceId: "instance-b", Status: "FAILED"},
+		{InstanceId: "instance-c", Status: "SKIPPED"},
+		{InstanceId: "instance-e", Status: "FAILED"},
+		{InstanceId: "instance-f", Status: "COMPLETE"},
+	}
+	currentVersions := map[string]string{
+		"instance-a": "2.0",
+		"instance-b": "2.0",
+		"instance-c": "1.0",
+		"instance-d": "1.5",
+		"instance-e": "1.0",
+	}
+
+	tests := []struct {
+		name          string
+		failedOnly    bool
+		expected      map[string][]string
+		expectedNotes map[string]string
+	}{
+		{
+			name:       "completed and failed instances",
+			failedOnly: false,
+			expected: map[string][]string{
+				"2.0": {"instance-a", "instance-b"},
+				"1.5": {"instance-d"},
+			},
+			expectedNotes: map[string]string{
+				"instance-c": "skipped: upgrade status is SKIPPED",
+				"instance-e": "skipped: already at version 1.0",
+				"instance-f": "skipped: instance not found",
+			},
+		},
+		{
+			name:       "failed instances only",
+			failedOnly: true,
+			expected: map[string][]string{
+				"2.0": {"instance-b"},
+				"1.5": {"instance-d"},
+			},
+			expectedNotes: map[string]string{
+				"instance-a": "skipped: upgrade status is COMPLETE",
+				"instance-c": "skipped: upgrade status is SKIPPED",
+				"instance-e": "skipped: already at version 1.0",
+				"instance-f": "skipped: upgrade status is COMPLETE",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			rows, groups := planRollback(upgradePath, instanceUpgrades, currentVersions, tt.failedOnly)
+			require.Equal(tt.expected, groups)
+			require.Len(rows, len(instanceUpgrades))
+			require.Equal(4, countInstances(map[string][]string{"a": {"1", "2"}, "b": {"3", "4"}}))
+
+			notes := make(map[string]string)
+			for i, row := range rows {
+				require.Equal("upgrade-1", row.UpgradeID)
+				require.Equal("1.0", row.RollbackVersion)
+				if row.Note != "" {
+					notes[row.InstanceID] = row.Note
+				} else {
+					// Instances to roll back come first
+					require.Empty(rows[max(i-1, 0)].Note)
+				}
+			}
+			require.Equal(tt.expectedNotes, notes)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package upgrade
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/upgrade/maintenancewindow"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+)
+
+// maintenanceScheduler computes the scheduled date of the upgrade of each instance from its maintenance window.
+// Instances without a maintenance window are scheduled at the default scheduled date.
+type maintenanceScheduler struct {
+	store *maintenancewindow.Store
+	// customerEmails are the emails of the subscription owners by subscription ID
+	customerEmails map[string]string
+	// notBefore is the time to look for the next maintenance window from
+	notBefore            time.Time
+	defaultScheduledDate *string
+
+	windows map[string]*maintenancewindow.Window
+	dates   map[string]*string
+}
+
+func newMaintenanceScheduler(store *maintenancewindow.Store, customerEmails map[string]string, notBefore time.Time, defaultScheduledDate *string) *maintenanceScheduler {
+	return &maintenanceScheduler{
+		store:                store,
+		customerEmails:       customerEmails,
+		notBefore:            notBefore,
+		defaultScheduledDate: defaultScheduledDate,
+		windows:              make(map[string]*maintenancewindow.Window),
+		dates:                make(map[string]*string),
+	}
+}
+
+// schedule returns the scheduled date of the upgrade of the instance, and its maintenance window with where it was
+// defined. Instances with the same scheduled date get the same pointer, so that they share an upgrade path.
+func (s *maintenanceScheduler) schedule(instance *openapiclientfleet.ResourceInstance) (scheduledDate *string, window string, err error) {
+	spec, source := s.store.Lookup(
+		maintenancewindow.InstanceTagWindow(instance),
+		instance.SubscriptionId,
+		s.customerEmails[instance.SubscriptionId],
+	)
+	if spec == "" {
+		return s.defaultScheduledDate, "", nil
+	}
+
+	parsed, ok := s.windows[spec]
+	if !ok {
+		if parsed, err = maintenancewindow.Parse(spec); err != nil {
+			return nil, "", fmt.Errorf("maintenance window of %s from %s: %w", instance.ConsumptionResourceInstanceResult.GetId(), source, err)
+		}
+		s.windows[spec] = parsed
+	}
+
+	date := parsed.Next(s.notBefore).UTC().Format(time.RFC3339)
+	if s.dates[date] == nil {
+		s.dates[date] = &date
+	}
+	return s.dates[date], fmt.Sprintf("%s (%s)", spec, source), nil
+}
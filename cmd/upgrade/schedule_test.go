@@ -0,0 +1,57 @@
+package upgrade
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/upgrade/maintenancewindow"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceScheduler(t *testing.T) {
+	require := require.New(t)
+
+	store := &maintenancewindow.Store{}
+	store.SetCustomer("admin@example.com", "Sun 02:00-04:00 Europe/Berlin")
+	store.SetSubscription("sub-2", "Sat 22:00-23:00")
+	store.SetSubscription("sub-bad", "Someday 02:00-04:00")
+
+	customerEmails := map[string]string{
+		"sub-1": "admin@example.com",
+		"sub-2": "admin@example.com",
+		"sub-3": "other@example.com",
+	}
+	defaultDate := "2025-01-16T00:00:00Z"
+	scheduler := newMaintenanceScheduler(store, customerEmails, time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), &defaultDate)
+
+	newInstance := func(id, subscriptionID string) *openapiclientfleet.ResourceInstance {
+		instance := &openapiclientfleet.ResourceInstance{SubscriptionId: subscriptionID}
+		instance.ConsumptionResourceInstanceResult.Id = utils.ToPtr(id)
+		return instance
+	}
+
+	first, window, err := scheduler.schedule(newInstance("instance-1", "sub-1"))
+	require.NoError(err)
+	require.Equal("2025-01-19T01:00:00Z", *first)
+	require.Equal("Sun 02:00-04:00 Europe/Berlin (customer admin@example.com)", window)
+
+	// Instances in the same window share the scheduled date pointer, and so the upgrade path
+	second, _, err := scheduler.schedule(newInstance("instance-2", "sub-1"))
+	require.NoError(err)
+	require.Same(first, second)
+
+	date, window, err := scheduler.schedule(newInstance("instance-3", "sub-2"))
+	require.NoError(err)
+	require.Equal("2025-01-18T22:00:00Z", *date)
+	require.Equal("Sat 22:00-23:00 (subscription sub-2)", window)
+
+	date, window, err = scheduler.schedule(newInstance("instance-4", "sub-3"))
+	require.NoError(err)
+	require.Same(&defaultDate, date)
+	require.Empty(window)
+
+	_, _, err = scheduler.schedule(newInstance("instance-5", "sub-bad"))
+	require.ErrorContains(err, "maintenance window of instance-5 from subscription sub-bad")
+}
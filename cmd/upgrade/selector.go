@@ -0,0 +1,273 @@
+package upgrade
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// upgradableInstanceStatuses are the instance statuses an upgrade can start from. Instances being deployed,
+// updated or deleted are left out of selector upgrades.
+var upgradableInstanceStatuses = []string{"RUNNING", "STOPPED", "FAILED"}
+
+// instanceSelector selects the instances to upgrade from the inventory instead of explicit instance IDs.
+type instanceSelector struct {
+	Service     string
+	Plan        string
+	Environment string
+	FromVersion string
+}
+
+// skippedInstance is an instance matching the selector that isn't upgraded.
+type skippedInstance struct {
+	InstanceID string
+	Reason     string
+}
+
+func (s instanceSelector) isSet() bool {
+	return s.Service != "" || s.Plan != "" || s.Environment != "" || s.FromVersion != ""
+}
+
+func (s instanceSelector) validate() error {
+	if s.Service == "" || s.Plan == "" {
+		return fmt.Errorf("--service and --plan are required to select instances to upgrade")
+	}
+	return nil
+}
+
+func (s instanceSelector) matches(instance openapiclientfleet.ResourceInstanceSearchRecord) bool {
+	return matchesNameOrID(s.Service, instance.ServiceId, instance.ServiceName) &&
+		matchesNameOrID(s.Plan, instance.ProductTierId, instance.GetProductTierName()) &&
+		matchesNameOrID(s.Environment, instance.ServiceEnvironmentId, instance.ServiceEnvironmentName) &&
+		(s.FromVersion == "" || s.FromVersion == instance.GetProductTierVersion())
+}
+
+func matchesNameOrID(value, id, name string) bool {
+	return value == "" || value == id || strings.EqualFold(value, name)
+}
+
+// selectInstances returns the instances matching the selector, by instance ID, and the matching instances that are
+// excluded or not in an upgradable status.
+func selectInstances(instances []openapiclientfleet.ResourceInstanceSearchRecord, selector instanceSelector, exclude []string) (selected map[string]openapiclientfleet.ResourceInstanceSearchRecord, skipped []skippedInstance) {
+	selected = make(map[string]openapiclientfleet.ResourceInstanceSearchRecord)
+	for _, instance := range instances {
+		if instance.Id == "" || !selector.matches(instance) {
+			continue
+		}
+		if _, ok := selected[instance.Id]; ok {
+			continue
+		}
+
+		switch {
+		case slices.Contains(exclude, instance.Id):
+			skipped = append(skipped, skippedInstance{InstanceID: instance.Id, Reason: "excluded"})
+		case !slices.Contains(upgradableInstanceStatuses, strings.ToUpper(instance.Status)):
+			skipped = append(skipped, skippedInstance{InstanceID: instance.Id, Reason: "status " + instance.Status})
+		default:
+			selected[instance.Id] = instance
+		}
+	}
+
+	sort.Slice(skipped, func(i, j int) bool {
+		return skipped[i].InstanceID < skipped[j].InstanceID
+	})
+	return
+}
+
+func formatSkippedInstances(skipped []skippedInstance) string {
+	parts := make([]string, 0, len(skipped))
+	for _, instance := range skipped {
+		parts = append(parts, fmt.Sprintf("%s (%s)", instance.InstanceID, instance.Reason))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// upgradeRequest is the instances to upgrade, given by ID or selected, and the version to upgrade them to. It is read
+// from the flags shared by upgrade and upgrade check.
+type upgradeRequest struct {
+	InstanceIDs []string
+	Selector    instanceSelector
+	Exclude     []string
+	Version     string
+	VersionName string
+}
+
+// getUpgradeRequest reads and validates the instance and version flags shared by upgrade and upgrade check.
+func getUpgradeRequest(cmd *cobra.Command, args []string) (req upgradeRequest, err error) {
+	if req.Version, err = cmd.Flags().GetString("version"); err != nil {
+		return
+	}
+	req.Version = strings.Trim(req.Version, "\"") // Remove quotes
+	if req.VersionName, err = cmd.Flags().GetString("version-name"); err != nil {
+		return
+	}
+	if req.Selector.Service, err = cmd.Flags().GetString("service"); err != nil {
+		return
+	}
+	if req.Selector.Plan, err = cmd.Flags().GetString("plan"); err != nil {
+		return
+	}
+	if req.Selector.Environment, err = cmd.Flags().GetString("environment"); err != nil {
+		return
+	}
+	if req.Selector.FromVersion, err = cmd.Flags().GetString("from-version"); err != nil {
+		return
+	}
+	if req.Exclude, err = cmd.Flags().GetStringSlice("exclude"); err != nil {
+		return
+	}
+
+	if req.Version == "" && req.VersionName == "" {
+		err = errors.New("version or version name is required")
+		return
+	}
+	if req.Version != "" && req.VersionName != "" {
+		err = errors.New("please provide either version or version name, not both")
+		return
+	}
+
+	if req.Selector.isSet() {
+		if len(args) > 0 {
+			err = errors.New("instance IDs can't be combined with --service, --plan, --environment or --from-version")
+			return
+		}
+		err = req.Selector.validate()
+		return
+	}
+
+	req.InstanceIDs = slices.DeleteFunc(slices.Clone(args), func(instanceID string) bool {
+		return slices.Contains(req.Exclude, instanceID)
+	})
+	if len(req.InstanceIDs) == 0 {
+		err = errors.New("instance IDs or --service and --plan are required")
+	}
+	return
+}
+
+// upgradeInstance is an instance to upgrade, with its source and target versions.
+type upgradeInstance struct {
+	Record        openapiclientfleet.ResourceInstanceSearchRecord
+	Details       *openapiclientfleet.ResourceInstance
+	SourceVersion string
+	TargetVersion string
+}
+
+func (i upgradeInstance) preflightTarget() preflightTarget {
+	params, _ := i.Details.InputParams.(map[string]interface{})
+	return preflightTarget{
+		InstanceID:    i.Record.Id,
+		ServiceID:     i.Record.ServiceId,
+		ProductTierID: i.Record.ProductTierId,
+		ResourceID:    i.Details.ConsumptionResourceInstanceResult.GetResourceID(),
+		SourceVersion: i.SourceVersion,
+		TargetVersion: i.TargetVersion,
+		Params:        params,
+	}
+}
+
+func (i upgradeInstance) preview() model.UpgradePreview {
+	return model.UpgradePreview{
+		InstanceID:    i.Record.Id,
+		Service:       i.Record.ServiceName,
+		Environment:   i.Record.ServiceEnvironmentName,
+		Plan:          i.Record.GetProductTierName(),
+		Status:        i.Record.Status,
+		SourceVersion: i.SourceVersion,
+		TargetVersion: i.TargetVersion,
+	}
+}
+
+// resolveUpgradeInstances finds the instances of the request, with their source version and the target version of
+// their plan. Selected instances already at the target version are skipped, while given ones are an error.
+func resolveUpgradeInstances(cmd *cobra.Command, token string, req upgradeRequest) (instances []upgradeInstance, skipped []skippedInstance, err error) {
+	// Select the instances from the inventory
+	instanceIDs := req.InstanceIDs
+	var selected map[string]openapiclientfleet.ResourceInstanceSearchRecord
+	if req.Selector.isSet() {
+		searchRes, err := dataaccess.SearchInventory(cmd.Context(), token, "resourceinstance:i")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		selected, skipped = selectInstances(searchRes.ResourceInstanceResults, req.Selector, req.Exclude)
+		instanceIDs = make([]string, 0, len(selected))
+		for instanceID := range selected {
+			instanceIDs = append(instanceIDs, instanceID)
+		}
+		slices.Sort(instanceIDs)
+
+		if len(instanceIDs) == 0 {
+			if len(skipped) > 0 {
+				return nil, nil, fmt.Errorf("no instances to upgrade match the selection, skipped: %s", formatSkippedInstances(skipped))
+			}
+			return nil, nil, errors.New("no instances to upgrade match the selection")
+		}
+	}
+
+	targetVersions := make(map[string]string)
+	for _, instanceID := range instanceIDs {
+		// Check if the instance exists
+		instance, found := selected[instanceID]
+		if !found {
+			searchRes, err := dataaccess.SearchInventory(cmd.Context(), token, fmt.Sprintf("resourceinstance:%s", instanceID))
+			if err != nil {
+				return nil, nil, err
+			}
+			if searchRes != nil {
+				for _, record := range searchRes.ResourceInstanceResults {
+					if record.Id == instanceID {
+						instance = record
+						found = true
+						break
+					}
+				}
+			}
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("%s not found. Please check the instance ID and try again", instanceID)
+		}
+
+		// Find the source version of the instance
+		details, err := dataaccess.DescribeResourceInstance(cmd.Context(), token, instance.ServiceId, instance.ServiceEnvironmentId, instanceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		sourceVersion := details.TierVersion
+
+		// Get the target version, once per plan
+		planKey := instance.ServiceId + "/" + instance.ProductTierId
+		targetVersion, ok := targetVersions[planKey]
+		if !ok {
+			targetVersion, err = resolveTargetVersion(cmd, token, instance.ServiceId, instance.ProductTierId, req.Version, req.VersionName)
+			if err != nil {
+				return nil, nil, err
+			}
+			targetVersions[planKey] = targetVersion
+		}
+
+		// Check if the target is the same as the source
+		if sourceVersion == targetVersion {
+			if req.Selector.isSet() {
+				skipped = append(skipped, skippedInstance{InstanceID: instanceID, Reason: "already at version " + targetVersion})
+				continue
+			}
+			return nil, nil, fmt.Errorf("source version %s is the same as target version for %s", sourceVersion, instanceID)
+		}
+
+		instances = append(instances, upgradeInstance{
+			Record:        instance,
+			Details:       details,
+			SourceVersion: sourceVersion,
+			TargetVersion: targetVersion,
+		})
+	}
+
+	return instances, skipped, nil
+}
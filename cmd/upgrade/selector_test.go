@@ -0,0 +1,97 @@
+package upgrade
+
+import (
+	"testing"
+
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectInstances(t *testing.T) {
+	require := require.New(t)
+
+	premium, basic := "Premium", "Basic"
+	v12, v13 := "1.2", "1.3"
+	instance := func(id, status, environment string, plan, version *string) openapiclientfleet.ResourceInstanceSearchRecord {
+		return openapiclientfleet.ResourceInstanceSearchRecord{
+			Id:                     id,
+			ServiceId:              "s-postgres",
+			ServiceName:            "postgres",
+			ServiceEnvironmentId:   "se-" + environment,
+			ServiceEnvironmentName: environment,
+			ProductTierId:          "pt-" + *plan,
+			ProductTierName:        plan,
+			ProductTierVersion:     version,
+			Status:                 status,
+		}
+	}
+	instances := []openapiclientfleet.ResourceInstanceSearchRecord{
+		instance("instance-1", "RUNNING", "Prod", &premium, &v12),
+		instance("instance-2", "STOPPED", "Prod", &premium, &v12),
+		instance("instance-3", "DEPLOYING", "Prod", &premium, &v12),
+		instance("instance-4", "RUNNING", "Prod", &premium, &v12),
+		instance("instance-5", "RUNNING", "Prod", &premium, &v13),
+		instance("instance-6", "RUNNING", "Dev", &premium, &v12),
+		instance("instance-7", "RUNNING", "Prod", &basic, &v12),
+		instance("instance-1", "RUNNING", "Prod", &premium, &v12),
+	}
+
+	selector := instanceSelector{Service: "Postgres", Plan: "premium", Environment: "se-Prod", FromVersion: "1.2"}
+	require.True(selector.isSet())
+	require.NoError(selector.validate())
+
+	selected, skipped := selectInstances(instances, selector, []string{"instance-4"})
+	require.Len(selected, 2)
+	require.Contains(selected, "instance-1")
+	require.Contains(selected, "instance-2")
+	require.Equal([]skippedInstance{
+		{InstanceID: "instance-3", Reason: "status DEPLOYING"},
+		{InstanceID: "instance-4", Reason: "excluded"},
+	}, skipped)
+	require.Equal("instance-3 (status DEPLOYING), instance-4 (excluded)", formatSkippedInstances(skipped))
+
+	selected, skipped = selectInstances(instances, instanceSelector{Service: "s-postgres", Plan: "pt-Premium"}, nil)
+	require.Len(selected, 5)
+	require.Len(skipped, 1)
+
+	require.False(instanceSelector{}.isSet())
+	require.EqualError(instanceSelector{Environment: "Prod"}.validate(), "--service and --plan are required to select instances to upgrade")
+}
+
+func TestGetUpgradeRequest(t *testing.T) {
+	require := require.New(t)
+
+	parse := func(args ...string) (upgradeRequest, error) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("version", "", "")
+		cmd.Flags().String("version-name", "", "")
+		cmd.Flags().String("service", "", "")
+		cmd.Flags().String("plan", "", "")
+		cmd.Flags().String("environment", "", "")
+		cmd.Flags().String("from-version", "", "")
+		cmd.Flags().StringSlice("exclude", []string{}, "")
+		require.NoError(cmd.ParseFlags(args))
+		return getUpgradeRequest(cmd, cmd.Flags().Args())
+	}
+
+	req, err := parse("instance-1", "instance-2", "--version", `"2.0"`, "--exclude", "instance-2")
+	require.NoError(err)
+	require.Equal(upgradeRequest{InstanceIDs: []string{"instance-1"}, Exclude: []string{"instance-2"}, Version: "2.0"}, req)
+
+	req, err = parse("--version-name", "v2", "--service", "postgres", "--plan", "Premium")
+	require.NoError(err)
+	require.Equal(instanceSelector{Service: "postgres", Plan: "Premium"}, req.Selector)
+	require.Empty(req.InstanceIDs)
+
+	_, err = parse("instance-1")
+	require.EqualError(err, "version or version name is required")
+	_, err = parse("instance-1", "--version", "2.0", "--version-name", "v2")
+	require.EqualError(err, "please provide either version or version name, not both")
+	_, err = parse("instance-1", "--version", "2.0", "--service", "postgres", "--plan", "Premium")
+	require.EqualError(err, "instance IDs can't be combined with --service, --plan, --environment or --from-version")
+	_, err = parse("--version", "2.0", "--environment", "Prod")
+	require.EqualError(err, "--service and --plan are required to select instances to upgrade")
+	_, err = parse("instance-1", "--version", "2.0", "--exclude", "instance-1")
+	require.EqualError(err, "instance IDs or --service and --plan are required")
+}
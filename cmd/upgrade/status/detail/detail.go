@@ -2,25 +2,38 @@ package detail
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
 
 	"github.com/chelnak/ysmrr"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
-	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
 	"github.com/spf13/cobra"
 )
 
 const (
 	detailExample = `# Get upgrade status detail
-omctl upgrade status detail [upgrade-id]`
+omctl upgrade status detail [upgrade-id]
+
+# Get only the timing summary of an upgrade, with duration percentiles, throughput and ETA
+omctl upgrade status detail [upgrade-id] --summary`
 )
 
 var Cmd = &cobra.Command{
-	Use:          "detail [upgrade-id] [flags]",
-	Short:        "Get Upgrade status detail",
+	Use:   "detail [upgrade-id] [flags]",
+	Short: "Get Upgrade status detail",
+	Long: `This command gets the upgrade status of each instance of an upgrade, with how long the upgrade of the instance
+took, or has been running for.
+
+It also summarizes the timing of the upgrade: the p50, p90 and max durations of the finished instance upgrades, the
+throughput in instances per hour, and the estimated time left to upgrade the pending instances at that throughput.
+Instances taking more than twice the median duration are flagged as slow.
+
+With --output=json, the status of each instance is printed with its duration and slow flag, and the timing summary is
+printed instead with --summary.`,
 	Example:      detailExample,
 	RunE:         run,
 	SilenceUsage: true,
@@ -28,6 +41,8 @@ var Cmd = &cobra.Command{
 
 func init() {
 	Cmd.Args = cobra.ExactArgs(1)
+
+	Cmd.Flags().Bool("summary", false, "Only print the timing summary of the upgrade")
 }
 
 func run(cmd *cobra.Command, args []string) error {
@@ -42,6 +57,11 @@ func run(cmd *cobra.Command, args []string) error {
 		utils.PrintError(err)
 		return err
 	}
+	summaryOnly, err := cmd.Flags().GetBool("summary")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
 
 	// Validate user login
 	token, err := common.GetTokenWithLogin()
@@ -60,8 +80,6 @@ func run(cmd *cobra.Command, args []string) error {
 		sm.Start()
 	}
 
-	formattedUpgradeStatusDetails := make([]*model.UpgradeStatusDetail, 0)
-
 	searchRes, err := dataaccess.SearchInventory(cmd.Context(), token, fmt.Sprintf("upgradepath:%s", upgradePathID))
 	if err != nil {
 		utils.HandleSpinnerError(spinner, sm, err)
@@ -97,24 +115,7 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	for _, instanceUpgrade := range instanceUpgrades {
-		startTime := ""
-		if instanceUpgrade.UpgradeStartTime != nil {
-			startTime = *instanceUpgrade.UpgradeStartTime
-		}
-
-		endTime := ""
-		if instanceUpgrade.UpgradeEndTime != nil {
-			endTime = *instanceUpgrade.UpgradeEndTime
-		}
-		formattedUpgradeStatusDetails = append(formattedUpgradeStatusDetails, &model.UpgradeStatusDetail{
-			UpgradeID:        upgradePathID,
-			InstanceID:       instanceUpgrade.InstanceId,
-			UpgradeStatus:    instanceUpgrade.Status,
-			UpgradeStartTime: startTime,
-			UpgradeEndTime:   endTime,
-		})
-	}
+	formattedUpgradeStatusDetails, timingSummary := computeUpgradeTiming(upgradePathID, instanceUpgrades, time.Now())
 
 	if len(formattedUpgradeStatusDetails) == 0 {
 		utils.HandleSpinnerSuccess(spinner, sm, "No upgrade found")
@@ -124,11 +125,40 @@ func run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Print output
+	if summaryOnly {
+		err = utils.PrintTextTableJsonOutput(output, timingSummary)
+		if err != nil {
+			utils.PrintError(err)
+			return err
+		}
+		return nil
+	}
+
 	err = utils.PrintTextTableJsonArrayOutput(output, formattedUpgradeStatusDetails)
 	if err != nil {
 		utils.PrintError(err)
 		return err
 	}
 
+	if output != "json" {
+		fmt.Println()
+		err = utils.PrintTextTableJsonOutput(output, timingSummary)
+		if err != nil {
+			utils.PrintError(err)
+			return err
+		}
+
+		slowInstanceIDs := make([]string, 0)
+		for _, detail := range formattedUpgradeStatusDetails {
+			if detail.Slow {
+				slowInstanceIDs = append(slowInstanceIDs, detail.InstanceID)
+			}
+		}
+		if len(slowInstanceIDs) > 0 {
+			utils.PrintWarning(fmt.Sprintf("Slow instance upgrades, taking more than %dx the median duration of %s: %s",
+				slowFactor, timingSummary.P50Duration, strings.Join(slowInstanceIDs, ", ")))
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,169 @@
+package detail
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+)
+
+const (
+	// slowFactor is how many times the median duration an instance upgrade takes to be reported as slow
+	slowFactor = 2
+	// minSamplesForOutliers is the number of finished instance upgrades needed to report slow ones
+	minSamplesForOutliers = 3
+)
+
+var (
+	finishedStatuses   = []string{model.Complete.String(), model.Failed.String(), model.Skipped.String(), model.Cancelled.String()}
+	inProgressStatuses = []string{model.InProgress.String(), model.Verifying.String()}
+)
+
+// instanceTiming is the timing of the upgrade of one instance.
+type instanceTiming struct {
+	start, end time.Time
+	duration   time.Duration
+	finished   bool
+	running    bool
+}
+
+// computeUpgradeTiming returns the status detail of each instance with its upgrade duration, and a summary of the
+// upgrade timing: duration percentiles of the finished instances, throughput, and the time left to upgrade the
+// remaining instances at the observed throughput. Instances running or finished much slower than the median are
+// flagged as slow.
+func computeUpgradeTiming(upgradePathID string, instanceUpgrades []openapiclientfleet.InstanceUpgrade, now time.Time) ([]*model.UpgradeStatusDetail, model.UpgradeTimingSummary) {
+	summary := model.UpgradeTimingSummary{
+		UpgradeID: upgradePathID,
+		Total:     len(instanceUpgrades),
+	}
+
+	timings := make([]instanceTiming, len(instanceUpgrades))
+	durations := make([]time.Duration, 0)
+	var firstStart, lastEnd time.Time
+	for i, instanceUpgrade := range instanceUpgrades {
+		status := strings.ToUpper(instanceUpgrade.Status)
+		timing := instanceTiming{
+			start:    parseTime(instanceUpgrade.UpgradeStartTime),
+			end:      parseTime(instanceUpgrade.UpgradeEndTime),
+			finished: slices.Contains(finishedStatuses, status),
+			running:  slices.Contains(inProgressStatuses, status),
+		}
+
+		switch {
+		case timing.finished:
+			summary.Finished++
+		case timing.running:
+			summary.InProgress++
+		default:
+			summary.Pending++
+		}
+
+		if !timing.start.IsZero() && (firstStart.IsZero() || timing.start.Before(firstStart)) {
+			firstStart = timing.start
+		}
+		switch {
+		case !timing.start.IsZero() && !timing.end.IsZero():
+			timing.duration = timing.end.Sub(timing.start)
+			durations = append(durations, timing.duration)
+			if timing.end.After(lastEnd) {
+				lastEnd = timing.end
+			}
+		case !timing.start.IsZero() && timing.running:
+			timing.duration = now.Sub(timing.start)
+		}
+		timings[i] = timing
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	p50 := percentile(durations, 50)
+	if len(durations) > 0 {
+		summary.P50Duration = formatDuration(p50)
+		summary.P90Duration = formatDuration(percentile(durations, 90))
+		summary.MaxDuration = formatDuration(durations[len(durations)-1])
+	}
+
+	// Throughput of the finished instances since the first one started
+	remaining := summary.InProgress + summary.Pending
+	if len(durations) > 0 && lastEnd.After(firstStart) {
+		elapsed := lastEnd.Sub(firstStart)
+		if remaining > 0 {
+			elapsed = now.Sub(firstStart)
+		}
+		summary.ThroughputPerHour = math.Round(float64(len(durations))/elapsed.Hours()*100) / 100
+	}
+	switch {
+	case remaining == 0:
+		summary.ETA = "done"
+	case summary.ThroughputPerHour > 0:
+		eta := time.Duration(float64(remaining) / summary.ThroughputPerHour * float64(time.Hour))
+		summary.ETA = formatDuration(eta)
+		summary.EstimatedCompletionAt = now.Add(eta).UTC().Format(time.RFC3339)
+	default:
+		summary.ETA = "unknown"
+	}
+
+	details := make([]*model.UpgradeStatusDetail, 0, len(instanceUpgrades))
+	for i, instanceUpgrade := range instanceUpgrades {
+		timing := timings[i]
+		detail := &model.UpgradeStatusDetail{
+			UpgradeID:        upgradePathID,
+			InstanceID:       instanceUpgrade.InstanceId,
+			UpgradeStatus:    instanceUpgrade.Status,
+			UpgradeStartTime: valueOrEmpty(instanceUpgrade.UpgradeStartTime),
+			UpgradeEndTime:   valueOrEmpty(instanceUpgrade.UpgradeEndTime),
+		}
+		if timing.duration > 0 {
+			detail.Duration = formatDuration(timing.duration)
+			if timing.running && timing.end.IsZero() {
+				detail.Duration += " (running)"
+			}
+		}
+		if len(durations) >= minSamplesForOutliers && timing.duration > slowFactor*p50 {
+			detail.Slow = true
+			summary.SlowInstances++
+		}
+		details = append(details, detail)
+	}
+
+	return details, summary
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+func parseTime(value *string) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, *value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func formatDuration(d time.Duration) string {
+	if d >= time.Minute {
+		d = d.Round(time.Second)
+	} else {
+		d = d.Round(100 * time.Millisecond)
+	}
+	return d.String()
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
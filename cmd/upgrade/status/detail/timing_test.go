@@ -0,0 +1,84 @@
+package detail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/utils"
+	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeUpgradeTiming(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) *string {
+		return utils.ToPtr(start.Add(time.Duration(minutes) * time.Minute).Format(time.RFC3339))
+	}
+
+	instanceUpgrades := []openapiclientfleet.InstanceUpgrade{
+		{InstanceId: "instance-1", Status: "COMPLETE", UpgradeStartTime: at(0), UpgradeEndTime: at(10)},
+		{InstanceId: "instance-2", Status: "COMPLETE", UpgradeStartTime: at(0), UpgradeEndTime: at(12)},
+		{InstanceId: "instance-3", Status: "FAILED", UpgradeStartTime: at(10), UpgradeEndTime: at(20)},
+		{InstanceId: "instance-4", Status: "COMPLETE", UpgradeStartTime: at(12), UpgradeEndTime: at(60)},
+		{InstanceId: "instance-5", Status: "IN_PROGRESS", UpgradeStartTime: at(20)},
+		{InstanceId: "instance-6", Status: "PENDING"},
+	}
+
+	details, summary := computeUpgradeTiming("upgrade-1", instanceUpgrades, start.Add(60*time.Minute))
+
+	require.Len(details, 6)
+	require.Equal("upgrade-1", details[0].UpgradeID)
+	require.Equal("10m0s", details[0].Duration)
+	require.False(details[0].Slow)
+	require.Equal("48m0s", details[3].Duration)
+	require.True(details[3].Slow)
+	require.Equal("40m0s (running)", details[4].Duration)
+	require.True(details[4].Slow)
+	require.Empty(details[5].Duration)
+	require.Empty(details[5].UpgradeStartTime)
+
+	require.Equal(6, summary.Total)
+	require.Equal(4, summary.Finished)
+	require.Equal(1, summary.InProgress)
+	require.Equal(1, summary.Pending)
+	require.Equal("10m0s", summary.P50Duration)
+	require.Equal("48m0s", summary.P90Duration)
+	require.Equal("48m0s", summary.MaxDuration)
+	require.Equal(4.0, summary.ThroughputPerHour)
+	require.Equal("30m0s", summary.ETA)
+	require.Equal("2025-01-15T11:30:00Z", summary.EstimatedCompletionAt)
+	require.Equal(2, summary.SlowInstances)
+}
+
+func TestComputeUpgradeTimingWithoutFinishedInstances(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	details, summary := computeUpgradeTiming("upgrade-1", []openapiclientfleet.InstanceUpgrade{
+		{InstanceId: "instance-1", Status: "IN_PROGRESS", UpgradeStartTime: utils.ToPtr("2025-01-15T09:00:00Z")},
+		{InstanceId: "instance-2", Status: "PENDING"},
+	}, now)
+
+	require.Equal("1h0m0s (running)", details[0].Duration)
+	require.False(details[0].Slow)
+	require.Empty(summary.P50Duration)
+	require.Zero(summary.ThroughputPerHour)
+	require.Equal("unknown", summary.ETA)
+	require.Empty(summary.EstimatedCompletionAt)
+
+	_, summary = computeUpgradeTiming("upgrade-1", nil, now)
+	require.Equal("done", summary.ETA)
+}
+
+func TestPercentile(t *testing.T) {
+	require := require.New(t)
+
+	durations := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	require.Equal(time.Duration(5), percentile(durations, 50))
+	require.Equal(time.Duration(9), percentile(durations, 90))
+	require.Equal(time.Duration(10), percentile(durations, 100))
+	require.Equal(time.Duration(1), percentile(durations, 0))
+	require.Zero(percentile(nil, 50))
+}
@@ -2,12 +2,19 @@ package upgrade
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/common"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/upgrade/list"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/upgrade/maintenancewindow"
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/upgrade/manageupgradelifecycle"
+	"github.com/omnistrate-oss/omnistrate-ctl/cmd/upgrade/rollback"
 
 	"github.com/chelnak/ysmrr"
+	"github.com/cqroot/prompt"
+	"github.com/cqroot/prompt/input"
 	"github.com/omnistrate-oss/omnistrate-ctl/cmd/upgrade/status"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/config"
 	"github.com/omnistrate-oss/omnistrate-ctl/internal/dataaccess"
@@ -31,13 +38,41 @@ omctl upgrade [instance1] [instance2] --version=preferred
 omctl upgrade [instance1] [instance2] --version-name=v0.1.1
 
 # Upgrade instance to a specific version with a schedule date in the future
-omctl upgrade [instance-id] --version=1.0 --scheduled-date="2023-12-01T00:00:00Z"`
+omctl upgrade [instance-id] --version=1.0 --scheduled-date="2023-12-01T00:00:00Z"
+
+# Upgrade all instances of a plan in an environment from version 1.2 to the preferred version, after a preview
+omctl upgrade --service=postgres --plan=Premium --environment=Prod --from-version=1.2 --version=preferred
+
+# Upgrade all instances of a plan except some, without confirmation
+omctl upgrade --service=postgres --plan=Premium --version=latest --exclude=instance-abcd1234,instance-efgh5678 --yes
+
+# Upgrade instances next Sunday at 02:00 in the Berlin time zone
+omctl upgrade [instance-id] --version=2.0 --scheduled-date="next sunday 02:00" --timezone=Europe/Berlin
+
+# Upgrade instances only if they are compatible with the target version
+omctl upgrade [instance1] [instance2] --version=latest --preflight
+
+# Upgrade each instance of a plan at the start of its next maintenance window, notifying the customers
+omctl upgrade --service=postgres --plan=Premium --version=latest --respect-maintenance-windows --notify-customer`
 )
 
 var Cmd = &cobra.Command{
-	Use:          "upgrade --version=[version]",
-	Short:        "Upgrade Instance Deployments to a newer or older version",
-	Long:         `This command helps you upgrade Instance Deployments to a newer or older version.`,
+	Use:   "upgrade [instance-id...] --version=[version]",
+	Short: "Upgrade Instance Deployments to a newer or older version",
+	Long: `This command helps you upgrade Instance Deployments to a newer or older version.
+
+Instead of instance IDs, the instances can be selected with --service and --plan, optionally narrowed down with
+--environment and --from-version. All matching instances in the RUNNING, STOPPED or FAILED status are upgraded,
+except the ones listed in --exclude and the ones already at the target version. A preview of each instance with its
+source and target version is shown for confirmation before the upgrade is scheduled, unless --yes is set.
+
+With --respect-maintenance-windows, the upgrade of each instance is scheduled at the start of its next maintenance
+window, after --scheduled-date if set, and the instances are split into one upgrade per scheduled date. Maintenance
+windows are defined with 'omctl upgrade maintenance-window' per customer email or subscription, or with a
+"maintenance-window" instance tag. Instances without a maintenance window are scheduled at --scheduled-date.
+
+With --preflight, the instances are first checked for compatibility with the target version, as done by 'omctl upgrade
+check', and no upgrade is created if any issue is blocking.`,
 	Example:      upgradeExample,
 	RunE:         run,
 	SilenceUsage: true,
@@ -45,18 +80,32 @@ var Cmd = &cobra.Command{
 
 func init() {
 	Cmd.AddCommand(status.Cmd)
+	Cmd.AddCommand(list.Cmd)
 	Cmd.AddCommand(manageupgradelifecycle.CancelCmd)
 	Cmd.AddCommand(manageupgradelifecycle.ResumeCmd)
 	Cmd.AddCommand(manageupgradelifecycle.PauseCmd)
 	Cmd.AddCommand(manageupgradelifecycle.NotifyCustomerCmd)
 	Cmd.AddCommand(manageupgradelifecycle.SkipInstancesCmd)
+	Cmd.AddCommand(rollback.Cmd)
+	Cmd.AddCommand(maintenancewindow.Cmd)
+	Cmd.AddCommand(checkCmd)
 
-	Cmd.Args = cobra.MinimumNArgs(1)
+	Cmd.Args = cobra.ArbitraryArgs
 
 	Cmd.Flags().StringP("version", "", "", "Specify the version number to upgrade to. Use 'latest' to upgrade to the latest version. Use 'preferred' to upgrade to the preferred version. Use either this flag or the --version-name flag to upgrade to a specific version.")
 	Cmd.Flags().StringP("version-name", "", "", "Specify the version name to upgrade to. Use either this flag or the --version flag to upgrade to a specific version.")
-	Cmd.Flags().StringP("scheduled-date", "", "", "Specify the scheduled date for the upgrade.")
+	Cmd.Flags().StringP("scheduled-date", "", "", "Specify the scheduled date for the upgrade, as "+utils.ScheduledDateSyntax+".")
+	Cmd.Flags().String("timezone", "", "Time zone of --scheduled-date when it has none, e.g. Europe/Berlin. Defaults to the local time zone")
+	Cmd.Flags().Bool("respect-maintenance-windows", false, "Schedule the upgrade of each instance at the start of its next maintenance window")
+	Cmd.Flags().String("maintenance-windows-file", "", "Maintenance windows file. Defaults to "+maintenancewindow.DefaultFile+" in the omnistrate-ctl config directory")
 	Cmd.Flags().Bool("notify-customer", false, "Enable customer notifications for the upgrade")
+	Cmd.Flags().String("service", "", "Upgrade the instances of this service, by name or ID, instead of the given instance IDs")
+	Cmd.Flags().String("plan", "", "Upgrade the instances of this service plan, by name or ID")
+	Cmd.Flags().String("environment", "", "Only upgrade the instances in this environment, by name or ID")
+	Cmd.Flags().String("from-version", "", "Only upgrade the instances currently at this version")
+	Cmd.Flags().StringSlice("exclude", []string{}, "Instance IDs to leave out of the upgrade")
+	Cmd.Flags().BoolP("yes", "y", false, "Pre-approve the upgrade of the selected instances without prompting for confirmation")
+	Cmd.Flags().Bool("preflight", false, "Check the compatibility of the instances with the target version first, and don't upgrade if any issue is blocking")
 }
 
 type Args struct {
@@ -79,41 +128,62 @@ func run(cmd *cobra.Command, args []string) error {
 	defer config.CleanupArgsAndFlags(cmd, &args)
 
 	// Retrieve flags
-	version, err := cmd.Flags().GetString("version")
+	output, err := cmd.Flags().GetString("output")
 	if err != nil {
 		utils.PrintError(err)
 		return err
 	}
-	version = strings.Trim(version, "\"") // Remove quotes
-
-	versionName, err := cmd.Flags().GetString("version-name")
+	req, err := getUpgradeRequest(cmd, args)
 	if err != nil {
 		utils.PrintError(err)
 		return err
 	}
-
-	output, err := cmd.Flags().GetString("output")
+	scheduledDateParam, err := cmd.Flags().GetString("scheduled-date")
 	if err != nil {
 		utils.PrintError(err)
 		return err
 	}
-	scheduledDateParam, pError := cmd.Flags().GetString("scheduled-date")
-	var scheduledDate *string
-	if pError == nil && scheduledDateParam != "" {
-		scheduledDate = &scheduledDateParam
+	timezone, err := cmd.Flags().GetString("timezone")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	respectMaintenanceWindows, err := cmd.Flags().GetBool("respect-maintenance-windows")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	maintenanceWindowsFile, err := cmd.Flags().GetString("maintenance-windows-file")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	notifyCustomer, err := cmd.Flags().GetBool("notify-customer")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		utils.PrintError(err)
+		return err
+	}
+	preflight, err := cmd.Flags().GetBool("preflight")
+	if err != nil {
+		utils.PrintError(err)
+		return err
 	}
-
-	notifyCustomer, _ := cmd.Flags().GetBool("notify-customer")
 
 	// Validate input arguments
-	if version == "" && versionName == "" {
-		err = errors.New("version or version name is required")
+	now := time.Now()
+	scheduledDate, scheduledAt, err := utils.ParseScheduledDateFlag(scheduledDateParam, timezone, now)
+	if err != nil {
 		utils.PrintError(err)
 		return err
 	}
 
-	if version != "" && versionName != "" {
-		err = errors.New("please provide either version or version name, not both")
+	if req.Selector.isSet() && output == "json" && !yes {
+		err = errors.New("--yes is required to upgrade selected instances with json output")
 		utils.PrintError(err)
 		return err
 	}
@@ -131,150 +201,171 @@ func run(cmd *cobra.Command, args []string) error {
 	if output != "json" {
 		sm = ysmrr.NewSpinnerManager()
 		msg := "Scheduling upgrade for all instances"
-		if len(args) == 1 {
-			msg = fmt.Sprintf("Scheduling upgrade for %s", args[0])
+		if req.Selector.isSet() {
+			msg = "Selecting instances to upgrade"
+		} else if len(req.InstanceIDs) == 1 {
+			msg = fmt.Sprintf("Scheduling upgrade for %s", req.InstanceIDs[0])
 		}
 		spinner = sm.AddSpinner(msg)
 		sm.Start()
 	}
 
-	upgrades := make(map[Args]*Res)
-	for _, instanceID := range args {
-		// Check if the instance exists
-		searchRes, err := dataaccess.SearchInventory(cmd.Context(), token, fmt.Sprintf("resourceinstance:%s", instanceID))
+	// Load the maintenance windows and the customer emails they may be defined for
+	var scheduler *maintenanceScheduler
+	if respectMaintenanceWindows {
+		if maintenanceWindowsFile == "" {
+			maintenanceWindowsFile = maintenancewindow.DefaultPath()
+		}
+		store, err := maintenancewindow.Load(maintenanceWindowsFile)
 		if err != nil {
 			utils.HandleSpinnerError(spinner, sm, err)
 			return err
 		}
 
-		if searchRes == nil || len(searchRes.ResourceInstanceResults) == 0 {
-			err = fmt.Errorf("%s not found. Please check the instance ID and try again", instanceID)
+		subscriptionsRes, err := dataaccess.SearchInventory(cmd.Context(), token, "subscription:s")
+		if err != nil {
 			utils.HandleSpinnerError(spinner, sm, err)
 			return err
 		}
-
-		var found bool
-		var serviceID, environmentID, productTierID, sourceVersion, targetVersion string
-		for _, instance := range searchRes.ResourceInstanceResults {
-			if instance.Id == instanceID {
-				serviceID = instance.ServiceId
-				environmentID = instance.ServiceEnvironmentId
-				productTierID = instance.ProductTierId
-				found = true
-				break
-			}
-		}
-		if !found {
-			err = fmt.Errorf("%s not found. Please check the instance ID and try again", instanceID)
-			utils.HandleSpinnerError(spinner, sm, err)
-			return nil
+		customerEmails := make(map[string]string)
+		for _, subscription := range subscriptionsRes.SubscriptionResults {
+			customerEmails[subscription.Id] = subscription.RootUserEmail
 		}
 
-		// Find the source version of the instance
-		describeRes, err := dataaccess.DescribeResourceInstance(cmd.Context(), token, serviceID, environmentID, instanceID)
-		if err != nil {
-			utils.HandleSpinnerError(spinner, sm, err)
-			return err
+		notBefore := now
+		if scheduledDate != nil {
+			notBefore = scheduledAt
 		}
-		sourceVersion = describeRes.TierVersion
-
-		// Get the target version
-		if version != "" {
-			switch version {
-			case "latest":
-				targetVersion, err = dataaccess.FindLatestVersion(cmd.Context(), token, serviceID, productTierID)
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-			case "preferred":
-				targetVersion, err = dataaccess.FindPreferredVersion(cmd.Context(), token, serviceID, productTierID)
-				if err != nil {
-					utils.HandleSpinnerError(spinner, sm, err)
-					return err
-				}
-			default:
-				targetVersion = version
-			}
-		} else {
-			allVersions, err := dataaccess.ListVersions(cmd.Context(), token, serviceID, productTierID)
+		scheduler = newMaintenanceScheduler(store, customerEmails, notBefore, scheduledDate)
+	}
+	withoutWindow := make([]string, 0)
+
+	// Find the instances to upgrade with their source and target versions
+	instances, skipped, err := resolveUpgradeInstances(cmd, token, req)
+	if err != nil {
+		utils.HandleSpinnerError(spinner, sm, err)
+		return err
+	}
+
+	upgrades := make(map[Args]*Res)
+	preflightTargets := make([]preflightTarget, 0, len(instances))
+	previews := make([]model.UpgradePreview, 0, len(instances))
+	for _, instance := range instances {
+		instanceID := instance.Record.Id
+
+		// Schedule the upgrade in the maintenance window of the instance
+		instanceScheduledDate, maintenanceWindow := scheduledDate, ""
+		if scheduler != nil {
+			instanceScheduledDate, maintenanceWindow, err = scheduler.schedule(instance.Details)
 			if err != nil {
 				utils.HandleSpinnerError(spinner, sm, err)
 				return err
 			}
-
-			targetVersions := make([]string, 0)
-			for _, versionSet := range allVersions.TierVersionSets {
-				if versionSet.Name != nil && *versionSet.Name == versionName {
-					targetVersions = append(targetVersions, versionSet.Version)
-				}
+			if maintenanceWindow == "" {
+				withoutWindow = append(withoutWindow, instanceID)
 			}
+		}
 
-			if len(targetVersions) == 0 {
-				err = fmt.Errorf("version name %s not found", versionName)
-				utils.HandleSpinnerError(spinner, sm, err)
+		upgradeArgs := Args{
+			ServiceID:      instance.Record.ServiceId,
+			ProductTierID:  instance.Record.ProductTierId,
+			SourceVersion:  instance.SourceVersion,
+			TargetVersion:  instance.TargetVersion,
+			ScheduledDate:  instanceScheduledDate,
+			NotifyCustomer: notifyCustomer,
+		}
+		if upgrades[upgradeArgs] == nil {
+			upgrades[upgradeArgs] = &Res{
+				InstanceIDs: make([]string, 0),
 			}
+		}
+		upgrades[upgradeArgs].InstanceIDs = append(upgrades[upgradeArgs].InstanceIDs, instanceID)
 
-			if len(targetVersions) > 1 {
-				err = fmt.Errorf("multiple versions found for version name %s, please specify the version number", versionName)
-				utils.HandleSpinnerError(spinner, sm, err)
-			}
+		preflightTargets = append(preflightTargets, instance.preflightTarget())
 
-			targetVersion = targetVersions[0]
-		}
+		preview := instance.preview()
+		preview.ScheduledDate = utils.FromPtr(instanceScheduledDate)
+		preview.MaintenanceWindow = maintenanceWindow
+		previews = append(previews, preview)
+	}
 
-		// Check if the target version exists
-		_, err = dataaccess.DescribeVersionSet(cmd.Context(), token, serviceID, productTierID, targetVersion)
+	// Check the compatibility of the instances with the target version before creating any upgrade path
+	if preflight {
+		issues, err := newPreflightChecker(cmd.Context(), token).check(preflightTargets)
 		if err != nil {
-			if strings.Contains(err.Error(), "Version set not found") {
-				err = errors.New(fmt.Sprintf("version %s not found", version))
-			}
 			utils.HandleSpinnerError(spinner, sm, err)
 			return err
 		}
 
-		// Check if the target is the same as the source
-		if sourceVersion == targetVersion {
-			err = fmt.Errorf("source version %s is the same as target version for %s", sourceVersion, instanceID)
+		if hasBlockingIssues(issues) {
+			err = fmt.Errorf("preflight check found %s, no upgrade was created. Run 'omctl upgrade check' with the same arguments for details", summarizeIssues(issues))
 			utils.HandleSpinnerError(spinner, sm, err)
+			if output != "json" {
+				_ = utils.PrintTextTableJsonArrayOutput(output, issues)
+			}
 			return err
 		}
 
-		if upgrades[Args{
-			ServiceID:      serviceID,
-			ProductTierID:  productTierID,
-			SourceVersion:  sourceVersion,
-			TargetVersion:  targetVersion,
-			ScheduledDate:  scheduledDate,
-			NotifyCustomer: notifyCustomer,
-		}] == nil {
-			upgrades[Args{
-				ServiceID:      serviceID,
-				ProductTierID:  productTierID,
-				SourceVersion:  sourceVersion,
-				TargetVersion:  targetVersion,
-				ScheduledDate:  scheduledDate,
-				NotifyCustomer: notifyCustomer,
-			}] = &Res{
-				InstanceIDs: make([]string, 0),
+		if len(issues) > 0 && output != "json" {
+			utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("Preflight check found %s", summarizeIssues(issues)))
+			if err = utils.PrintTextTableJsonArrayOutput(output, issues); err != nil {
+				return err
 			}
+
+			sm = ysmrr.NewSpinnerManager()
+			spinner = sm.AddSpinner(fmt.Sprintf("Scheduling upgrade for %d instances", len(preflightTargets)))
+			sm.Start()
 		}
+	}
 
-		upgrades[Args{
-			ServiceID:      serviceID,
-			ProductTierID:  productTierID,
-			SourceVersion:  sourceVersion,
-			TargetVersion:  targetVersion,
-			ScheduledDate:  scheduledDate,
-			NotifyCustomer: notifyCustomer,
-		}].InstanceIDs = append(upgrades[Args{
-			ServiceID:      serviceID,
-			ProductTierID:  productTierID,
-			SourceVersion:  sourceVersion,
-			TargetVersion:  targetVersion,
-			ScheduledDate:  scheduledDate,
-			NotifyCustomer: notifyCustomer,
-		}].InstanceIDs, instanceID)
+	// Preview the selected instances and confirm the upgrade
+	if req.Selector.isSet() {
+		if len(previews) == 0 {
+			err = fmt.Errorf("no instances to upgrade match the selection, skipped: %s", formatSkippedInstances(skipped))
+			utils.HandleSpinnerError(spinner, sm, err)
+			return err
+		}
+
+		if output != "json" {
+			utils.HandleSpinnerSuccess(spinner, sm, fmt.Sprintf("Selected %d instances to upgrade", len(previews)))
+
+			err = utils.PrintTextTableJsonArrayOutput(output, previews)
+			if err != nil {
+				return err
+			}
+			if len(skipped) > 0 {
+				utils.PrintWarning(fmt.Sprintf("Skipping %d instances: %s", len(skipped), formatSkippedInstances(skipped)))
+			}
+			if len(withoutWindow) > 0 {
+				utils.PrintWarning(fmt.Sprintf("No maintenance window defined for %d instances, which are scheduled without one: %s", len(withoutWindow), strings.Join(withoutWindow, ", ")))
+			}
+		}
+
+		if !yes {
+			ok, err := prompt.New().Ask(fmt.Sprintf("Are you sure you want to upgrade these %d instances? (y/n)", len(previews))).
+				Input("", input.WithValidateFunc(
+					func(input string) error {
+						if slices.Contains([]string{"y", "yes", "n", "no"}, strings.ToLower(input)) {
+							return nil
+						} else {
+							return errors.New("invalid input")
+						}
+					}))
+			if err != nil {
+				utils.PrintError(err)
+				return err
+			}
+
+			if !slices.Contains([]string{"y", "yes"}, strings.ToLower(ok)) {
+				return nil
+			}
+		}
+
+		if output != "json" {
+			sm = ysmrr.NewSpinnerManager()
+			spinner = sm.AddSpinner(fmt.Sprintf("Scheduling upgrade for %d instances", len(previews)))
+			sm.Start()
+		}
 	}
 
 	// Create upgrade path
@@ -326,6 +417,10 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if output != "json" && !req.Selector.isSet() && len(withoutWindow) > 0 {
+		utils.PrintWarning(fmt.Sprintf("No maintenance window defined for %d instances, which are scheduled without one: %s", len(withoutWindow), strings.Join(withoutWindow, ", ")))
+	}
+
 	if output != "json" {
 		println("\nCheck the upgrade status using the following command(s):")
 		for _, upgradeRes := range upgrades {
@@ -335,3 +430,56 @@ func run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// resolveTargetVersion returns the version to upgrade the instances of a plan to, from the version or version name.
+func resolveTargetVersion(cmd *cobra.Command, token, serviceID, productTierID, version, versionName string) (targetVersion string, err error) {
+	if version != "" {
+		switch version {
+		case "latest":
+			targetVersion, err = dataaccess.FindLatestVersion(cmd.Context(), token, serviceID, productTierID)
+			if err != nil {
+				return
+			}
+		case "preferred":
+			targetVersion, err = dataaccess.FindPreferredVersion(cmd.Context(), token, serviceID, productTierID)
+			if err != nil {
+				return
+			}
+		default:
+			targetVersion = version
+		}
+	} else {
+		allVersions, err := dataaccess.ListVersions(cmd.Context(), token, serviceID, productTierID)
+		if err != nil {
+			return "", err
+		}
+
+		targetVersions := make([]string, 0)
+		for _, versionSet := range allVersions.TierVersionSets {
+			if versionSet.Name != nil && *versionSet.Name == versionName {
+				targetVersions = append(targetVersions, versionSet.Version)
+			}
+		}
+
+		if len(targetVersions) == 0 {
+			return "", fmt.Errorf("version name %s not found", versionName)
+		}
+
+		if len(targetVersions) > 1 {
+			return "", fmt.Errorf("multiple versions found for version name %s, please specify the version number", versionName)
+		}
+
+		targetVersion = targetVersions[0]
+	}
+
+	// Check if the target version exists
+	_, err = dataaccess.DescribeVersionSet(cmd.Context(), token, serviceID, productTierID, targetVersion)
+	if err != nil {
+		if strings.Contains(err.Error(), "Version set not found") {
+			err = errors.New(fmt.Sprintf("version %s not found", version))
+		}
+		return "", err
+	}
+
+	return targetVersion, nil
+}
@@ -23,3 +23,21 @@ func GetDefaultDeploymentConfigID(ctx context.Context, token string) (string, er
 	r.Body.Close()
 	return res.Id, nil
 }
+
+func DescribeDeploymentConfig(ctx context.Context, token, deploymentConfigID string) (*openapiclient.DescribeDeploymentConfigResult, error) {
+	ctxWithToken := context.WithValue(ctx, openapiclient.ContextAccessToken, token)
+
+	apiClient := getV1Client()
+	res, r, err := apiClient.DeploymentConfigApiAPI.DeploymentConfigApiDescribeDeploymentConfig(
+		ctxWithToken,
+		deploymentConfigID,
+	).Execute()
+
+	err = handleV1Error(err)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Body.Close()
+	return res, nil
+}
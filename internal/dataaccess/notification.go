@@ -91,4 +91,65 @@ func GetNotificationChannel(ctx context.Context, token, channelID string) (res *
 	}
 	
 	return
-}
\ No newline at end of file
+}
+
+func CreateNotificationChannel(ctx context.Context, token string, request openapiclientfleet.CreateNotificationChannelRequest2) (channelID string, err error) {
+	ctxWithToken := context.WithValue(ctx, openapiclientfleet.ContextAccessToken, token)
+	client := getFleetClient()
+
+	var r *http.Response
+	defer func() {
+		if r != nil {
+			_ = r.Body.Close()
+		}
+	}()
+
+	channelID, r, err = client.NotificationsApiAPI.NotificationsApiCreateNotificationChannel(ctxWithToken).
+		CreateNotificationChannelRequest2(request).
+		Execute()
+	if err != nil {
+		return "", handleFleetError(err)
+	}
+
+	return
+}
+
+func UpdateNotificationChannel(ctx context.Context, token, channelID string, request openapiclientfleet.UpdateNotificationChannelRequest2) (err error) {
+	ctxWithToken := context.WithValue(ctx, openapiclientfleet.ContextAccessToken, token)
+	client := getFleetClient()
+
+	var r *http.Response
+	defer func() {
+		if r != nil {
+			_ = r.Body.Close()
+		}
+	}()
+
+	r, err = client.NotificationsApiAPI.NotificationsApiUpdateNotificationChannel(ctxWithToken, channelID).
+		UpdateNotificationChannelRequest2(request).
+		Execute()
+	if err != nil {
+		return handleFleetError(err)
+	}
+
+	return nil
+}
+
+func DeleteNotificationChannel(ctx context.Context, token, channelID string) (err error) {
+	ctxWithToken := context.WithValue(ctx, openapiclientfleet.ContextAccessToken, token)
+	client := getFleetClient()
+
+	var r *http.Response
+	defer func() {
+		if r != nil {
+			_ = r.Body.Close()
+		}
+	}()
+
+	r, err = client.NotificationsApiAPI.NotificationsApiDeleteNotificationChannel(ctxWithToken, channelID).Execute()
+	if err != nil {
+		return handleFleetError(err)
+	}
+
+	return nil
+}
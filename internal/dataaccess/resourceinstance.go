@@ -443,3 +443,36 @@ func OneOffPatchResourceInstance(ctx context.Context, token string, serviceID, e
 	}
 	return
 }
+
+// ListResourceInstances returns all instances of a service plan in an environment, without instance details.
+func ListResourceInstances(ctx context.Context, token string, serviceID, environmentID, productTierID string) ([]openapiclientfleet.ResourceInstance, error) {
+	ctxWithToken := context.WithValue(ctx, openapiclientfleet.ContextAccessToken, token)
+	apiClient := getFleetClient()
+
+	instances := make([]openapiclientfleet.ResourceInstance, 0)
+	var nextPageToken string
+	for {
+		req := apiClient.InventoryApiAPI.InventoryApiListResourceInstances(
+			ctxWithToken,
+			serviceID,
+			environmentID,
+		).ProductTierId(productTierID).ExcludeDetail(true)
+		if nextPageToken != "" {
+			req = req.NextPageToken(nextPageToken)
+		}
+
+		res, r, err := req.Execute()
+		if r != nil {
+			_ = r.Body.Close()
+		}
+		if err != nil {
+			return nil, handleFleetError(err)
+		}
+
+		instances = append(instances, res.ResourceInstances...)
+		nextPageToken = res.GetNextPageToken()
+		if nextPageToken == "" {
+			return instances, nil
+		}
+	}
+}
@@ -2,8 +2,10 @@ package dataaccess
 
 import (
 	"context"
-	"github.com/pkg/errors"
 	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
 
 	openapiclientfleet "github.com/omnistrate-oss/omnistrate-sdk-go/fleet"
 )
@@ -119,3 +121,109 @@ func GetSubscriptionByCustomerEmail(ctx context.Context, token string, serviceID
 	err = errors.New("no subscription found for the given customer email or the plan does not exist")
 	return
 }
+
+func CreateSubscriptionOnBehalfOfCustomer(ctx context.Context, token string, serviceID, environmentID, planID, customerUserID string) (subscriptionID string, err error) {
+	ctxWithToken := context.WithValue(ctx, openapiclientfleet.ContextAccessToken, token)
+	apiClient := getFleetClient()
+
+	req := apiClient.InventoryApiAPI.InventoryApiCreateSubscriptionOnBehalfOfCustomer(
+		ctxWithToken,
+		serviceID,
+		environmentID,
+	).FleetCreateSubscriptionOnBehalfOfCustomerRequest2(openapiclientfleet.FleetCreateSubscriptionOnBehalfOfCustomerRequest2{
+		ProductTierId:            planID,
+		OnBehalfOfCustomerUserId: customerUserID,
+	})
+
+	resp, r, err := req.Execute()
+	defer func() {
+		if r != nil {
+			_ = r.Body.Close()
+		}
+	}()
+	if err != nil {
+		return "", handleFleetError(err)
+	}
+	return resp.GetId(), nil
+}
+
+func SuspendSubscription(ctx context.Context, token string, serviceID, environmentID, subscriptionID string) error {
+	ctxWithToken := context.WithValue(ctx, openapiclientfleet.ContextAccessToken, token)
+	apiClient := getFleetClient()
+
+	r, err := apiClient.InventoryApiAPI.InventoryApiSuspendSubscription(ctxWithToken, serviceID, environmentID, subscriptionID).Execute()
+	defer func() {
+		if r != nil {
+			_ = r.Body.Close()
+		}
+	}()
+	if err != nil {
+		return handleFleetError(err)
+	}
+	return nil
+}
+
+func ResumeSubscription(ctx context.Context, token string, serviceID, environmentID, subscriptionID string) error {
+	ctxWithToken := context.WithValue(ctx, openapiclientfleet.ContextAccessToken, token)
+	apiClient := getFleetClient()
+
+	r, err := apiClient.InventoryApiAPI.InventoryApiResumeSubscription(ctxWithToken, serviceID, environmentID, subscriptionID).Execute()
+	defer func() {
+		if r != nil {
+			_ = r.Body.Close()
+		}
+	}()
+	if err != nil {
+		return handleFleetError(err)
+	}
+	return nil
+}
+
+func TerminateSubscription(ctx context.Context, token string, serviceID, environmentID, subscriptionID string) error {
+	ctxWithToken := context.WithValue(ctx, openapiclientfleet.ContextAccessToken, token)
+	apiClient := getFleetClient()
+
+	r, err := apiClient.InventoryApiAPI.InventoryApiTerminateSubscription(ctxWithToken, serviceID, environmentID, subscriptionID).Execute()
+	defer func() {
+		if r != nil {
+			_ = r.Body.Close()
+		}
+	}()
+	if err != nil {
+		return handleFleetError(err)
+	}
+	return nil
+}
+
+// GetUserIDByEmail returns the ID of the customer user of the service with the email.
+func GetUserIDByEmail(ctx context.Context, token string, serviceID, email string) (string, error) {
+	ctxWithToken := context.WithValue(ctx, openapiclientfleet.ContextAccessToken, token)
+	apiClient := getFleetClient()
+
+	var nextPageToken string
+	for {
+		req := apiClient.InventoryApiAPI.InventoryApiListAllUsers(ctxWithToken).ServiceId(serviceID)
+		if nextPageToken != "" {
+			req = req.NextPageToken(nextPageToken)
+		}
+
+		resp, r, err := req.Execute()
+		if r != nil {
+			_ = r.Body.Close()
+		}
+		if err != nil {
+			return "", handleFleetError(errors.Wrap(err, "failed to list users"))
+		}
+
+		for _, user := range resp.Users {
+			if strings.EqualFold(user.GetEmail(), email) {
+				return user.GetUserId(), nil
+			}
+		}
+
+		nextPageToken = resp.GetNextPageToken()
+		if nextPageToken == "" {
+			return "", errors.Errorf("no user found with email %s", email)
+		}
+	}
+}
@@ -14,3 +14,10 @@ type CustomNetwork struct {
 	GcpProjectNumber             string `json:"gcp_project_number"`
 	HostClusterID                string `json:"host_cluster_id"`
 }
+
+type CIDRBlock struct {
+	CIDR      string `json:"cidr"`
+	FirstIP   string `json:"first_ip"`
+	LastIP    string `json:"last_ip"`
+	Addresses uint64 `json:"addresses"`
+}
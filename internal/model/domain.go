@@ -7,3 +7,13 @@ type Domain struct {
 	Status          string `json:"status"`
 	ClusterEndpoint string `json:"cluster_endpoint"`
 }
+
+type DomainRecordCheck struct {
+	Record   string `json:"record"`
+	Expected string `json:"expected"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	TTL      uint32 `json:"ttl"`
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+}
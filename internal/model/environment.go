@@ -30,3 +30,31 @@ type Promotion struct {
 	TargetEnvName         string `json:"target_env_name"`
 	PromoteStatus         string `json:"promote_status"`
 }
+
+type PromotionGate struct {
+	Gate   string `json:"gate"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail"`
+}
+
+type PromotionDefaultVersion struct {
+	TargetEnvName string `json:"target_env_name"`
+	PlanName      string `json:"plan_name"`
+	Version       string `json:"version"`
+}
+
+type EnvironmentDiff struct {
+	ServiceID       string              `json:"service_id"`
+	ServiceName     string              `json:"service_name"`
+	FromEnvironment string              `json:"from_environment"`
+	ToEnvironment   string              `json:"to_environment"`
+	Changes         []EnvironmentChange `json:"changes"`
+}
+
+type EnvironmentChange struct {
+	Category string `json:"category"`
+	Subject  string `json:"subject"`
+	Change   string `json:"change"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
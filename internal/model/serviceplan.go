@@ -76,3 +76,38 @@ type Resource struct {
 	L7LoadBalancerConfiguration any    `json:"l7_load_balancer_configuration,omitempty"`
 	OperatorCRDConfiguration    any    `json:"operator_crd_configuration,omitempty"`
 }
+
+type ServicePlanVersionReport struct {
+	GeneratedAt    string                    `json:"generated_at"`
+	ServiceID      string                    `json:"service_id"`
+	ServiceName    string                    `json:"service_name"`
+	PlanName       string                    `json:"plan_name"`
+	TotalInstances int                       `json:"total_instances"`
+	Versions       []ServicePlanVersionUsage `json:"versions"`
+}
+
+type ServicePlanVersionUsage struct {
+	Environment             string         `json:"environment"`
+	PlanID                  string         `json:"plan_id"`
+	Version                 string         `json:"version"`
+	VersionName             string         `json:"version_name,omitempty"`
+	VersionStatus           string         `json:"version_status"`
+	ReleasedAt              string         `json:"released_at,omitempty"`
+	Instances               int            `json:"instances"`
+	InstancesByCloud        map[string]int `json:"instances_by_cloud"`
+	InstancesByRegion       map[string]int `json:"instances_by_region"`
+	InstancesByStatus       map[string]int `json:"instances_by_status"`
+	OldestInstanceCreatedAt string         `json:"oldest_instance_created_at,omitempty"`
+	OldestInstanceAgeDays   int            `json:"oldest_instance_age_days"`
+}
+
+type ServicePlanVersionUsageRow struct {
+	Environment       string `json:"environment"`
+	Version           string `json:"version"`
+	VersionStatus     string `json:"version_status"`
+	Instances         int    `json:"instances"`
+	Clouds            string `json:"clouds"`
+	Regions           string `json:"regions"`
+	Statuses          string `json:"statuses"`
+	OldestInstanceAge string `json:"oldest_instance_age"`
+}
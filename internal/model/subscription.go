@@ -11,3 +11,17 @@ type Subscription struct {
 	SubscriptionOwnerEmail string `json:"subscription_owner_email"`
 	Status                 string `json:"status"`
 }
+
+type SubscriptionInstance struct {
+	InstanceID             string `json:"instance_id"`
+	SubscriptionID         string `json:"subscription_id"`
+	SubscriptionOwnerEmail string `json:"subscription_owner_email"`
+	Service                string `json:"service"`
+	Environment            string `json:"environment"`
+	Plan                   string `json:"plan"`
+	Version                string `json:"version"`
+	Resource               string `json:"resource"`
+	CloudProvider          string `json:"cloud_provider"`
+	Region                 string `json:"region"`
+	Status                 string `json:"status"`
+}
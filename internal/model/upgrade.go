@@ -17,6 +17,9 @@ const (
 	Verifying  UpgradePathStatus = "VERIFYING"
 )
 
+// UpgradePathStatuses lists all upgrade path statuses
+var UpgradePathStatuses = []UpgradePathStatus{InProgress, Scheduled, Complete, Failed, Cancelled, Skipped, Verifying}
+
 type UpgradeStatus struct {
 	UpgradeID      string `json:"upgrade_id"`
 	Total          int64  `json:"total"`
@@ -30,6 +33,24 @@ type UpgradeStatus struct {
 	NotifyCustomer bool   `json:"notify_customer"`
 }
 
+type UpgradePathSummary struct {
+	UpgradeID     string `json:"upgrade_id"`
+	Service       string `json:"service"`
+	Plan          string `json:"plan"`
+	Environment   string `json:"environment"`
+	SourceVersion string `json:"source_version"`
+	TargetVersion string `json:"target_version"`
+	Status        string `json:"status"`
+	Total         int64  `json:"total"`
+	Pending       int64  `json:"pending"`
+	InProgress    int64  `json:"in_progress"`
+	Completed     int64  `json:"completed"`
+	Failed        int64  `json:"failed"`
+	Skipped       int64  `json:"skipped"`
+	CreatedAt     string `json:"created_at"`
+	CompletedAt   string `json:"completed_at"`
+}
+
 type Upgrade struct {
 	UpgradeID      string  `json:"upgrade_id"`
 	SourceVersion  string  `json:"source_version"`
@@ -46,6 +67,23 @@ type UpgradeStatusDetail struct {
 	PlannedExecutionDate *string `json:"planned_execution_date"`
 	UpgradeEndTime       string  `json:"upgrade_end_time"`
 	UpgradeStatus        string  `json:"upgrade_status"`
+	Duration             string  `json:"duration"`
+	Slow                 bool    `json:"slow"`
+}
+
+type UpgradeTimingSummary struct {
+	UpgradeID             string  `json:"upgrade_id"`
+	Total                 int     `json:"total"`
+	Finished              int     `json:"finished"`
+	InProgress            int     `json:"in_progress"`
+	Pending               int     `json:"pending"`
+	P50Duration           string  `json:"p50_duration"`
+	P90Duration           string  `json:"p90_duration"`
+	MaxDuration           string  `json:"max_duration"`
+	ThroughputPerHour     float64 `json:"throughput_per_hour"`
+	ETA                   string  `json:"eta"`
+	EstimatedCompletionAt string  `json:"estimated_completion_at"`
+	SlowInstances         int     `json:"slow_instances"`
 }
 
 type UpgradeMaintenanceAction string
@@ -76,3 +114,42 @@ const (
 	CompletionStatusCancelled = "cancelled"
 	CompletionStatusSkipped   = "skipped"
 )
+
+type UpgradePreview struct {
+	InstanceID        string `json:"instance_id"`
+	Service           string `json:"service"`
+	Environment       string `json:"environment"`
+	Plan              string `json:"plan"`
+	Status            string `json:"status"`
+	SourceVersion     string `json:"source_version"`
+	TargetVersion     string `json:"target_version"`
+	ScheduledDate     string `json:"scheduled_date,omitempty"`
+	MaintenanceWindow string `json:"maintenance_window,omitempty"`
+}
+
+type UpgradeRollbackInstance struct {
+	UpgradeID         string `json:"upgrade_id"`
+	InstanceID        string `json:"instance_id"`
+	UpgradeStatus     string `json:"upgrade_status"`
+	CurrentVersion    string `json:"current_version"`
+	RollbackVersion   string `json:"rollback_version"`
+	RollbackUpgradeID string `json:"rollback_upgrade_id"`
+	Note              string `json:"note,omitempty"`
+}
+
+type MaintenanceWindow struct {
+	Scope           string `json:"scope"`
+	Target          string `json:"target"`
+	Window          string `json:"window"`
+	NextWindowStart string `json:"next_window_start"`
+}
+
+type UpgradeCheckIssue struct {
+	InstanceID    string `json:"instance_id"`
+	SourceVersion string `json:"source_version"`
+	TargetVersion string `json:"target_version"`
+	Severity      string `json:"severity"`
+	Category      string `json:"category"`
+	Subject       string `json:"subject"`
+	Message       string `json:"message"`
+}
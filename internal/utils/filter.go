@@ -7,17 +7,27 @@ import (
 	"strings"
 )
 
-func GetSupportedFilterKeys[T any](obj T) (supportedFilterKeys []string) {
+// FilterKeysOption changes which fields GetSupportedFilterKeys returns.
+type FilterKeysOption int
+
+const (
+	// ScalarFieldsOnly leaves out slices, maps and nested structs, which filter expressions and list options can't
+	// match on. ParseFilters keys don't use it.
+	ScalarFieldsOnly FilterKeysOption = iota
+)
+
+func GetSupportedFilterKeys[T any](obj T, opts ...FilterKeysOption) (supportedFilterKeys []string) {
 	objValue := reflect.ValueOf(obj)
 	if objValue.Kind() != reflect.Struct {
 		return
 	}
 
+	scalarFieldsOnly := slices.Contains(opts, ScalarFieldsOnly)
 	objType := objValue.Type()
 	for i := 0; i < objType.NumField(); i++ {
 		field := objType.Field(i)
 		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" {
+		if jsonTag != "" && (!scalarFieldsOnly || isFilterableType(field.Type)) {
 			// jsonTag may have options, e.g., "name,omitempty"
 			parts := strings.Split(jsonTag, ",")
 			jsonTag = parts[0] // Use the first part as the JSON field name
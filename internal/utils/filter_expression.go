@@ -0,0 +1,666 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// FilterExpressionSyntax describes the filter expression syntax for flag usage and command help.
+const FilterExpressionSyntax = `Filter expressions compare a key with a value using = (equals), != (not equals), : (equals, case-insensitive), like / ilike (glob match with * and ?, ilike is case-insensitive), =~ / !~ (regular expression match / no match), and in (...) / not in (...) (one of a list of values). Comparisons can be combined with and (or ','), or and not, and grouped with parentheses. Quote values containing spaces, commas, parentheses or quotes with "..." or '...'. Keys without a value match no comparison, not even != or not in. Filters of the key:value[,key:value] form, whose values may contain spaces, are also accepted. Repeated filter flags are combined with or.`
+
+// FilterExpression is a parsed filter expression that can be matched against structs, using their JSON field names as keys.
+type FilterExpression struct {
+	source string
+	root   filterNode
+}
+
+// FilterSyntaxError reports an invalid filter expression along with the position of the error.
+type FilterSyntaxError struct {
+	Filter   string
+	Position int // 0-based byte offset into Filter
+	Message  string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s\n  %s\n  %s^", e.Position+1, e.Message, e.Filter, strings.Repeat(" ", e.Position))
+}
+
+func (f *FilterExpression) String() string {
+	return f.source
+}
+
+// UsesKey reports whether the expression compares the given key.
+func (f *FilterExpression) UsesKey(key string) bool {
+	return filterNodeUsesKey(f.root, key)
+}
+
+func filterNodeUsesKey(node filterNode, key string) bool {
+	switch n := node.(type) {
+	case *filterAndNode:
+		return filterNodeUsesKey(n.left, key) || filterNodeUsesKey(n.right, key)
+	case *filterOrNode:
+		return filterNodeUsesKey(n.left, key) || filterNodeUsesKey(n.right, key)
+	case *filterNotNode:
+		return filterNodeUsesKey(n.operand, key)
+	case *filterComparisonNode:
+		return n.key == key
+	default:
+		return false
+	}
+}
+
+// ParseFilterExpressions parses each filter into an expression, validating the keys against supportedFilterKeys.
+func ParseFilterExpressions(filters []string, supportedFilterKeys []string) (expressions []*FilterExpression, err error) {
+	expressions = make([]*FilterExpression, 0)
+	for _, filter := range filters {
+		if filter == "[]" {
+			continue // Those are empty filters that are reset to default values when clean up args and flags
+		}
+
+		var expression *FilterExpression
+		if expression, err = ParseFilterExpression(filter, supportedFilterKeys); err != nil {
+			return
+		}
+		expressions = append(expressions, expression)
+	}
+	return
+}
+
+// ParseFilterExpression parses a single filter expression, validating the keys against supportedFilterKeys.
+func ParseFilterExpression(filter string, supportedFilterKeys []string) (*FilterExpression, error) {
+	p := &filterParser{input: filter, supportedFilterKeys: supportedFilterKeys}
+
+	p.skipSpace()
+	if p.eof() {
+		return nil, p.errorf(p.pos, "filter is empty")
+	}
+
+	root, err := p.parseOr()
+	if err == nil {
+		p.skipSpace()
+		if !p.eof() {
+			if p.peek() == ')' {
+				err = p.errorf(p.pos, "unexpected ')' without a matching '('")
+			} else {
+				err = p.errorf(p.pos, "unexpected %q, expected and, or or the end of the filter", p.rest())
+			}
+		}
+	}
+	if err != nil {
+		// Filters accepted by ParseFilters, whose values may contain spaces, keep working
+		if legacyRoot, ok := parseLegacyFilter(filter, supportedFilterKeys); ok {
+			return &FilterExpression{source: filter, root: legacyRoot}, nil
+		}
+		return nil, err
+	}
+
+	return &FilterExpression{source: filter, root: root}, nil
+}
+
+// parseLegacyFilter parses a filter of the key:value[,key:value] form of ParseFilters, where a value is the rest of
+// its term up to the next comma. Values containing filter expression operators aren't read as legacy values.
+func parseLegacyFilter(filter string, supportedFilterKeys []string) (filterNode, bool) {
+	var root filterNode
+	for _, part := range strings.Split(filter, ",") {
+		keyValue := strings.Split(part, ":")
+		if len(keyValue) != 2 || !slices.Contains(supportedFilterKeys, keyValue[0]) || hasFilterOperators(keyValue[1]) {
+			return nil, false
+		}
+
+		var node filterNode = &filterComparisonNode{key: keyValue[0], op: filterOpEqualsIgnoreCase, values: []string{keyValue[1]}}
+		if root != nil {
+			node = &filterAndNode{left: root, right: node}
+		}
+		root = node
+	}
+	return root, true
+}
+
+func hasFilterOperators(value string) bool {
+	if strings.ContainsAny(value, "=!~()\"'") || strings.Contains(value, "&&") || strings.Contains(value, "||") {
+		return true
+	}
+	for _, word := range strings.Fields(strings.ToLower(value)) {
+		switch word {
+		case "and", "or", "not", "in", "like", "ilike":
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesFilterExpressions reports whether obj matches at least one of the expressions. No expressions match everything.
+func MatchesFilterExpressions[T any](obj T, expressions []*FilterExpression) (bool, error) {
+	if len(expressions) == 0 {
+		return true, nil
+	}
+
+	fields, err := filterFieldValues(obj)
+	if err != nil {
+		return false, err
+	}
+
+	for _, expression := range expressions {
+		matches, err := expression.root.eval(fields)
+		if err != nil {
+			return false, err
+		}
+		if matches {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// filterFieldValues returns the string values of the filterable fields of obj, keyed by JSON field name. Nil pointers
+// have a nil value.
+func filterFieldValues(obj any) (map[string]*string, error) {
+	objValue := reflect.ValueOf(obj)
+	if objValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("obj must be a struct")
+	}
+
+	fields := make(map[string]*string)
+	objType := objValue.Type()
+	for i := 0; i < objType.NumField(); i++ {
+		jsonTag := objType.Field(i).Tag.Get("json")
+		if jsonTag == "" {
+			continue
+		}
+		if value, ok := filterFieldValue(objValue.Field(i)); ok {
+			fields[strings.Split(jsonTag, ",")[0]] = value
+		}
+	}
+
+	return fields, nil
+}
+
+// filterFieldValue formats scalar fields, pointers to scalars and fmt.Stringers for matching. Nil pointers have no
+// value, so that, as with ParseFilters, they match no comparison.
+func filterFieldValue(field reflect.Value) (*string, bool) {
+	if !field.IsValid() {
+		return nil, false
+	}
+
+	if field.CanInterface() {
+		if stringer, ok := field.Interface().(fmt.Stringer); ok {
+			if field.Kind() == reflect.Ptr && field.IsNil() {
+				return nil, true
+			}
+			value := stringer.String()
+			return &value, true
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		value := field.String()
+		return &value, true
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		value := fmt.Sprint(field.Interface())
+		return &value, true
+	case reflect.Ptr:
+		if !isFilterableType(field.Type().Elem()) {
+			return nil, false
+		}
+		if field.IsNil() {
+			return nil, true
+		}
+		return filterFieldValue(field.Elem())
+	default:
+		return nil, false
+	}
+}
+
+func isFilterableType(t reflect.Type) bool {
+	if t.Implements(reflect.TypeOf((*fmt.Stringer)(nil)).Elem()) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Ptr:
+		return isFilterableType(t.Elem())
+	default:
+		return false
+	}
+}
+
+type filterOperator string
+
+const (
+	filterOpEquals            filterOperator = "="
+	filterOpDoubleEquals      filterOperator = "=="
+	filterOpNotEquals         filterOperator = "!="
+	filterOpEqualsIgnoreCase  filterOperator = ":"
+	filterOpRegexMatch        filterOperator = "=~"
+	filterOpRegexNotMatch     filterOperator = "!~"
+	filterOpLike              filterOperator = "like"
+	filterOpNotLike           filterOperator = "not like"
+	filterOpLikeIgnoreCase    filterOperator = "ilike"
+	filterOpNotLikeIgnoreCase filterOperator = "not ilike"
+	filterOpIn                filterOperator = "in"
+	filterOpNotIn             filterOperator = "not in"
+)
+
+// filterSymbolOperators are tried in order, so longer operators sharing a prefix come first.
+var filterSymbolOperators = []filterOperator{
+	filterOpDoubleEquals,
+	filterOpRegexMatch,
+	filterOpNotEquals,
+	filterOpRegexNotMatch,
+	filterOpEquals,
+	filterOpEqualsIgnoreCase,
+}
+
+// filterKeywordOperators are operators made of one or more keywords.
+var filterKeywordOperators = []filterOperator{
+	filterOpNotLikeIgnoreCase,
+	filterOpNotLike,
+	filterOpNotIn,
+	filterOpLikeIgnoreCase,
+	filterOpLike,
+	filterOpIn,
+}
+
+type filterNode interface {
+	eval(fields map[string]*string) (bool, error)
+}
+
+type filterAndNode struct {
+	left, right filterNode
+}
+
+type filterOrNode struct {
+	left, right filterNode
+}
+
+type filterNotNode struct {
+	operand filterNode
+}
+
+type filterComparisonNode struct {
+	key     string
+	op      filterOperator
+	values  []string
+	pattern *regexp.Regexp // For the glob and regular expression operators
+}
+
+func (n *filterAndNode) eval(fields map[string]*string) (bool, error) {
+	left, err := n.left.eval(fields)
+	if err != nil || !left {
+		return false, err
+	}
+	return n.right.eval(fields)
+}
+
+func (n *filterOrNode) eval(fields map[string]*string) (bool, error) {
+	left, err := n.left.eval(fields)
+	if err != nil || left {
+		return left, err
+	}
+	return n.right.eval(fields)
+}
+
+func (n *filterNotNode) eval(fields map[string]*string) (bool, error) {
+	matches, err := n.operand.eval(fields)
+	return !matches, err
+}
+
+func (n *filterComparisonNode) eval(fields map[string]*string) (bool, error) {
+	fieldValue, found := fields[n.key]
+	if !found {
+		return false, fmt.Errorf("invalid JSON field name: %s", n.key)
+	}
+	if fieldValue == nil {
+		return false, nil
+	}
+	value := *fieldValue
+
+	switch n.op {
+	case filterOpEquals, filterOpDoubleEquals:
+		return value == n.values[0], nil
+	case filterOpNotEquals:
+		return value != n.values[0], nil
+	case filterOpEqualsIgnoreCase:
+		return strings.EqualFold(value, n.values[0]), nil
+	case filterOpRegexMatch, filterOpLike, filterOpLikeIgnoreCase:
+		return n.pattern.MatchString(value), nil
+	case filterOpRegexNotMatch, filterOpNotLike, filterOpNotLikeIgnoreCase:
+		return !n.pattern.MatchString(value), nil
+	case filterOpIn:
+		return slices.Contains(n.values, value), nil
+	case filterOpNotIn:
+		return !slices.Contains(n.values, value), nil
+	default:
+		return false, fmt.Errorf("unsupported filter operator %s", n.op)
+	}
+}
+
+// globToRegexp converts a glob pattern with * and ? wildcards into an anchored regular expression.
+func globToRegexp(glob string, ignoreCase bool) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	if ignoreCase {
+		sb.WriteString("(?i)")
+	}
+	sb.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// filterParser is a recursive descent parser for filter expressions:
+//
+//	or         = and { ("or" | "||") and }
+//	and        = not { ("and" | "&&" | ",") not }
+//	not        = ("not" | "!") not | primary
+//	primary    = "(" or ")" | comparison
+//	comparison = key operator value | key ["not"] "in" "(" value { "," value } ")"
+type filterParser struct {
+	input               string
+	pos                 int
+	supportedFilterKeys []string
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.matchKeyword("or") || p.matchSymbol("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterOrNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.matchKeyword("and") || p.matchSymbol("&&") || p.matchSymbol(",") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &filterAndNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (filterNode, error) {
+	if p.matchKeyword("not") || (!p.lookingAtSymbol("!=") && !p.lookingAtSymbol("!~") && p.matchSymbol("!")) {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNotNode{operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (filterNode, error) {
+	p.skipSpace()
+	if p.eof() {
+		return nil, p.errorf(p.pos, "expected a key or '('")
+	}
+
+	if open := p.pos; p.matchSymbol("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.matchSymbol(")") {
+			if p.eof() {
+				return nil, p.errorf(open, "missing ')' for this '('")
+			}
+			return nil, p.errorf(p.pos, "expected ')'")
+		}
+		return node, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	p.skipSpace()
+	keyPos := p.pos
+	key := p.readIdentifier()
+	if key == "" {
+		return nil, p.errorf(keyPos, "expected a key, found %q", p.rest())
+	}
+	if !slices.Contains(p.supportedFilterKeys, key) {
+		return nil, p.errorf(keyPos, "unsupported filter key: %s. Supported keys: %s", key, strings.Join(p.supportedFilterKeys, ", "))
+	}
+
+	p.skipSpace()
+	opPos := p.pos
+	op, found := p.readOperator()
+	if !found {
+		return nil, p.errorf(opPos, "expected an operator after %s, one of =, !=, :, like, ilike, =~, !~, in or not in", key)
+	}
+
+	node := &filterComparisonNode{key: key, op: op}
+	if op == filterOpIn || op == filterOpNotIn {
+		values, err := p.readValueList()
+		if err != nil {
+			return nil, err
+		}
+		node.values = values
+		return node, nil
+	}
+
+	p.skipSpace()
+	valuePos := p.pos
+	value, err := p.readValue()
+	if err != nil {
+		return nil, err
+	}
+	node.values = []string{value}
+
+	switch op {
+	case filterOpRegexMatch, filterOpRegexNotMatch:
+		if node.pattern, err = regexp.Compile(value); err != nil {
+			return nil, p.errorf(valuePos, "invalid regular expression: %v", err)
+		}
+	case filterOpLike, filterOpNotLike, filterOpLikeIgnoreCase, filterOpNotLikeIgnoreCase:
+		ignoreCase := op == filterOpLikeIgnoreCase || op == filterOpNotLikeIgnoreCase
+		if node.pattern, err = globToRegexp(value, ignoreCase); err != nil {
+			return nil, p.errorf(valuePos, "invalid glob pattern: %v", err)
+		}
+	}
+
+	return node, nil
+}
+
+func (p *filterParser) readOperator() (filterOperator, bool) {
+	for _, op := range filterSymbolOperators {
+		if p.matchSymbol(string(op)) {
+			return op, true
+		}
+	}
+
+	start := p.pos
+	for _, op := range filterKeywordOperators {
+		matched := true
+		for _, word := range strings.Fields(string(op)) {
+			if !p.matchKeyword(word) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return op, true
+		}
+		p.pos = start
+	}
+
+	return "", false
+}
+
+func (p *filterParser) readValueList() ([]string, error) {
+	p.skipSpace()
+	open := p.pos
+	if !p.matchSymbol("(") {
+		return nil, p.errorf(p.pos, "expected '(' to start the list of values")
+	}
+
+	var values []string
+	for {
+		p.skipSpace()
+		value, err := p.readValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		if p.matchSymbol(",") {
+			continue
+		}
+		if p.matchSymbol(")") {
+			return values, nil
+		}
+		if p.eof() {
+			return nil, p.errorf(open, "missing ')' for this list of values")
+		}
+		return nil, p.errorf(p.pos, "expected ',' or ')' in the list of values")
+	}
+}
+
+// readValue reads a quoted value, or an unquoted value up to the next space, comma, parenthesis, && or ||.
+func (p *filterParser) readValue() (string, error) {
+	if p.eof() {
+		return "", p.errorf(p.pos, "expected a value")
+	}
+
+	if quote := p.peek(); quote == '"' || quote == '\'' {
+		open := p.pos
+		p.pos++
+		var sb strings.Builder
+		for !p.eof() {
+			c := p.input[p.pos]
+			switch {
+			case c == '\\' && p.pos+1 < len(p.input):
+				sb.WriteByte(p.input[p.pos+1])
+				p.pos += 2
+			case c == quote:
+				p.pos++
+				return sb.String(), nil
+			default:
+				sb.WriteByte(c)
+				p.pos++
+			}
+		}
+		return "", p.errorf(open, "unterminated quoted value")
+	}
+
+	start := p.pos
+	for !p.eof() {
+		c := p.input[p.pos]
+		if unicode.IsSpace(rune(c)) || c == ',' || c == '(' || c == ')' || p.lookingAtSymbol("&&") || p.lookingAtSymbol("||") {
+			break
+		}
+		if c == '"' || c == '\'' {
+			return "", p.errorf(p.pos, "unexpected quote inside an unquoted value, quote the whole value instead")
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf(p.pos, "expected a value")
+	}
+
+	return p.input[start:p.pos], nil
+}
+
+func (p *filterParser) readIdentifier() string {
+	start := p.pos
+	for !p.eof() {
+		c := rune(p.input[p.pos])
+		if !(unicode.IsLetter(c) || c == '_' || (p.pos > start && unicode.IsDigit(c))) {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// matchKeyword consumes a case-insensitive keyword that isn't followed by an identifier character.
+func (p *filterParser) matchKeyword(keyword string) bool {
+	p.skipSpace()
+	end := p.pos + len(keyword)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], keyword) {
+		return false
+	}
+	if end < len(p.input) {
+		if c := rune(p.input[end]); unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' {
+			return false
+		}
+	}
+	p.pos = end
+	return true
+}
+
+func (p *filterParser) matchSymbol(symbol string) bool {
+	p.skipSpace()
+	if p.lookingAtSymbol(symbol) {
+		p.pos += len(symbol)
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) lookingAtSymbol(symbol string) bool {
+	return strings.HasPrefix(p.input[p.pos:], symbol)
+}
+
+func (p *filterParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *filterParser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *filterParser) rest() string {
+	return p.input[p.pos:]
+}
+
+func (p *filterParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *filterParser) errorf(pos int, format string, args ...any) error {
+	return &FilterSyntaxError{Filter: p.input, Position: pos, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesFilterExpressions(t *testing.T) {
+	require := require.New(t)
+
+	instance := model.Instance{
+		InstanceID:    "instance-123",
+		Service:       "Postgres",
+		Environment:   "Production",
+		Plan:          "premium",
+		Version:       "1.2",
+		Resource:      "writer",
+		CloudProvider: "aws",
+		Region:        "us-east-1",
+		Status:        "RUNNING",
+	}
+
+	tests := []struct {
+		name    string
+		filters []string
+		ok      bool
+	}{
+		{name: "No filters", filters: []string{}, ok: true},
+		{name: "Legacy key:value is case-insensitive", filters: []string{"service:postgres,environment:production"}, ok: true},
+		{name: "Legacy filters are ORed", filters: []string{"service:mysql", "region:us-east-1"}, ok: true},
+		{name: "Legacy value with colon", filters: []string{"instance_id:instance-123:x"}, ok: false},
+		{name: "Equals is case-sensitive", filters: []string{"service = postgres"}, ok: false},
+		{name: "Double equals", filters: []string{"service == Postgres"}, ok: true},
+		{name: "Not equals", filters: []string{"status != FAILED"}, ok: true},
+		{name: "Glob", filters: []string{"region like us-*-1"}, ok: true},
+		{name: "Glob must match the whole value", filters: []string{"region like us-east"}, ok: false},
+		{name: "Glob no match", filters: []string{"region like eu-*"}, ok: false},
+		{name: "Case-insensitive glob", filters: []string{"service ilike post*"}, ok: true},
+		{name: "Not like", filters: []string{"service not like post*"}, ok: true},
+		{name: "Regex", filters: []string{`version =~ "^1\\.[0-9]+$"`}, ok: true},
+		{name: "Case-insensitive regex", filters: []string{`status =~ "(?i)^running$"`}, ok: true},
+		{name: "Negated regex", filters: []string{"status !~ FAIL"}, ok: true},
+		{name: "In", filters: []string{"cloud_provider in (gcp, aws)"}, ok: true},
+		{name: "Not in", filters: []string{"cloud_provider not in (gcp, azure)"}, ok: true},
+		{name: "In with quoted values", filters: []string{`plan in ("basic, free", 'premium')`}, ok: true},
+		{name: "And", filters: []string{"service = Postgres and status = STOPPED"}, ok: false},
+		{name: "Or", filters: []string{"service = MySQL or status = RUNNING"}, ok: true},
+		{name: "Symbols", filters: []string{"service = MySQL || (status = RUNNING && region = us-east-1)"}, ok: true},
+		{name: "Not", filters: []string{"not (service = MySQL or status = STOPPED)"}, ok: true},
+		{name: "Bang", filters: []string{"!service:postgres"}, ok: false},
+		{name: "And binds tighter than or", filters: []string{"service = MySQL and status = RUNNING or region = us-east-1"}, ok: true},
+		{name: "Keywords are case-insensitive", filters: []string{"service:postgres AND NOT status IN (FAILED)"}, ok: true},
+		{name: "Quoted value with spaces and escapes", filters: []string{`resource != "my \"writer\" (primary)"`}, ok: true},
+		{name: "Empty quoted value", filters: []string{`subscription_id = ""`}, ok: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expressions, err := ParseFilterExpressions(test.filters, GetSupportedFilterKeys(model.Instance{}, ScalarFieldsOnly))
+			require.NoError(err)
+
+			got, err := MatchesFilterExpressions(instance, expressions)
+			require.NoError(err)
+			require.Equal(test.ok, got, "filters %v", test.filters)
+		})
+	}
+}
+
+func TestLegacyFilterValuesWithSpaces(t *testing.T) {
+	require := require.New(t)
+
+	instance := model.Instance{Plan: "Premium Plan", Status: "RUNNING", Region: "us-east-1"}
+	supportedFilterKeys := GetSupportedFilterKeys(model.Instance{}, ScalarFieldsOnly)
+
+	for filter, expected := range map[string]bool{
+		"plan:premium plan":                  true,
+		"plan:Premium Plan,status:RUNNING":   true,
+		"plan:Premium Plan,status:STOPPED":   false,
+		"status:RUNNING,plan:Basic Plan":     false,
+		"plan = 'Premium Plan' and status:x": false,
+	} {
+		expression, err := ParseFilterExpression(filter, supportedFilterKeys)
+		require.NoError(err, filter)
+
+		got, err := MatchesFilterExpressions(instance, []*FilterExpression{expression})
+		require.NoError(err)
+		require.Equal(expected, got, filter)
+	}
+
+	// Values with expression operators are parsed as expressions
+	for _, filter := range []string{"plan:Premium Plan and status = RUNNING", "plan:Premium (Plan)", "plan:Premium Plan in x"} {
+		_, err := ParseFilterExpression(filter, supportedFilterKeys)
+		require.Error(err, filter)
+	}
+}
+
+func TestParseFilterExpressionErrors(t *testing.T) {
+	require := require.New(t)
+
+	supportedFilterKeys := []string{"service", "environment"}
+	tests := []struct {
+		name             string
+		filter           string
+		expectedPosition int
+		expectedErrorMsg string
+	}{
+		{name: "Empty", filter: "  ", expectedPosition: 2, expectedErrorMsg: "filter is empty"},
+		{name: "Unsupported key", filter: "service:s1 and plan:p1", expectedPosition: 15, expectedErrorMsg: "unsupported filter key: plan"},
+		{name: "Missing operator", filter: "service s1", expectedPosition: 8, expectedErrorMsg: "expected an operator after service"},
+		{name: "Missing value", filter: "service =", expectedPosition: 9, expectedErrorMsg: "expected a value"},
+		{name: "Missing key", filter: "service:s1 and", expectedPosition: 14, expectedErrorMsg: "expected a key or '('"},
+		{name: "Unterminated quote", filter: `service = "s1`, expectedPosition: 10, expectedErrorMsg: "unterminated quoted value"},
+		{name: "Unbalanced parenthesis", filter: "(service:s1 or environment:e1", expectedPosition: 0, expectedErrorMsg: "missing ')' for this '('"},
+		{name: "Extra closing parenthesis", filter: "service:s1)", expectedPosition: 10, expectedErrorMsg: "unexpected ')' without a matching '('"},
+		{name: "Unquoted parenthesis in value", filter: "service =~ (", expectedPosition: 11, expectedErrorMsg: "expected a value"},
+		{name: "Invalid quoted regex", filter: `service =~ "a("`, expectedPosition: 11, expectedErrorMsg: "invalid regular expression"},
+		{name: "In without list", filter: "service in s1", expectedPosition: 11, expectedErrorMsg: "expected '(' to start the list of values"},
+		{name: "Unterminated list", filter: "service in (s1, s2", expectedPosition: 11, expectedErrorMsg: "missing ')' for this list of values"},
+		{name: "Trailing tokens", filter: "service:s1 environment:e1", expectedPosition: 11, expectedErrorMsg: "expected and, or or the end of the filter"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseFilterExpression(test.filter, supportedFilterKeys)
+			require.Error(err)
+			require.ErrorContains(err, test.expectedErrorMsg)
+
+			var syntaxErr *FilterSyntaxError
+			require.ErrorAs(err, &syntaxErr)
+			require.Equal(test.expectedPosition, syntaxErr.Position)
+		})
+	}
+}
+
+func TestMatchesFilterExpressionsNonStringFields(t *testing.T) {
+	require := require.New(t)
+
+	email := "user@example.com"
+	cell := model.DeploymentCell{
+		ID:                         "hc-123",
+		IsCustomDeployment:         true,
+		CurrentNumberOfDeployments: 3,
+		CustomerEmail:              &email,
+		HealthStatus:               model.DeploymentCellHealthStatus{OverallStatus: "HEALTHY"},
+	}
+
+	supportedFilterKeys := GetSupportedFilterKeys(model.DeploymentCell{}, ScalarFieldsOnly)
+	require.Contains(supportedFilterKeys, "is_custom_deployment")
+	require.Contains(supportedFilterKeys, "customer_email")
+	require.Contains(supportedFilterKeys, "health_status")
+	require.NotContains(supportedFilterKeys, "helm_packages")
+	require.NotContains(supportedFilterKeys, "custom_network")
+
+	// The keys of the commands still using ParseFilters are unchanged
+	legacyFilterKeys := GetSupportedFilterKeys(model.DeploymentCell{})
+	require.Contains(legacyFilterKeys, "helm_packages")
+	require.Contains(legacyFilterKeys, "custom_network")
+
+	for filter, expected := range map[string]bool{
+		"is_custom_deployment = true":          true,
+		"current_number_of_deployments in (3)": true,
+		"customer_email ilike *@EXAMPLE.com":   true,
+		"customer_organization_name = ''":      false,
+		"customer_organization_name != ''":     false,
+		"customer_organization_name in ('')":   false,
+		"health_status like '*HEALTHY*'":       true,
+	} {
+		expression, err := ParseFilterExpression(filter, supportedFilterKeys)
+		require.NoError(err)
+
+		got, err := MatchesFilterExpressions(cell, []*FilterExpression{expression})
+		require.NoError(err)
+		require.Equal(expected, got, filter)
+	}
+}
+
+func TestFilterExpressionUsesKey(t *testing.T) {
+	require := require.New(t)
+
+	supportedFilterKeys := GetSupportedFilterKeys(model.Instance{}, ScalarFieldsOnly)
+	expression, err := ParseFilterExpression("status = FAILED or not (plan:premium and created_at like 2025-*)", supportedFilterKeys)
+	require.NoError(err)
+	require.True(expression.UsesKey("created_at"))
+	require.True(expression.UsesKey("status"))
+	require.False(expression.UsesKey("region"))
+
+	expression, err = ParseFilterExpression("plan:Premium Plan,region:us-east-1", supportedFilterKeys)
+	require.NoError(err)
+	require.True(expression.UsesKey("region"))
+	require.False(expression.UsesKey("created_at"))
+}
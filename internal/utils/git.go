@@ -0,0 +1,242 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrGitRemoteNotFound = errors.New("git remote not found")
+
+	scpLikeGitURLRegex = regexp.MustCompile(`^(?:(?P<user>[^@/]+)@)?(?P<host>[^:/]+):(?P<path>.+)$`)
+	gitSHARegex        = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+)
+
+// GitRemote is a parsed git remote URL.
+type GitRemote struct {
+	URL   string
+	Host  string
+	Owner string // May contain nested groups, e.g. "group/subgroup" for GitLab
+	Repo  string
+}
+
+// GitRepository holds the information about the local git repository that's relevant for building a service.
+type GitRepository struct {
+	Remote    GitRemote
+	CommitSHA string
+	Branch    string // Empty when HEAD is detached
+}
+
+// WebURL returns the https URL of the repository, without credentials or the .git suffix.
+func (r GitRemote) WebURL() string {
+	return fmt.Sprintf("https://%s/%s/%s", r.Host, r.Owner, r.Repo)
+}
+
+// ShortCommitSHA returns the abbreviated commit SHA.
+func (r GitRepository) ShortCommitSHA() string {
+	return CutString(r.CommitSHA, 7)
+}
+
+// ParseGitRemoteURL parses https, http, ssh, git and scp-style (git@host:owner/repo.git) remote URLs.
+func ParseGitRemoteURL(remoteURL string) (remote GitRemote, err error) {
+	remote.URL = strings.TrimSpace(remoteURL)
+	if remote.URL == "" {
+		err = errors.New("git remote URL is empty")
+		return
+	}
+
+	var host, repoPath string
+	if strings.Contains(remote.URL, "://") {
+		var parsedURL *url.URL
+		if parsedURL, err = url.Parse(remote.URL); err != nil {
+			err = errors.Wrapf(err, "invalid git remote URL %s", remote.URL)
+			return
+		}
+		switch parsedURL.Scheme {
+		case "https", "http", "ssh", "git", "git+ssh", "ssh+git":
+		default:
+			err = fmt.Errorf("unsupported git remote URL scheme %s", parsedURL.Scheme)
+			return
+		}
+		host = parsedURL.Hostname()
+		repoPath = parsedURL.Path
+	} else {
+		matches := scpLikeGitURLRegex.FindStringSubmatch(remote.URL)
+		if matches == nil {
+			err = fmt.Errorf("invalid git remote URL %s", remote.URL)
+			return
+		}
+		host = matches[scpLikeGitURLRegex.SubexpIndex("host")]
+		repoPath = matches[scpLikeGitURLRegex.SubexpIndex("path")]
+	}
+
+	remote.Host = strings.ToLower(host)
+
+	repoPath = strings.Trim(repoPath, "/")
+	repoPath = strings.TrimSuffix(repoPath, ".git")
+
+	segments := strings.Split(repoPath, "/")
+	// Bitbucket Server serves https clones under /scm/<project>/<repo>
+	if len(segments) > 2 && segments[0] == "scm" {
+		segments = segments[1:]
+	}
+	if len(segments) < 2 || segments[0] == "" || segments[len(segments)-1] == "" {
+		err = fmt.Errorf("git remote URL %s does not contain an owner and repository name", remote.URL)
+		return
+	}
+
+	remote.Owner = strings.Join(segments[:len(segments)-1], "/")
+	remote.Repo = segments[len(segments)-1]
+	return
+}
+
+// ReadGitRepository reads the origin remote, the checked out branch and the commit SHA of the repository
+// rooted at dir without invoking the git binary.
+func ReadGitRepository(dir string) (repo GitRepository, err error) {
+	gitDir, commonDir, err := resolveGitDirs(dir)
+	if err != nil {
+		return
+	}
+
+	remoteURL, err := readGitConfigValue(filepath.Join(commonDir, "config"), `remote "origin"`, "url")
+	if err != nil {
+		return
+	}
+	if remoteURL == "" {
+		err = errors.Wrap(ErrGitRemoteNotFound, "no remote.origin.url configured")
+		return
+	}
+
+	if repo.Remote, err = ParseGitRemoteURL(remoteURL); err != nil {
+		return
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		err = errors.Wrap(err, "failed to read git HEAD")
+		return
+	}
+
+	headRef := strings.TrimSpace(string(head))
+	if !strings.HasPrefix(headRef, "ref: ") {
+		// Detached HEAD
+		repo.CommitSHA = headRef
+		return
+	}
+
+	ref := strings.TrimSpace(strings.TrimPrefix(headRef, "ref: "))
+	repo.Branch = strings.TrimPrefix(ref, "refs/heads/")
+	// The SHA is left empty for a branch without commits
+	repo.CommitSHA, err = resolveGitRef(gitDir, commonDir, ref)
+	return
+}
+
+// resolveGitDirs returns the git directory and the common directory, which differ for linked worktrees.
+func resolveGitDirs(dir string) (gitDir, commonDir string, err error) {
+	gitPath := filepath.Join(dir, ".git")
+	info, err := os.Stat(gitPath)
+	if err != nil {
+		err = errors.Wrap(err, "not a git repository")
+		return
+	}
+
+	gitDir = gitPath
+	if !info.IsDir() {
+		// Worktrees and submodules use a .git file pointing to the actual git directory
+		var data []byte
+		if data, err = os.ReadFile(gitPath); err != nil {
+			return
+		}
+		line := strings.TrimSpace(string(data))
+		if !strings.HasPrefix(line, "gitdir:") {
+			err = fmt.Errorf("invalid .git file in %s", dir)
+			return
+		}
+		gitDir = strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(dir, gitDir)
+		}
+	}
+
+	commonDir = gitDir
+	if data, readErr := os.ReadFile(filepath.Join(gitDir, "commondir")); readErr == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+
+	return
+}
+
+func resolveGitRef(gitDir, commonDir, ref string) (string, error) {
+	for _, dir := range []string{gitDir, commonDir} {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref)))
+		if err == nil {
+			sha := strings.TrimSpace(string(data))
+			if gitSHARegex.MatchString(sha) {
+				return sha, nil
+			}
+		}
+	}
+
+	packedRefs, err := os.Open(filepath.Join(commonDir, "packed-refs"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer packedRefs.Close()
+
+	scanner := bufio.NewScanner(packedRefs)
+	for scanner.Scan() {
+		sha, name, found := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if found && name == ref && gitSHARegex.MatchString(sha) {
+			return sha, nil
+		}
+	}
+
+	return "", scanner.Err()
+}
+
+// readGitConfigValue returns the value of a key in a section of a git config file, e.g. section `remote "origin"` and key url.
+func readGitConfigValue(configPath, section, key string) (string, error) {
+	configFile, err := os.Open(configPath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read git config")
+	}
+	defer configFile.Close()
+
+	inSection := false
+	scanner := bufio.NewScanner(configFile)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inSection = strings.TrimSpace(line[1:len(line)-1]) == section
+			continue
+		}
+
+		if !inSection {
+			continue
+		}
+
+		k, v, found := strings.Cut(line, "=")
+		if found && strings.EqualFold(strings.TrimSpace(k), key) {
+			return strings.Trim(strings.TrimSpace(v), `"`), nil
+		}
+	}
+
+	return "", scanner.Err()
+}
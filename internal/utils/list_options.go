@@ -0,0 +1,334 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListOptions control the order, pagination and columns of list output.
+type ListOptions struct {
+	SortBy         string // JSON field name to sort by. Empty keeps the API order
+	SortDescending bool
+	Offset         int
+	Limit          int      // 0 means no limit
+	Columns        []string // JSON field names to print, in order. Empty prints the fields declared by the model
+	NoHeaders      bool
+}
+
+// ParseSortBy parses a sort specification of the form field[,asc|desc].
+func ParseSortBy(sortBy string, supportedKeys []string) (field string, descending bool, err error) {
+	if sortBy == "" {
+		return
+	}
+
+	field, direction, _ := strings.Cut(sortBy, ",")
+	field = strings.TrimSpace(field)
+	if !slices.Contains(supportedKeys, field) {
+		err = fmt.Errorf("unsupported sort key: %s. Supported keys: %s", field, strings.Join(supportedKeys, ", "))
+		return
+	}
+
+	switch strings.ToLower(strings.TrimSpace(direction)) {
+	case "", "asc":
+	case "desc":
+		descending = true
+	default:
+		err = fmt.Errorf("invalid sort direction: %s, expected asc or desc", direction)
+	}
+	return
+}
+
+// ParseColumns parses a comma-separated list of columns, validating them against supportedKeys.
+func ParseColumns(columns string, supportedKeys []string) ([]string, error) {
+	if strings.TrimSpace(columns) == "" {
+		return nil, nil
+	}
+
+	var parsed []string
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		if !slices.Contains(supportedKeys, column) {
+			return nil, fmt.Errorf("unsupported column: %s. Supported columns: %s", column, strings.Join(supportedKeys, ", "))
+		}
+		parsed = append(parsed, column)
+	}
+
+	return parsed, nil
+}
+
+// SortAndPaginate returns the objects sorted by opts.SortBy and limited to the page selected by opts.Offset and opts.Limit.
+// Sorting is stable, so objects with equal values keep their API order.
+func SortAndPaginate[T any](objects []T, opts ListOptions) ([]T, error) {
+	if opts.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative, got %d", opts.Offset)
+	}
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("limit must not be negative, got %d", opts.Limit)
+	}
+
+	sorted := slices.Clone(objects)
+	if opts.SortBy != "" {
+		values := make([]string, len(sorted))
+		for i, obj := range sorted {
+			fields, err := filterFieldValues(obj)
+			if err != nil {
+				return nil, err
+			}
+			value, found := fields[opts.SortBy]
+			if !found {
+				return nil, fmt.Errorf("invalid JSON field name: %s", opts.SortBy)
+			}
+			// Nil pointers sort as empty values, last
+			if value != nil {
+				values[i] = *value
+			}
+		}
+
+		indices := make([]int, len(sorted))
+		for i := range indices {
+			indices[i] = i
+		}
+		compare := listValueComparison(opts.SortBy, values)
+		sort.SliceStable(indices, func(i, j int) bool {
+			return lessListValue(compare, values[indices[i]], values[indices[j]], opts.SortDescending)
+		})
+
+		reordered := make([]T, len(sorted))
+		for i, index := range indices {
+			reordered[i] = sorted[index]
+		}
+		sorted = reordered
+	}
+
+	start := min(opts.Offset, len(sorted))
+	end := len(sorted)
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, end)
+	}
+
+	return sorted[start:end], nil
+}
+
+// listValueComparison returns how to compare the values of a column, chosen once for all of them so that the order
+// is consistent: versions by their numeric parts for version fields, numbers numerically and RFC 3339 timestamps
+// chronologically when all the values are, and everything else case-insensitively. Empty values are ignored.
+func listValueComparison(field string, values []string) func(a, b string) int {
+	allValues := func(valid func(string) bool) bool {
+		for _, value := range values {
+			if value != "" && !valid(value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case isVersionField(field) && allValues(isVersion):
+		return compareVersions
+	case allValues(isNumber):
+		return func(a, b string) int {
+			af, _ := strconv.ParseFloat(a, 64)
+			bf, _ := strconv.ParseFloat(b, 64)
+			return compareOrdered(af, bf)
+		}
+	case allValues(isTimestamp):
+		return func(a, b string) int {
+			at, _ := time.Parse(time.RFC3339, a)
+			bt, _ := time.Parse(time.RFC3339, b)
+			return at.Compare(bt)
+		}
+	}
+	return func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+}
+
+// lessListValue orders two values of a column with its comparison. Empty values always come last.
+func lessListValue(compare func(a, b string) int, a, b string, descending bool) bool {
+	if a == "" || b == "" {
+		return a != "" && b == ""
+	}
+	return applySortDirection(compare(a, b), descending)
+}
+
+func isVersionField(field string) bool {
+	return field == "version" || strings.HasSuffix(field, "_version")
+}
+
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+func isTimestamp(value string) bool {
+	_, err := time.Parse(time.RFC3339, value)
+	return err == nil
+}
+
+// isVersion returns whether the value is a version of dot-separated numbers, with an optional v prefix and
+// -pre-release or +build suffix, e.g. 1.10, v2.0.3 or 1.2.0-rc.1.
+func isVersion(value string) bool {
+	core, _ := splitVersion(value)
+	for _, part := range strings.Split(core, ".") {
+		if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares versions by their numeric parts, missing parts counting as 0, then orders a pre-release
+// before its release, as in semantic versioning.
+func compareVersions(a, b string) int {
+	aCore, aPreRelease := splitVersion(a)
+	bCore, bPreRelease := splitVersion(b)
+
+	aParts, bParts := strings.Split(aCore, "."), strings.Split(bCore, ".")
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		var aPart, bPart uint64
+		if i < len(aParts) {
+			aPart, _ = strconv.ParseUint(aParts[i], 10, 64)
+		}
+		if i < len(bParts) {
+			bPart, _ = strconv.ParseUint(bParts[i], 10, 64)
+		}
+		if aPart != bPart {
+			if aPart < bPart {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPreRelease == bPreRelease:
+		return 0
+	case aPreRelease == "":
+		return 1
+	case bPreRelease == "":
+		return -1
+	}
+	return strings.Compare(aPreRelease, bPreRelease)
+}
+
+// splitVersion splits a version into its dot-separated core, without v prefix, and its pre-release, without build
+// metadata.
+func splitVersion(version string) (core, preRelease string) {
+	version, _, _ = strings.Cut(strings.TrimPrefix(version, "v"), "+")
+	core, preRelease, _ = strings.Cut(version, "-")
+	return
+}
+
+func compareOrdered(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func applySortDirection(cmp int, descending bool) bool {
+	if descending {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// jsonFieldNames returns the JSON field names of a struct type in declaration order.
+func jsonFieldNames(t reflect.Type) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// outputColumns returns the columns to print for the first JSON row: opts.Columns if set, otherwise the fields
+// of T present in the row in declaration order. Nil means the row's keys sorted alphabetically.
+func outputColumns[T any](firstRow string, columns []string) ([]string, error) {
+	if len(columns) > 0 {
+		return columns, nil
+	}
+
+	declared := jsonFieldNames(reflect.TypeOf((*T)(nil)).Elem())
+	if len(declared) == 0 {
+		return nil, nil
+	}
+
+	var row map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(firstRow), &row); err != nil {
+		return nil, err
+	}
+
+	present := make([]string, 0, len(row))
+	for _, name := range declared {
+		if _, ok := row[name]; ok {
+			present = append(present, name)
+		}
+	}
+
+	return present, nil
+}
+
+// selectJSONColumns rewrites a JSON object to contain only the given keys, in the given order.
+func selectJSONColumns(data []byte, columns []string) ([]byte, error) {
+	var row map[string]json.RawMessage
+	if err := json.Unmarshal(data, &row); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, column := range columns {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		if value, ok := row[column]; ok {
+			buf.Write(value)
+		} else {
+			buf.WriteString("null")
+		}
+	}
+	buf.WriteString("}")
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", "    "); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
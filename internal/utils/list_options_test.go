@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/omnistrate-oss/omnistrate-ctl/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSortBy(t *testing.T) {
+	require := require.New(t)
+
+	supportedKeys := []string{"status", "version"}
+
+	field, descending, err := ParseSortBy("status", supportedKeys)
+	require.NoError(err)
+	require.Equal("status", field)
+	require.False(descending)
+
+	field, descending, err = ParseSortBy("version,DESC", supportedKeys)
+	require.NoError(err)
+	require.Equal("version", field)
+	require.True(descending)
+
+	_, _, err = ParseSortBy("plan", supportedKeys)
+	require.ErrorContains(err, "unsupported sort key: plan")
+
+	_, _, err = ParseSortBy("status,down", supportedKeys)
+	require.ErrorContains(err, "invalid sort direction: down")
+}
+
+func TestParseColumns(t *testing.T) {
+	require := require.New(t)
+
+	columns, err := ParseColumns("status, version", []string{"status", "version"})
+	require.NoError(err)
+	require.Equal([]string{"status", "version"}, columns)
+
+	columns, err = ParseColumns("", []string{"status"})
+	require.NoError(err)
+	require.Nil(columns)
+
+	_, err = ParseColumns("status,plan", []string{"status"})
+	require.ErrorContains(err, "unsupported column: plan")
+}
+
+func TestSortAndPaginate(t *testing.T) {
+	require := require.New(t)
+
+	instances := []model.Instance{
+		{InstanceID: "i1", Version: "10", Status: "RUNNING"},
+		{InstanceID: "i2", Version: "9", Status: "failed"},
+		{InstanceID: "i3", Version: "", Status: "FAILED"},
+		{InstanceID: "i4", Version: "2", Status: "deploying"},
+	}
+
+	instanceIDs := func(instances []model.Instance) (ids []string) {
+		for _, instance := range instances {
+			ids = append(ids, instance.InstanceID)
+		}
+		return
+	}
+
+	tests := []struct {
+		name     string
+		opts     ListOptions
+		expected []string
+	}{
+		{name: "Keeps API order", opts: ListOptions{}, expected: []string{"i1", "i2", "i3", "i4"}},
+		{name: "Numbers are compared by value, empty values last", opts: ListOptions{SortBy: "version"}, expected: []string{"i4", "i2", "i1", "i3"}},
+		{name: "Descending, empty values last", opts: ListOptions{SortBy: "version", SortDescending: true}, expected: []string{"i1", "i2", "i4", "i3"}},
+		{name: "Strings are compared case-insensitively and stably", opts: ListOptions{SortBy: "status"}, expected: []string{"i4", "i2", "i3", "i1"}},
+		{name: "Limit", opts: ListOptions{SortBy: "version", Limit: 2}, expected: []string{"i4", "i2"}},
+		{name: "Offset and limit", opts: ListOptions{SortBy: "version", Offset: 1, Limit: 2}, expected: []string{"i2", "i1"}},
+		{name: "Offset past the end", opts: ListOptions{Offset: 10}, expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sorted, err := SortAndPaginate(instances, test.opts)
+			require.NoError(err)
+			require.Equal(test.expected, instanceIDs(sorted))
+		})
+	}
+
+	_, err := SortAndPaginate(instances, ListOptions{Limit: -1})
+	require.Error(err)
+}
+
+func TestListValueComparison(t *testing.T) {
+	require := require.New(t)
+
+	sortValues := func(field string, values ...string) []string {
+		type row struct {
+			Version   string `json:"version"`
+			Status    string `json:"status"`
+			CreatedAt string `json:"created_at"`
+		}
+		rows := make([]row, 0, len(values))
+		for _, value := range values {
+			rows = append(rows, row{Version: value, Status: value, CreatedAt: value})
+		}
+		sorted, err := SortAndPaginate(rows, ListOptions{SortBy: field})
+		require.NoError(err)
+		result := make([]string, 0, len(sorted))
+		for _, r := range sorted {
+			result = append(result, r.Version)
+		}
+		return result
+	}
+
+	require.Equal([]string{"1.2", "1.9", "1.10", "2.0"}, sortValues("version", "1.10", "2.0", "1.9", "1.2"))
+	require.Equal([]string{"1.2.3", "1.10.0", "v1.10.1", "2"}, sortValues("version", "1.10.0", "2", "1.2.3", "v1.10.1"))
+	require.Equal([]string{"1.0.0-alpha", "1.0.0-rc.1", "1.0", "1.0.1"}, sortValues("version", "1.0.1", "1.0", "1.0.0-rc.1", "1.0.0-alpha"))
+	require.Equal([]string{"1.10", "1.9", "latest"}, sortValues("version", "latest", "1.9", "1.10"))
+
+	require.Equal([]string{"2", "10", "1.5e3"}, sortValues("status", "10", "1.5e3", "2"))
+	require.Equal([]string{"10", "9", "a"}, sortValues("status", "a", "9", "10"))
+
+	require.Equal([]string{"2024-01-02T10:00:00Z", "2024-01-02T11:00:00+00:00", "2024-01-02T13:00:00+01:00"},
+		sortValues("created_at", "2024-01-02T13:00:00+01:00", "2024-01-02T10:00:00Z", "2024-01-02T11:00:00+00:00"))
+}
+
+func TestOutputColumns(t *testing.T) {
+	require := require.New(t)
+
+	columns, err := outputColumns[model.ServicePlan](`{"tenancy_type":"t","plan_id":"p","service_name":"s"}`, nil)
+	require.NoError(err)
+	require.Equal([]string{"plan_id", "service_name", "tenancy_type"}, columns)
+
+	columns, err = outputColumns[model.ServicePlan](`{"plan_id":"p"}`, []string{"service_name", "plan_id"})
+	require.NoError(err)
+	require.Equal([]string{"service_name", "plan_id"}, columns)
+
+	columns, err = outputColumns[map[string]any](`{"b":1,"a":2}`, nil)
+	require.NoError(err)
+	require.Nil(columns)
+}
+
+func TestSelectJSONColumns(t *testing.T) {
+	require := require.New(t)
+
+	data, err := selectJSONColumns([]byte(`{"a":1,"b":"x","c":true}`), []string{"c", "a", "d"})
+	require.NoError(err)
+	require.Equal("{\n    \"c\": true,\n    \"a\": 1,\n    \"d\": null\n}", string(data))
+}
@@ -57,45 +57,62 @@ func PrintJSON(res interface{}) {
 }
 
 func PrintTextTableJsonArrayOutput[T any](output string, objects []T) error {
-	switch output {
-	case "text":
-		dataArray := make([]string, 0)
-		for _, obj := range objects {
-			data, err := json.MarshalIndent(obj, "", "    ")
-			if err != nil {
+	return PrintListOutput(output, objects, ListOptions{})
+}
+
+// PrintListOutput prints the objects in the output format. The text and table columns follow the order in which
+// the JSON fields of T are declared, unless opts.Columns selects the columns to print. Sorting and pagination
+// must be applied beforehand with SortAndPaginate.
+func PrintListOutput[T any](output string, objects []T, opts ListOptions) error {
+	dataArray := make([]string, 0)
+	for _, obj := range objects {
+		data, err := json.MarshalIndent(obj, "", "    ")
+		if err != nil {
+			return err
+		}
+		if len(opts.Columns) > 0 {
+			if data, err = selectJSONColumns(data, opts.Columns); err != nil {
 				return err
 			}
-			dataArray = append(dataArray, string(data))
 		}
-		err := PrintText(dataArray)
+		dataArray = append(dataArray, string(data))
+	}
+
+	var columns []string
+	if len(dataArray) > 0 && output != "json" {
+		var err error
+		if columns, err = outputColumns[T](dataArray[0], opts.Columns); err != nil {
+			return err
+		}
+	}
+
+	switch output {
+	case "text":
+		err := printText(dataArray, columns, opts.NoHeaders)
 		if err == nil {
 			LastPrintedString = fmt.Sprintf("%v", dataArray)
 		}
 		return err
 	case "table":
-		dataArray := make([]string, 0)
-		for _, obj := range objects {
-			data, err := json.MarshalIndent(obj, "", "    ")
-			if err != nil {
-				return err
-			}
-			dataArray = append(dataArray, string(data))
-		}
-		err := PrintTable(dataArray)
+		err := printTable(dataArray, columns, opts.NoHeaders)
 		if err == nil {
 			LastPrintedString = fmt.Sprintf("%v", dataArray)
 		}
 		return err
 	case "json":
-		data, err := json.MarshalIndent(objects, "", "    ")
-		if err != nil {
-			return err
-		}
 		if len(objects) == 0 {
 			formatted := "[]"
 			fmt.Println(formatted)
 			LastPrintedString = formatted
 		} else {
+			rows := make([]json.RawMessage, 0, len(dataArray))
+			for _, data := range dataArray {
+				rows = append(rows, json.RawMessage(data))
+			}
+			data, err := json.MarshalIndent(rows, "", "    ")
+			if err != nil {
+				return err
+			}
 			formatted := string(data)
 			fmt.Printf("%s\n", formatted)
 			LastPrintedString = formatted
@@ -114,13 +131,21 @@ func PrintTextTableJsonOutput[T any](output string, object T) error {
 
 	switch output {
 	case "text":
-		err := PrintText([]string{string(data)})
+		columns, err := outputColumns[T](string(data), nil)
+		if err != nil {
+			return err
+		}
+		err = printText([]string{string(data)}, columns, false)
 		if err == nil {
 			LastPrintedString = string(data)
 		}
 		return err
 	case "table":
-		err := PrintTable([]string{string(data)})
+		columns, err := outputColumns[T](string(data), nil)
+		if err != nil {
+			return err
+		}
+		err = printTable([]string{string(data)}, columns, false)
 		if err == nil {
 			LastPrintedString = string(data)
 		}
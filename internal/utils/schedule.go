@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ScheduledDateSyntax describes the inputs accepted by ParseScheduledDate, for flag help texts.
+const ScheduledDateSyntax = `RFC3339 (e.g. 2025-12-01T02:00:00Z), a local date and time (e.g. "2025-12-01 02:00"), "now", "in 2h", "in 3d", "today 22:00", "tomorrow 02:00", "sunday 02:00" or "next sunday 02:00"`
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// ParseWeekday parses a weekday name, in full or as its first three letters, in any case.
+func ParseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for name, weekday := range weekdays {
+		if s == name || (len(s) == 3 && strings.HasPrefix(name, s)) {
+			return weekday, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", s)
+}
+
+// ParseClock parses a time of day in the 24-hour HH:MM format.
+func ParseClock(s string) (hour, minute int, err error) {
+	hourPart, minutePart, found := strings.Cut(strings.TrimSpace(s), ":")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	hour, err = strconv.Atoi(hourPart)
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	minute, err = strconv.Atoi(minutePart)
+	if err != nil || minute < 0 || minute > 59 || len(minutePart) != 2 {
+		return 0, 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return hour, minute, nil
+}
+
+// ParseScheduledDateFlag parses a --scheduled-date flag in the --timezone time zone, the local one if empty, and returns
+// it in RFC3339 with the parsed time. An empty flag returns nil. Dates in the past are rejected.
+func ParseScheduledDateFlag(scheduledDate, timezone string, now time.Time) (*string, time.Time, error) {
+	if scheduledDate == "" {
+		return nil, time.Time{}, nil
+	}
+
+	loc := time.Local
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, time.Time{}, fmt.Errorf("unknown time zone %s", timezone)
+		}
+	}
+
+	t, err := ParseScheduledDate(scheduledDate, loc, now)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if t.Before(now.Add(-time.Minute)) {
+		return nil, time.Time{}, fmt.Errorf("scheduled date %s is in the past", t.Format(time.RFC3339))
+	}
+
+	formatted := t.UTC().Format(time.RFC3339)
+	return &formatted, t, nil
+}
+
+// ParseScheduledDate parses an absolute or relative date, as described by ScheduledDateSyntax. Dates without a time
+// zone are in loc, and relative dates are relative to now.
+func ParseScheduledDate(input string, loc *time.Location, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, errors.New("scheduled date is empty")
+	}
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, input, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	now = now.In(loc)
+	fields := strings.Fields(strings.ToLower(input))
+	if fields[0] == "now" && len(fields) == 1 {
+		return now, nil
+	}
+	if fields[0] == "in" {
+		if len(fields) != 2 {
+			return time.Time{}, fmt.Errorf("invalid scheduled date %q, expected e.g. \"in 2h\"", input)
+		}
+		duration, err := parseRelativeDuration(fields[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid scheduled date %q: %w", input, err)
+		}
+		return now.Add(duration), nil
+	}
+
+	// Day, optionally followed by [at] HH:MM
+	var day time.Time
+	bareWeekday := false
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	rest := fields[1:]
+	switch fields[0] {
+	case "today":
+		day = midnight
+	case "tomorrow":
+		day = midnight.AddDate(0, 0, 1)
+	case "next":
+		if len(fields) < 2 {
+			return time.Time{}, fmt.Errorf("invalid scheduled date %q, expected e.g. \"next sunday 02:00\"", input)
+		}
+		weekday, err := ParseWeekday(fields[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid scheduled date %q: %w", input, err)
+		}
+		days := (int(weekday) - int(now.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		day = midnight.AddDate(0, 0, days)
+		rest = fields[2:]
+	default:
+		weekday, err := ParseWeekday(fields[0])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid scheduled date %q. Supported formats: %s", input, ScheduledDateSyntax)
+		}
+		day = midnight.AddDate(0, 0, (int(weekday)-int(now.Weekday())+7)%7)
+		bareWeekday = true
+	}
+
+	if len(rest) > 0 && rest[0] == "at" {
+		rest = rest[1:]
+	}
+	hour, minute := 0, 0
+	switch len(rest) {
+	case 0:
+	case 1:
+		var err error
+		if hour, minute, err = ParseClock(rest[0]); err != nil {
+			return time.Time{}, fmt.Errorf("invalid scheduled date %q: %w", input, err)
+		}
+	default:
+		return time.Time{}, fmt.Errorf("invalid scheduled date %q. Supported formats: %s", input, ScheduledDateSyntax)
+	}
+
+	t := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+	if bareWeekday && t.Before(now) {
+		// A weekday whose time already passed today is the same weekday next week
+		t = t.AddDate(0, 0, 7)
+	}
+	return t, nil
+}
+
+// parseRelativeDuration parses a Go duration, or a number of days such as 3d.
+func parseRelativeDuration(s string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(s, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return duration, nil
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScheduledDate(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Wednesday
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, berlin)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{name: "RFC3339", input: "2025-02-01T02:00:00Z", expected: time.Date(2025, 2, 1, 2, 0, 0, 0, time.UTC)},
+		{name: "local date and time", input: "2025-02-01 02:00", expected: time.Date(2025, 2, 1, 2, 0, 0, 0, berlin)},
+		{name: "local date", input: "2025-02-01", expected: time.Date(2025, 2, 1, 0, 0, 0, 0, berlin)},
+		{name: "now", input: "now", expected: now},
+		{name: "in hours", input: "in 2h", expected: now.Add(2 * time.Hour)},
+		{name: "in days", input: "in 3d", expected: now.Add(72 * time.Hour)},
+		{name: "today", input: "today 22:00", expected: time.Date(2025, 1, 15, 22, 0, 0, 0, berlin)},
+		{name: "tomorrow with at", input: "Tomorrow at 02:00", expected: time.Date(2025, 1, 16, 2, 0, 0, 0, berlin)},
+		{name: "weekday", input: "sunday 02:00", expected: time.Date(2025, 1, 19, 2, 0, 0, 0, berlin)},
+		{name: "abbreviated weekday", input: "sun 02:00", expected: time.Date(2025, 1, 19, 2, 0, 0, 0, berlin)},
+		{name: "same weekday later today", input: "wednesday 12:00", expected: time.Date(2025, 1, 15, 12, 0, 0, 0, berlin)},
+		{name: "same weekday already passed", input: "wednesday 08:00", expected: time.Date(2025, 1, 22, 8, 0, 0, 0, berlin)},
+		{name: "next weekday", input: "next sunday 02:00", expected: time.Date(2025, 1, 19, 2, 0, 0, 0, berlin)},
+		{name: "next same weekday", input: "next wednesday 12:00", expected: time.Date(2025, 1, 22, 12, 0, 0, 0, berlin)},
+		{name: "weekday without time", input: "friday", expected: time.Date(2025, 1, 17, 0, 0, 0, 0, berlin)},
+		{name: "empty", input: "", wantErr: true},
+		{name: "unknown word", input: "someday", wantErr: true},
+		{name: "invalid time", input: "tomorrow 25:00", wantErr: true},
+		{name: "invalid duration", input: "in soon", wantErr: true},
+		{name: "next without weekday", input: "next", wantErr: true},
+		{name: "trailing words", input: "sunday 02:00 please", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseScheduledDate(tt.input, berlin, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.expected.Equal(got), "expected %s, got %s", tt.expected, got)
+		})
+	}
+}
+
+func TestParseScheduledDateFlag(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	date, _, err := ParseScheduledDateFlag("", "", now)
+	require.NoError(err)
+	require.Nil(date)
+
+	date, at, err := ParseScheduledDateFlag("next sunday 02:00", "Europe/Berlin", now)
+	require.NoError(err)
+	require.Equal("2025-01-19T01:00:00Z", *date)
+	require.Equal("2025-01-19T01:00:00Z", at.UTC().Format(time.RFC3339))
+
+	_, _, err = ParseScheduledDateFlag("2025-01-01T00:00:00Z", "", now)
+	require.ErrorContains(err, "in the past")
+
+	_, _, err = ParseScheduledDateFlag("tomorrow", "Mars/Olympus", now)
+	require.ErrorContains(err, "unknown time zone")
+}
+
+func TestParseClock(t *testing.T) {
+	t.Parallel()
+
+	hour, minute, err := ParseClock("02:05")
+	require.NoError(t, err)
+	require.Equal(t, 2, hour)
+	require.Equal(t, 5, minute)
+
+	for _, input := range []string{"2", "24:00", "02:60", "02:5", "ab:cd"} {
+		_, _, err = ParseClock(input)
+		require.Error(t, err, input)
+	}
+}
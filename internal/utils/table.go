@@ -14,46 +14,62 @@ type Table struct {
 	columns     []string
 }
 
+// NewTable creates a table with the columns in the given order.
 func NewTable(columns []any) (t *Table) {
-	t = &Table{
-		tableWriter: prettytable.NewWriter(),
-	}
-
 	columnsAsStrings := make([]string, 0, len(columns))
 
 	for _, column := range columns {
 		columnsAsStrings = append(columnsAsStrings, fmt.Sprintf("%v", column))
 	}
 
-	// Sort the columns
-	t.columns = columnsAsStrings
-	slices.Sort(t.columns)
-
-	// Convert back to any
-	var columnsAsAny []any
+	return newTable(columnsAsStrings, true)
+}
 
-	for _, column := range t.columns {
-		columnsAsAny = append(columnsAsAny, column)
+func newTable(columns []string, withHeader bool) (t *Table) {
+	t = &Table{
+		tableWriter: prettytable.NewWriter(),
+		columns:     columns,
 	}
 
-	t.tableWriter.AppendHeader(columnsAsAny)
+	if withHeader {
+		// Convert back to any
+		var columnsAsAny []any
+
+		for _, column := range t.columns {
+			columnsAsAny = append(columnsAsAny, column)
+		}
+
+		t.tableWriter.AppendHeader(columnsAsAny)
+	}
 
 	return
 }
 
+// NewTableFromJSONTemplate creates a table with the keys of the JSON object as columns, sorted alphabetically.
 func NewTableFromJSONTemplate(data json.RawMessage) (t *Table, err error) {
+	columns, err := jsonTemplateColumns(data)
+	if err != nil {
+		return
+	}
+
+	t = newTable(columns, true)
+	return
+}
+
+func jsonTemplateColumns(data json.RawMessage) (columns []string, err error) {
 	var mappedData map[string]any
 	if err = json.Unmarshal(data, &mappedData); err != nil {
 		return
 	}
 
-	columns := make([]any, 0, len(mappedData))
+	columns = make([]string, 0, len(mappedData))
 
 	for k := range mappedData {
 		columns = append(columns, k)
 	}
 
-	t = NewTable(columns)
+	// Sort the columns
+	slices.Sort(columns)
 	return
 }
 
@@ -87,16 +103,23 @@ func (t Table) PrintToWriter(finalW io.Writer) {
 }
 
 func PrintTable(jsonData []string) (err error) {
+	return printTable(jsonData, nil, false)
+}
+
+// printTable prints the rows with the given columns, or the keys of the first row sorted alphabetically if columns is empty.
+func printTable(jsonData []string, columns []string, noHeaders bool) (err error) {
 	if len(jsonData) == 0 {
 		return
 	}
 
-	var tableWriter *Table
-	if tableWriter, err = NewTableFromJSONTemplate(json.RawMessage(jsonData[0])); err != nil {
-		// Just print the JSON directly and return
-		fmt.Printf("%+v\n", jsonData)
-		return err
+	if len(columns) == 0 {
+		if columns, err = jsonTemplateColumns(json.RawMessage(jsonData[0])); err != nil {
+			// Just print the JSON directly and return
+			fmt.Printf("%+v\n", jsonData)
+			return err
+		}
 	}
+	tableWriter := newTable(columns, !noHeaders)
 
 	for _, data := range jsonData {
 		if err = tableWriter.AddRowFromJSON(json.RawMessage(data)); err != nil {
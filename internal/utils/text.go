@@ -10,34 +10,42 @@ import (
 )
 
 func PrintText(data []string) (err error) {
+	return printText(data, nil, false)
+}
+
+// printText prints the rows with the given columns, or the keys of the first row sorted alphabetically if columns is empty.
+func printText(data []string, columnsAsStrings []string, noHeaders bool) (err error) {
 	if len(data) == 0 {
 		return
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.TabIndent)
 
-	// Unmarshal the first JSON data to get the columns
 	var mappedData map[string]any
-	if err = json.Unmarshal(json.RawMessage(data[0]), &mappedData); err != nil {
-		return
-	}
+	if len(columnsAsStrings) == 0 {
+		// Unmarshal the first JSON data to get the columns
+		if err = json.Unmarshal(json.RawMessage(data[0]), &mappedData); err != nil {
+			return
+		}
 
-	columns := make([]any, 0, len(mappedData))
-	for k := range mappedData {
-		columns = append(columns, k)
-	}
+		columns := make([]any, 0, len(mappedData))
+		for k := range mappedData {
+			columns = append(columns, k)
+		}
 
-	// Sort the columns
-	columnsAsStrings := make([]string, 0, len(columns))
-	for _, column := range columns {
-		columnsAsStrings = append(columnsAsStrings, fmt.Sprintf("%v", column))
+		// Sort the columns
+		for _, column := range columns {
+			columnsAsStrings = append(columnsAsStrings, fmt.Sprintf("%v", column))
+		}
+		slices.Sort(columnsAsStrings)
 	}
-	slices.Sort(columnsAsStrings)
 
 	// Print the header
-	_, err = fmt.Fprintln(w, strings.Join(columnsAsStrings, "\t"))
-	if err != nil {
-		return
+	if !noHeaders {
+		_, err = fmt.Fprintln(w, strings.Join(columnsAsStrings, "\t"))
+		if err != nil {
+			return
+		}
 	}
 
 	// Print the data